@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// seedDemoData populates an empty store with a small board so the API is
+// usable straight away when running with STORAGE=memory.
+func seedDemoData(ctx context.Context, store database.Store) error {
+	users := []models.User{
+		{ID: uuid.New().String(), Name: "Alice", AvatarURL: "https://api.dicebear.com/7.x/avataaars/svg?seed=Alice"},
+		{ID: uuid.New().String(), Name: "Bob", AvatarURL: "https://api.dicebear.com/7.x/avataaars/svg?seed=Bob"},
+		{ID: uuid.New().String(), Name: "Charlie", AvatarURL: "https://api.dicebear.com/7.x/avataaars/svg?seed=Charlie"},
+	}
+	for _, u := range users {
+		if err := store.CreateUser(ctx, u); err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", u.Name, err)
+		}
+	}
+
+	labels := []models.Label{
+		{ID: uuid.New().String(), Name: "Bug", Color: "#ef4444"},
+		{ID: uuid.New().String(), Name: "Feature", Color: "#3b82f6"},
+		{ID: uuid.New().String(), Name: "Enhancement", Color: "#10b981"},
+		{ID: uuid.New().String(), Name: "Documentation", Color: "#f59e0b"},
+	}
+	for _, l := range labels {
+		if err := store.CreateLabel(ctx, l); err != nil {
+			return fmt.Errorf("failed to seed label %s: %w", l.Name, err)
+		}
+	}
+
+	issues := []struct {
+		Title       string
+		Description string
+		Status      string
+		Priority    string
+	}{
+		{"Setup CI/CD pipeline", "Configure automated testing and deployment workflows", "Backlog", "Medium"},
+		{"Implement dark mode", "Add dark theme support across the application", "Backlog", "Low"},
+		{"Create user profile page", "Design and implement user profile with settings", "Todo", "Medium"},
+		{"Add search functionality", "Implement full-text search for issues", "Todo", "High"},
+		{"Implement user authentication", "Add login and registration functionality", "In Progress", "Critical"},
+		{"Add file upload feature", "Allow users to attach files to issues", "In Progress", "Medium"},
+		{"Deploy to production", "Configure CI/CD pipeline and deploy to production server", "Done", "Critical"},
+		{"Legacy API support", "Support for old API version - no longer required", "Canceled", "Low"},
+	}
+
+	now := time.Now()
+	for i, d := range issues {
+		assigneeID := users[i%len(users)].ID
+		issue := models.Issue{
+			ID:          uuid.New().String(),
+			Title:       d.Title,
+			Description: d.Description,
+			Status:      d.Status,
+			Priority:    d.Priority,
+			AssigneeID:  &assigneeID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			OrderIndex:  float64(i),
+		}
+		if err := store.CreateIssue(ctx, issue); err != nil {
+			return fmt.Errorf("failed to seed issue %q: %w", d.Title, err)
+		}
+		if err := store.UpdateIssueLabels(ctx, issue.ID, []string{labels[i%len(labels)].ID}); err != nil {
+			return fmt.Errorf("failed to seed labels for %q: %w", d.Title, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/config"
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// startJobs starts the background maintenance jobs, which stop when ctx is
+// done
+func startJobs(ctx context.Context, cfg *config.Config, store database.Store) {
+	if cfg.Trash.Retention > 0 {
+		go every(ctx, cfg.Trash.PurgeInterval, func(ctx context.Context) {
+			purgeTrash(ctx, store, time.Now().Add(-cfg.Trash.Retention))
+		})
+		slog.Info("Trash purge started", "retention", cfg.Trash.Retention.String(), "interval", cfg.Trash.PurgeInterval.String())
+	}
+	if cfg.Archive.After > 0 {
+		go every(ctx, cfg.Archive.Interval, func(ctx context.Context) {
+			archiveClosed(ctx, store, time.Now().Add(-cfg.Archive.After))
+		})
+		slog.Info("Auto-archive started", "after", cfg.Archive.After.String(), "interval", cfg.Archive.Interval.String())
+	}
+}
+
+// every runs job immediately and then every interval until ctx is done
+func every(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTrash permanently deletes the issues trashed before cutoff
+func purgeTrash(ctx context.Context, store database.Store, cutoff time.Time) {
+	n, err := store.PurgeTrash(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to purge trash", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Info("Purged trash", "issues", n, "deleted_before", cutoff)
+	}
+}
+
+// archiveClosed archives the issues closed before cutoff
+func archiveClosed(ctx context.Context, store database.Store, cutoff time.Time) {
+	n, err := store.ArchiveStale(ctx, models.ClosedStatuses, cutoff)
+	if err != nil {
+		slog.Error("Failed to archive closed issues", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Info("Archived closed issues", "issues", n, "updated_before", cutoff)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/config"
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestPurgeTrash(t *testing.T) {
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"old", "live"} {
+		issue := models.Issue{ID: id, Title: id, Status: "Todo", Priority: "Low", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		if err := store.CreateIssue(ctx, issue); err != nil {
+			t.Fatalf("CreateIssue() failed: %v", err)
+		}
+	}
+	store.DeleteIssue(ctx, "old")
+
+	purgeTrash(ctx, store, time.Now().Add(time.Minute))
+
+	if trash, _ := store.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Trashed: true}}); len(trash.Items) != 0 {
+		t.Errorf("Expected the trash to be purged, got %d issues", len(trash.Items))
+	}
+	if issue, _ := store.GetIssue(ctx, "live"); issue == nil {
+		t.Error("Expected live issues to be kept")
+	}
+}
+
+func TestArchiveClosed(t *testing.T) {
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	ctx := context.Background()
+	old := time.Now().Add(-time.Hour)
+	for id, status := range map[string]string{"done": "Done", "canceled": "Canceled", "open": "In Progress"} {
+		issue := models.Issue{ID: id, Title: id, Status: status, Priority: "Low", CreatedAt: old, UpdatedAt: old}
+		if err := store.CreateIssue(ctx, issue); err != nil {
+			t.Fatalf("CreateIssue() failed: %v", err)
+		}
+	}
+
+	archiveClosed(ctx, store, time.Now())
+
+	list, _ := store.ListIssues(ctx, models.IssueQuery{})
+	if len(list.Items) != 1 || list.Items[0].ID != "open" {
+		t.Errorf("Expected only the open issue left on the board, got %+v", list.Items)
+	}
+	for _, id := range []string{"done", "canceled"} {
+		if issue, _ := store.GetIssue(ctx, id); issue == nil || issue.ArchivedAt == nil {
+			t.Errorf("Expected %s to be archived, got %+v", id, issue)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		every(ctx, time.Millisecond, func(context.Context) { runs <- struct{}{} })
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected run %d", i+1)
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected every to stop when the context is done")
+	}
+}
@@ -11,10 +11,13 @@ import (
 	"time"
 
 	_ "github.com/abhir9/issue-board/api/docs"
+	"github.com/abhir9/issue-board/api/internal/auth"
 	"github.com/abhir9/issue-board/api/internal/config"
 	"github.com/abhir9/issue-board/api/internal/database"
 	"github.com/abhir9/issue-board/api/internal/handlers"
 	customMiddleware "github.com/abhir9/issue-board/api/internal/middleware"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -38,6 +41,10 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-API-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token: "Bearer <token>"
 func main() {
 	// Setup structured logging
 	logger := setupLogger()
@@ -52,15 +59,45 @@ func main() {
 
 	slog.Info("Starting Issue Board API", "version", "1.0.0")
 
-	// Setup database
-	if err := setupDatabase(cfg); err != nil {
-		slog.Error("Failed to setup database", "error", err)
+	// Setup database (the memory store needs none)
+	if cfg.Database.Driver != database.DriverMemory {
+		if err := setupDatabase(cfg); err != nil {
+			slog.Error("Failed to setup database", "error", err)
+			os.Exit(1)
+		}
+		defer database.DB.Close()
+	}
+
+	// Setup storage backend
+	store, err := setupStore(cfg)
+	if err != nil {
+		slog.Error("Failed to setup store", "error", err)
 		os.Exit(1)
 	}
-	defer database.DB.Close()
+
+	if cfg.Database.Driver == database.DriverMemory {
+		slog.Warn("Using in-memory storage: data is not persisted across restarts")
+		if err := seedDemoData(context.Background(), store); err != nil {
+			slog.Error("Failed to seed demo data", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Fail fast on an unreadable JWKS file rather than on the first request
+	if cfg.Auth.JWT.JWKSFile != "" {
+		if err := (&auth.FileKeySet{Path: cfg.Auth.JWT.JWKSFile}).Load(); err != nil {
+			slog.Error("Failed to load JWT keys", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Background jobs stop when main returns
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startJobs(ctx, cfg, store)
 
 	// Setup router
-	r := setupRouter(cfg)
+	r := setupRouter(cfg, store)
 
 	// Create and start server
 	server := setupServer(cfg, r)
@@ -98,8 +135,13 @@ func setupLogger() *slog.Logger {
 }
 
 func setupDatabase(cfg *config.Config) error {
+	driver := cfg.Database.Driver
+	if driver == "" {
+		driver = database.DriverSQLite
+	}
+
 	// Initialize database with connection pool settings
-	if err := database.InitDB(cfg.Database.Path); err != nil {
+	if err := database.Open(driver, cfg.Database.DSN()); err != nil {
 		return err
 	}
 
@@ -108,33 +150,47 @@ func setupDatabase(cfg *config.Config) error {
 	database.DB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	database.DB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
 
-	// Run migrations
-	return database.RunMigrations(cfg.Database.MigrationDir)
+	// Run dialect-specific migrations
+	return database.RunMigrations(cfg.Database.MigrationPath())
 }
 
-func setupRouter(cfg *config.Config) *chi.Mux {
-	// Setup repository and handlers
-	repo := database.NewRepository(database.DB)
-	h := handlers.NewHandler(repo)
+// setupStore returns the storage backend selected by configuration
+func setupStore(cfg *config.Config) (database.Store, error) {
+	return database.NewStore(cfg.Database.Driver, database.DB)
+}
+
+func setupRouter(cfg *config.Config, store database.Store) *chi.Mux {
+	// Setup handlers
+	h := handlers.NewHandler(store)
+	h.WIP = cfg.WIP
+	store.SetWIPLimits(cfg.WIP)
+	ah := setupAuthHandler(cfg, store)
 
 	// Setup router
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(customMiddleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
 
 	// CORS setup with improved configuration
 	c := cors.New(cors.Options{
 		AllowedOrigins:   cfg.Server.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-CSRF-Token", "Idempotency-Key", "If-None-Match", "If-Modified-Since"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "X-WIP-Warning", "X-Column-Counts", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "Idempotent-Replayed", "ETag", "X-Board-Version"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
 	r.Use(c.Handler)
+	if cfg.Server.MaxBodyBytes > 0 {
+		r.Use(customMiddleware.MaxBodySize(cfg.Server.MaxBodyBytes))
+	}
+
+	// Unmatched routes get problem responses like every other error
+	r.NotFound(utils.NotFound)
+	r.MethodNotAllowed(utils.MethodNotAllowed)
 
 	// Redirect /docs to /docs/index.html
 	r.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
@@ -154,7 +210,7 @@ func setupRouter(cfg *config.Config) *chi.Mux {
 		dbStatus := "healthy"
 		statusCode := http.StatusOK
 
-		if err := database.DB.PingContext(ctx); err != nil {
+		if err := store.Ping(ctx); err != nil {
 			dbStatus = "unhealthy"
 			status = "degraded"
 			statusCode = http.StatusServiceUnavailable
@@ -172,24 +228,148 @@ func setupRouter(cfg *config.Config) *chi.Mux {
 		}
 	})
 
-	// API routes with authentication
-	r.Route("/api", func(r chi.Router) {
-		r.Use(customMiddleware.APIKeyAuth(cfg.Auth.APIKey)) // Apply Auth middleware to /api routes
-
-		r.Get("/issues", h.GetIssues)
-		r.Post("/issues", h.CreateIssue)
-		r.Get("/issues/{id}", h.GetIssue)
-		r.Patch("/issues/{id}", h.UpdateIssue)
-		r.Patch("/issues/{id}/move", h.MoveIssue)
-		r.Delete("/issues/{id}", h.DeleteIssue)
+	// API routes with authentication; every route requires a permission
+	authz := &customMiddleware.Authorizer{Roles: store}
+	authn := customMiddleware.Authenticate(setupAuthenticators(cfg, store)...)
+
+	// Per-caller request budgets, keyed by user, API key or client IP once
+	// authenticated. The per-IP budgets are spent before authentication, so
+	// guessing credentials is limited however the guesses differ.
+	limiter := customMiddleware.NewRateLimiter(cfg.RateLimit.Read, cfg.RateLimit.Write, cfg.RateLimit.Window)
+	limiter.MaxKeys = cfg.RateLimit.MaxKeys
+	ipLimiter := customMiddleware.NewRateLimiter(cfg.RateLimit.IPRead, cfg.RateLimit.IPWrite, cfg.RateLimit.Window)
+	ipLimiter.MaxKeys = cfg.RateLimit.MaxKeys
+	ipLimiter.Key = customMiddleware.ClientIPKey
+
+	// Retried POSTs with an Idempotency-Key get the first response again
+	idempotency := customMiddleware.NewIdempotency(cfg.Idempotency.TTL)
+	idempotency.MaxKeys = cfg.Idempotency.MaxKeys
+
+	// Responses are not cached unless a route says otherwise. Board reads
+	// are revalidated with their ETag on every use; stats may be reused for
+	// a minute.
+	noStore := customMiddleware.CacheControl("no-store")
+	revalidate := customMiddleware.CacheControl("private, no-cache")
+	shortLived := customMiddleware.CacheControl("private, max-age=60")
+
+	// Mutations in a sync batch are served by the issue routes they stand
+	// for, so each is checked against the permission its route requires
+	mutations := chi.NewRouter()
+	mutations.With(authz.Require(models.PermIssuesWrite)).Post("/issues", h.CreateIssue)
+	mutations.With(authz.Require(models.PermIssuesWrite)).Patch("/issues/{id}", h.UpdateIssue)
+	mutations.With(authz.Require(models.PermIssuesWrite)).Patch("/issues/{id}/move", h.MoveIssue)
+	mutations.With(authz.Require(models.PermIssuesDelete)).Delete("/issues/{id}", h.DeleteIssue)
+	h.Mutations = mutations
+
+	// Login endpoints, which create the sessions the API routes accept
+	r.Route("/api/auth", func(r chi.Router) {
+		r.Use(limiter.Limit)
+		r.Use(idempotency.Handle)
+		r.Use(noStore)
+
+		r.Post("/login", ah.Login)
+		r.Post("/logout", ah.Logout)
+		r.Get("/session", ah.GetSession)
+		r.Get("/oidc/login", ah.OIDCLogin)
+		r.Get("/oidc/callback", ah.OIDCCallback)
+	})
 
-		r.Get("/users", h.GetUsers)
-		r.Get("/labels", h.GetLabels)
+	r.Route("/api", func(r chi.Router) {
+		r.Use(ipLimiter.Limit) // Before authentication, so failed credentials are limited too
+		r.Use(authn)           // Apply Auth middleware to /api routes: API key, bearer token or session cookie
+		r.Use(limiter.Limit)
+		r.Use(idempotency.Handle)
+		r.Use(noStore)
+
+		r.With(authz.Require(models.PermIssuesRead), revalidate).Get("/board/version", h.GetBoardVersion)
+		r.With(authz.Require(models.PermIssuesRead), revalidate).Get("/issues", h.GetIssues)
+		r.With(authz.Require(models.PermIssuesWrite)).Post("/issues", h.CreateIssue)
+		r.With(authz.Require(models.PermIssuesRead), revalidate).Get("/issues/{id}", h.GetIssue)
+		r.With(authz.Require(models.PermIssuesWrite)).Patch("/issues/{id}", h.UpdateIssue)
+		r.With(authz.Require(models.PermIssuesWrite)).Patch("/issues/{id}/move", h.MoveIssue)
+		r.With(authz.Require(models.PermIssuesWrite)).Post("/issues/{id}/archive", h.ArchiveIssue)
+		r.With(authz.Require(models.PermIssuesWrite)).Post("/issues/{id}/unarchive", h.UnarchiveIssue)
+		r.With(authz.Require(models.PermIssuesDelete)).Delete("/issues/{id}", h.DeleteIssue)
+		r.With(authz.Require(models.PermIssuesDelete)).Post("/issues/{id}/restore", h.RestoreIssue)
+		r.With(authz.Require(models.PermIssuesPurge)).Delete("/issues/{id}/permanent", h.PurgeIssue)
+		r.With(authz.Require(models.PermIssuesRead)).Get("/issues/{id}/comments", h.GetComments)
+		r.With(authz.Require(models.PermIssuesWrite)).Post("/issues/{id}/comments", h.CreateComment)
+		r.With(authz.Require(models.PermIssuesDelete)).Get("/trash", h.GetTrash)
+
+		r.With(authz.Require(models.PermIssuesRead), authz.Require(models.PermLabelsRead), authz.Require(models.PermUsersRead)).Get("/sync", h.GetSync)
+		r.With(authz.Require(models.PermIssuesWrite)).Post("/sync", h.PostSync)
+
+		r.With(authz.Require(models.PermUsersRead), revalidate).Get("/users", h.GetUsers)
+		r.With(authz.Require(models.PermUsersManage)).Post("/users", h.CreateUser)
+		r.With(authz.Require(models.PermRolesManage)).Put("/users/{id}/role", h.SetUserRole)
+		r.With(authz.Require(models.PermLabelsRead), revalidate).Get("/labels", h.GetLabels)
+
+		r.With(authz.Require(models.PermTemplatesRead)).Get("/templates", h.GetTemplates)
+		r.With(authz.Require(models.PermTemplatesManage)).Post("/templates", h.CreateTemplate)
+		r.With(authz.Require(models.PermTemplatesRead)).Get("/templates/{id}", h.GetTemplate)
+		r.With(authz.Require(models.PermTemplatesManage)).Put("/templates/{id}", h.UpdateTemplate)
+		r.With(authz.Require(models.PermTemplatesManage)).Delete("/templates/{id}", h.DeleteTemplate)
+
+		r.With(authz.Require(models.PermFieldsRead)).Get("/fields", h.GetFields)
+		r.With(authz.Require(models.PermFieldsManage)).Post("/fields", h.CreateField)
+		r.With(authz.Require(models.PermFieldsRead)).Get("/fields/{id}", h.GetField)
+		r.With(authz.Require(models.PermFieldsManage)).Patch("/fields/{id}", h.UpdateField)
+		r.With(authz.Require(models.PermFieldsManage)).Delete("/fields/{id}", h.DeleteField)
+		r.With(authz.Require(models.PermTypesRead)).Get("/issue-types", h.GetIssueTypes)
+		r.With(authz.Require(models.PermTypesRead)).Get("/issue-types/{key}", h.GetIssueType)
+		r.With(authz.Require(models.PermTypesManage)).Put("/issue-types/{key}", h.UpdateIssueType)
+
+		r.With(authz.Require(models.PermStatsRead), shortLived).Get("/stats", h.GetStats)
+		r.With(authz.Require(models.PermStatsRead), shortLived).Get("/stats/created-vs-closed", h.GetCreatedVsClosed)
+		r.With(authz.Require(models.PermStatsRead), shortLived).Get("/stats/cycle-time", h.GetFlowTimes)
+		r.With(authz.Require(models.PermStatsRead), shortLived).Get("/stats/throughput", h.GetThroughput)
+		r.With(authz.Require(models.PermStatsRead), shortLived).Get("/reports/cumulative-flow", h.GetCumulativeFlow)
 	})
 
 	return r
 }
 
+// setupAuthenticators returns the accepted kinds of credentials, in the order
+// they are tried
+func setupAuthenticators(cfg *config.Config, store database.Store) []customMiddleware.Authenticator {
+	authenticators := []customMiddleware.Authenticator{
+		customMiddleware.APIKeyAuthenticator{Key: cfg.Auth.APIKey, Role: cfg.Auth.APIKeyRole},
+	}
+	if jwt := cfg.Auth.JWT; jwt.Enabled() {
+		var keys auth.KeySet = auth.Secret(jwt.Secret)
+		if jwt.JWKSFile != "" {
+			keys = &auth.FileKeySet{Path: jwt.JWKSFile}
+		}
+		authenticators = append(authenticators, customMiddleware.JWTAuthenticator{
+			Verifier: &auth.Verifier{Keys: keys, Issuer: jwt.Issuer, Audience: jwt.Audience, Leeway: jwt.Leeway},
+			Role:     jwt.DefaultRole,
+		})
+	}
+	return append(authenticators, customMiddleware.SessionAuthenticator{Sessions: store, DefaultRole: cfg.Auth.Session.DefaultRole})
+}
+
+// setupAuthHandler configures session login and, if enabled, OIDC login
+func setupAuthHandler(cfg *config.Config, store database.Store) *handlers.AuthHandler {
+	ah := &handlers.AuthHandler{
+		Repo:         store,
+		SessionTTL:   cfg.Auth.Session.TTL,
+		SecureCookie: cfg.Auth.Session.SecureCookie,
+		SameSite:     cfg.Auth.Session.SameSite,
+	}
+	if oidc := cfg.Auth.OIDC; oidc.Enabled() {
+		ah.OIDC = auth.NewProvider(auth.OIDCConfig{
+			Issuer:       oidc.Issuer,
+			ClientID:     oidc.ClientID,
+			ClientSecret: oidc.ClientSecret,
+			RedirectURL:  oidc.RedirectURL,
+			Scopes:       oidc.Scopes,
+		}, &http.Client{Timeout: 10 * time.Second})
+		ah.OIDCIssuer = oidc.Issuer
+		ah.PostLoginURL = oidc.PostLoginURL
+	}
+	return ah
+}
+
 func setupServer(cfg *config.Config, handler http.Handler) *http.Server {
 	return &http.Server{
 		Addr:         cfg.Server.Host + ":" + cfg.Server.Port,
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/abhir9/issue-board/api/internal/config"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
 )
 
 func TestSetupLogger(t *testing.T) {
@@ -42,12 +49,201 @@ func TestSetupRouter(t *testing.T) {
 		},
 	}
 
-	router := setupRouter(cfg)
+	store, err := setupStore(cfg)
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+
+	router := setupRouter(cfg, store)
 	if router == nil {
 		t.Error("setupRouter() returned nil router")
 	}
 }
 
+func TestSetupRouterRateLimit(t *testing.T) {
+	cfg := &config.Config{
+		Auth:      config.AuthConfig{APIKey: "test-key", APIKeyRole: models.RoleAdmin},
+		RateLimit: config.RateLimitConfig{Read: 100, Write: 1, IPRead: 100, IPWrite: 3, Window: time.Minute},
+	}
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	router := setupRouter(cfg, store)
+
+	post := func(path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"email": "nobody@example.com", "password": "wrong"}`))
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Authenticated callers have their own budget
+	if w := post("/api/issues", "test-key"); w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("Expected RateLimit-Remaining 0, got %q", w.Header().Get("RateLimit-Remaining"))
+	}
+	if w := post("/api/issues", "test-key"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After, got %d", w.Code)
+	}
+
+	// Login attempts are limited by client IP
+	if w := post("/api/auth/login", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	if w := post("/api/auth/login", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+
+	// Requests are counted by IP before authentication, so guessing a
+	// different key each time does not get a fresh budget
+	if w := post("/api/issues", "guess-1"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	if w := post("/api/issues", "guess-2"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 before authentication, got %d", w.Code)
+	}
+}
+
+func TestSetupRouterProblems(t *testing.T) {
+	cfg := &config.Config{Auth: config.AuthConfig{APIKey: "test-key", APIKeyRole: models.RoleAdmin}}
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	router := setupRouter(cfg, store)
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		code         utils.Code
+	}{
+		{"GET", "/api/issues/missing", http.StatusNotFound, utils.CodeIssueNotFound},
+		{"DELETE", "/api/issues/missing", http.StatusNotFound, utils.CodeIssueNotFound},
+		{"GET", "/api/nope", http.StatusNotFound, utils.CodeRouteNotFound},
+		{"PUT", "/api/issues", http.StatusMethodNotAllowed, utils.CodeMethodNotAllowed},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-API-Key", "test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var p utils.Problem
+		json.Unmarshal(w.Body.Bytes(), &p)
+		if w.Code != tc.status || p.Code != tc.code {
+			t.Errorf("%s %s: expected %d %s, got %d %s", tc.method, tc.path, tc.status, tc.code, w.Code, w.Body.String())
+		}
+		if w.Header().Get("Content-Type") != utils.ProblemContentType || p.RequestID == "" || p.Instance != tc.path {
+			t.Errorf("%s %s: expected a problem with request ID and instance, got %s", tc.method, tc.path, w.Body.String())
+		}
+	}
+
+	// Requests without credentials are rejected by middleware with a problem too
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/issues", nil))
+	var p utils.Problem
+	json.Unmarshal(w.Body.Bytes(), &p)
+	if w.Code != http.StatusUnauthorized || p.Code != utils.CodeUnauthenticated {
+		t.Errorf("Expected 401 UNAUTHENTICATED, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetupRouterIdempotency(t *testing.T) {
+	cfg := &config.Config{
+		Auth:        config.AuthConfig{APIKey: "test-key", APIKeyRole: models.RoleAdmin},
+		Idempotency: config.IdempotencyConfig{TTL: time.Hour, MaxKeys: 100},
+	}
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	router := setupRouter(cfg, store)
+
+	create := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/issues", strings.NewReader(`{"title": "Retried", "status": "Todo", "priority": "Low"}`))
+		req.Header.Set("X-API-Key", "test-key")
+		req.Header.Set("Idempotency-Key", "create-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	first, retry := create(), create()
+	if first.Code != http.StatusCreated || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("Expected the retry to replay the first 201, got %d and %d", first.Code, retry.Code)
+	}
+
+	list, _ := store.ListIssues(context.Background(), models.IssueQuery{})
+	if len(list.Items) != 1 {
+		t.Errorf("Expected one issue to be created, got %d", len(list.Items))
+	}
+}
+
+func TestSetupRouterCaching(t *testing.T) {
+	cfg := &config.Config{Auth: config.AuthConfig{APIKey: "test-key", APIKeyRole: models.RoleAdmin}}
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	router := setupRouter(cfg, store)
+
+	for path, want := range map[string]string{
+		"/api/issues":        "private, no-cache",
+		"/api/board/version": "private, no-cache",
+		"/api/stats":         "private, max-age=60",
+		"/api/auth/session":  "no-store",
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-API-Key", "test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if got := w.Header().Get("Cache-Control"); got != want {
+			t.Errorf("%s: expected Cache-Control %q, got %q", path, want, got)
+		}
+	}
+}
+
+func TestSetupRouterSyncPermissions(t *testing.T) {
+	cfg := &config.Config{Auth: config.AuthConfig{APIKey: "test-key", APIKeyRole: models.RoleMember}}
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	router := setupRouter(cfg, store)
+
+	issue := models.Issue{ID: "issue-1", Title: "Keep", Status: "Todo", Priority: "Low", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := store.CreateIssue(context.Background(), issue); err != nil {
+		t.Fatalf("CreateIssue() failed: %v", err)
+	}
+
+	// Members may move issues but not delete them, in a batch as elsewhere
+	req := httptest.NewRequest("POST", "/api/sync", strings.NewReader(`{"mutations": [
+		{"client_id": "move", "op": "move", "id": "issue-1", "data": {"status": "Done", "order_index": 1}},
+		{"client_id": "delete", "op": "delete", "id": "issue-1"}
+	]}`))
+	req.Header.Set("X-API-Key", "test-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var result models.SyncResult
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if w.Code != http.StatusOK || len(result.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d %s", w.Code, w.Body.String())
+	}
+	if result.Results[0].Result != models.MutationApplied {
+		t.Errorf("Expected the move applied, got %+v", result.Results[0])
+	}
+	var p utils.Problem
+	json.Unmarshal(result.Results[1].Problem, &p)
+	if result.Results[1].Result != models.MutationRejected || p.Code != utils.CodePermissionDenied {
+		t.Errorf("Expected the delete rejected with PERMISSION_DENIED, got %+v", result.Results[1])
+	}
+	if got, _ := store.GetIssue(context.Background(), "issue-1"); got == nil {
+		t.Error("Expected the issue to survive")
+	}
+}
+
 func TestSetupServer(t *testing.T) {
 	cfg := &config.Config{
 		Server: config.ServerConfig{
@@ -74,4 +270,29 @@ func TestSetupServer(t *testing.T) {
 	if server.Handler != handler {
 		t.Error("setupServer() Handler not set correctly")
 	}
-}
\ No newline at end of file
+}
+
+func TestSetupStoreMemory(t *testing.T) {
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{
+			Driver: "memory",
+		},
+	}
+
+	store, err := setupStore(cfg)
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+
+	if err := seedDemoData(context.Background(), store); err != nil {
+		t.Fatalf("seedDemoData() failed: %v", err)
+	}
+
+	list, err := store.ListIssues(context.Background(), models.IssueQuery{Page: 1})
+	if err != nil {
+		t.Fatalf("ListIssues() failed: %v", err)
+	}
+	if len(list.Items) == 0 {
+		t.Error("Expected demo issues to be seeded")
+	}
+}
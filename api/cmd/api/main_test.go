@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -39,47 +40,14 @@ func setupAPITest(t *testing.T) (*httptest.Server, func()) {
 	require.NoError(t, err)
 
 	// Create schema
-	schema := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		avatar_url TEXT
-	);
-
-	CREATE TABLE labels (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		color TEXT NOT NULL
-	);
-
-	CREATE TABLE issues (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		description TEXT,
-		status TEXT NOT NULL,
-		priority TEXT NOT NULL,
-		assignee_id TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		order_index REAL NOT NULL DEFAULT 0,
-		FOREIGN KEY (assignee_id) REFERENCES users(id)
-	);
-
-	CREATE TABLE issue_labels (
-		issue_id TEXT NOT NULL,
-		label_id TEXT NOT NULL,
-		PRIMARY KEY (issue_id, label_id),
-		FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
-		FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
-	);
-
-	-- Insert default labels
-	INSERT INTO labels (id, name, color) VALUES
+	err = database.Migrate(db, filepath.Join("..", "..", "migrations", database.DriverSQLite))
+	require.NoError(t, err)
+
+	// Insert default labels
+	_, err = db.Exec(`INSERT INTO labels (id, name, color) VALUES
 		('bug', 'Bug', '#FF0000'),
 		('feature', 'Feature', '#00FF00'),
-		('enhancement', 'Enhancement', '#0000FF');
-	`
-	_, err = db.Exec(schema)
+		('enhancement', 'Enhancement', '#0000FF')`)
 	require.NoError(t, err)
 
 	// Setup repository and handlers
@@ -612,7 +580,8 @@ func TestAPIErrorHandling(t *testing.T) {
 		require.NoError(t, err)
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
 	})
 
 	t.Run("Delete Non-existent Issue", func(t *testing.T) {
@@ -623,6 +592,7 @@ func TestAPIErrorHandling(t *testing.T) {
 		require.NoError(t, err)
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
 	})
 }
\ No newline at end of file
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/config"
+	"github.com/abhir9/issue-board/api/internal/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// routeRoles is the least privileged role allowed on every /api route
+var routeRoles = map[string]models.Role{
+	"GET /api/board/version":            models.RoleViewer,
+	"GET /api/issues":                   models.RoleViewer,
+	"POST /api/issues":                  models.RoleMember,
+	"GET /api/issues/{id}":              models.RoleViewer,
+	"PATCH /api/issues/{id}":            models.RoleMember,
+	"PATCH /api/issues/{id}/move":       models.RoleMember,
+	"POST /api/issues/{id}/archive":     models.RoleMember,
+	"POST /api/issues/{id}/unarchive":   models.RoleMember,
+	"DELETE /api/issues/{id}":           models.RoleMaintainer,
+	"POST /api/issues/{id}/restore":     models.RoleMaintainer,
+	"DELETE /api/issues/{id}/permanent": models.RoleAdmin,
+	"GET /api/issues/{id}/comments":     models.RoleViewer,
+	"POST /api/issues/{id}/comments":    models.RoleMember,
+	"GET /api/trash":                    models.RoleMaintainer,
+	"GET /api/users":                    models.RoleViewer,
+	"POST /api/users":                   models.RoleAdmin,
+	"PUT /api/users/{id}/role":          models.RoleAdmin,
+	"GET /api/labels":                   models.RoleViewer,
+	"GET /api/templates":                models.RoleViewer,
+	"POST /api/templates":               models.RoleMaintainer,
+	"GET /api/templates/{id}":           models.RoleViewer,
+	"PUT /api/templates/{id}":           models.RoleMaintainer,
+	"DELETE /api/templates/{id}":        models.RoleMaintainer,
+	"GET /api/fields":                   models.RoleViewer,
+	"POST /api/fields":                  models.RoleMaintainer,
+	"GET /api/fields/{id}":              models.RoleViewer,
+	"PATCH /api/fields/{id}":            models.RoleMaintainer,
+	"DELETE /api/fields/{id}":           models.RoleMaintainer,
+	"GET /api/issue-types":              models.RoleViewer,
+	"GET /api/issue-types/{key}":        models.RoleViewer,
+	"PUT /api/issue-types/{key}":        models.RoleMaintainer,
+	"GET /api/stats":                    models.RoleViewer,
+	"GET /api/stats/created-vs-closed":  models.RoleViewer,
+	"GET /api/stats/cycle-time":         models.RoleViewer,
+	"GET /api/stats/throughput":         models.RoleViewer,
+	"GET /api/reports/cumulative-flow":  models.RoleViewer,
+	"GET /api/sync":                     models.RoleViewer,
+	"POST /api/sync":                    models.RoleMember,
+}
+
+// publicRoutes are reachable without credentials
+var publicRoutes = map[string]bool{
+	"GET /api/health":             true,
+	"POST /api/auth/login":        true,
+	"POST /api/auth/logout":       true,
+	"GET /api/auth/session":       true,
+	"GET /api/auth/oidc/login":    true,
+	"GET /api/auth/oidc/callback": true,
+}
+
+func TestRoutePermissions(t *testing.T) {
+	routers := make(map[models.Role]*chi.Mux)
+	for _, role := range models.ValidRoles {
+		cfg := &config.Config{
+			Server: config.ServerConfig{AllowedOrigins: []string{"*"}},
+			Auth:   config.AuthConfig{APIKey: "test-key", APIKeyRole: role},
+		}
+		store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+		if err != nil {
+			t.Fatalf("setupStore() failed: %v", err)
+		}
+		routers[role] = setupRouter(cfg, store)
+	}
+
+	// Every registered API route must be covered by the matrix
+	err := chi.Walk(routers[models.RoleAdmin], func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/") && !publicRoutes[method+" "+route] {
+			if _, ok := routeRoles[method+" "+route]; !ok {
+				t.Errorf("Route %s %s has no entry in the permission matrix", method, route)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chi.Walk() failed: %v", err)
+	}
+
+	for route, minRole := range routeRoles {
+		method, pattern, _ := strings.Cut(route, " ")
+		path := strings.ReplaceAll(pattern, "{id}", "missing")
+
+		allowed := false
+		for _, role := range models.ValidRoles {
+			allowed = allowed || role == minRole
+
+			req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+			req.Header.Set("X-API-Key", "test-key")
+			w := httptest.NewRecorder()
+			routers[role].ServeHTTP(w, req)
+
+			if denied := w.Code == http.StatusForbidden; denied == allowed {
+				t.Errorf("%s as %s: got status %d, allowed=%v", route, role, w.Code, allowed)
+			}
+		}
+	}
+}
+
+func TestBearerTokenAuth(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			APIKey: "test-key",
+			JWT: config.JWTConfig{
+				Secret:      secret,
+				Issuer:      "https://auth.example.com",
+				Audience:    "issue-board",
+				DefaultRole: models.RoleViewer,
+			},
+		},
+	}
+	store, err := setupStore(&config.Config{Database: config.DatabaseConfig{Driver: "memory"}})
+	if err != nil {
+		t.Fatalf("setupStore() failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := store.CreateUser(ctx, models.User{ID: "svc-importer", Name: "Importer"}); err != nil {
+		t.Fatalf("CreateUser() failed: %v", err)
+	}
+	if err := store.SetUserRole(ctx, models.RoleAssignment{UserID: "svc-importer", Role: models.RoleMember}); err != nil {
+		t.Fatalf("SetUserRole() failed: %v", err)
+	}
+	r := setupRouter(cfg, store)
+
+	token := func(sub, scope string) string {
+		b64 := base64.RawURLEncoding
+		payload, _ := json.Marshal(map[string]interface{}{
+			"iss": "https://auth.example.com", "aud": "issue-board", "sub": sub, "scope": scope,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		input := b64.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + b64.EncodeToString(payload)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(input))
+		return input + "." + b64.EncodeToString(mac.Sum(nil))
+	}
+	serve := func(method, path, bearer string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"title": "Imported", "status": "Todo", "priority": "Low"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		bearer string
+		want   int
+	}{
+		{"Assigned role", "POST", token("svc-importer", "issues:read issues:write"), http.StatusCreated},
+		{"Out of scope", "POST", token("svc-importer", "issues:read"), http.StatusForbidden},
+		{"Beyond the role", "DELETE", token("svc-importer", "issues:delete"), http.StatusForbidden},
+		{"Default role", "GET", token("svc-reporting", "issues:read"), http.StatusOK},
+		{"Default role cannot write", "POST", token("svc-reporting", "issues:write"), http.StatusForbidden},
+		{"Invalid token", "GET", "not-a-jwt", http.StatusUnauthorized},
+	} {
+		path := "/api/issues"
+		if tt.method == "DELETE" {
+			path += "/missing"
+		}
+		if got := serve(tt.method, path, tt.bearer); got != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
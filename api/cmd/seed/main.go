@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -14,7 +17,7 @@ func main() {
 	}
 	defer database.DB.Close()
 
-	if err := database.RunMigrations("./migrations"); err != nil {
+	if err := database.RunMigrations("./migrations/sqlite"); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -53,8 +56,35 @@ func seedDatabase() error {
 	return nil
 }
 
+// store writes the seed data, recording each entity in the change log and
+// bumping the board version like any other write
+func store() *database.Repository {
+	return database.NewRepository(database.DB)
+}
+
 func clearExistingData() error {
-	_, err := database.DB.Exec("DELETE FROM issue_labels")
+	// Leave tombstones for everything wiped, so clients syncing from an old
+	// token drop it, and bump the board version for cached lists
+	now := time.Now().UTC()
+	for _, entity := range []struct{ name, table string }{
+		{models.EntityIssue, "issues"},
+		{models.EntityLabel, "labels"},
+		{models.EntityUser, "users"},
+	} {
+		query := "INSERT INTO change_log (entity, entity_id, deleted, changed_at) SELECT ?, id, TRUE, ? FROM " + entity.table
+		if _, err := database.DB.Exec(query, entity.name, now); err != nil {
+			return err
+		}
+	}
+	if _, err := database.DB.Exec("UPDATE board_version SET version = version + 1, updated_at = ? WHERE id = 1", now); err != nil {
+		return err
+	}
+
+	_, err := database.DB.Exec("DELETE FROM issue_status_history")
+	if err != nil {
+		return err
+	}
+	_, err = database.DB.Exec("DELETE FROM issue_labels")
 	if err != nil {
 		return err
 	}
@@ -66,6 +96,11 @@ func clearExistingData() error {
 	if err != nil {
 		return err
 	}
+	for _, table := range []string{"sessions", "user_identities", "accounts", "user_roles"} {
+		if _, err = database.DB.Exec("DELETE FROM " + table); err != nil {
+			return err
+		}
+	}
 	_, err = database.DB.Exec("DELETE FROM users")
 	return err
 }
@@ -81,9 +116,8 @@ func seedUsers() error {
 	}
 
 	for _, u := range users {
-		id := uuid.New().String()
-		_, err := database.DB.Exec("INSERT INTO users (id, name, avatar_url) VALUES (?, ?, ?)", id, u.Name, u.AvatarURL)
-		if err != nil {
+		user := models.User{ID: uuid.New().String(), Name: u.Name, AvatarURL: u.AvatarURL}
+		if err := store().CreateUser(context.Background(), user); err != nil {
 			return err
 		}
 		fmt.Printf("Inserted user: %s\n", u.Name)
@@ -105,8 +139,7 @@ func seedLabels() ([]string, error) {
 	var labelIDs []string
 	for _, l := range labels {
 		id := uuid.New().String()
-		_, err := database.DB.Exec("INSERT INTO labels (id, name, color) VALUES (?, ?, ?)", id, l.Name, l.Color)
-		if err != nil {
+		if err := store().CreateLabel(context.Background(), models.Label{ID: id, Name: l.Name, Color: l.Color}); err != nil {
 			return nil, err
 		}
 		labelIDs = append(labelIDs, id)
@@ -173,34 +206,33 @@ func seedIssues(userIDs, labelIDs []string) error {
 		{"Legacy API support", "Support for old API version - no longer required", "Canceled", "Low"},
 	}
 
+	now := time.Now()
 	for i, issue := range issues {
-		id := uuid.New().String()
 		assigneeID := userIDs[i%len(userIDs)]
 
-		_, err := database.DB.Exec(`
-			INSERT INTO issues (id, title, description, status, priority, assignee_id, order_index)
-			VALUES (?, ?, ?, ?, ?, ?, ?)
-		`, id, issue.Title, issue.Description, issue.Status, issue.Priority, assigneeID, float64(i))
-		if err != nil {
-			return err
+		// Add 1-2 labels per issue
+		labels := []models.Label{{ID: labelIDs[i%len(labelIDs)]}}
+		if i%3 == 0 && len(labelIDs) > 1 {
+			labels = append(labels, models.Label{ID: labelIDs[(i+1)%len(labelIDs)]})
 		}
 
-		// Add 1-2 labels per issue
-		labelID := labelIDs[i%len(labelIDs)]
-		_, err = database.DB.Exec("INSERT INTO issue_labels (issue_id, label_id) VALUES (?, ?)", id, labelID)
+		// The store also starts the status history for cycle time reports
+		err := store().CreateIssue(context.Background(), models.Issue{
+			ID:          uuid.New().String(),
+			Title:       issue.Title,
+			Description: issue.Description,
+			Status:      issue.Status,
+			Priority:    issue.Priority,
+			AssigneeID:  &assigneeID,
+			Labels:      labels,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			OrderIndex:  float64(i),
+		})
 		if err != nil {
 			return err
 		}
 
-		// Add a second label to some issues
-		if i%3 == 0 && len(labelIDs) > 1 {
-			secondLabelID := labelIDs[(i+1)%len(labelIDs)]
-			_, err = database.DB.Exec("INSERT INTO issue_labels (issue_id, label_id) VALUES (?, ?)", id, secondLabelID)
-			if err != nil {
-				return err
-			}
-		}
-
 		fmt.Printf("Inserted issue: %s (Status: %s, Priority: %s)\n", issue.Title, issue.Status, issue.Priority)
 	}
 	return nil
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/abhir9/issue-board/api/internal/database"
@@ -33,41 +35,7 @@ func setupSeedTest(t *testing.T) (*sql.DB, func()) {
 	require.NoError(t, err)
 
 	// Create schema
-	schema := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		avatar_url TEXT
-	);
-
-	CREATE TABLE labels (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		color TEXT NOT NULL
-	);
-
-	CREATE TABLE issues (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		description TEXT,
-		status TEXT NOT NULL,
-		priority TEXT NOT NULL,
-		assignee_id TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		order_index REAL NOT NULL DEFAULT 0,
-		FOREIGN KEY (assignee_id) REFERENCES users(id)
-	);
-
-	CREATE TABLE issue_labels (
-		issue_id TEXT NOT NULL,
-		label_id TEXT NOT NULL,
-		PRIMARY KEY (issue_id, label_id),
-		FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
-		FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
-	);
-	`
-	_, err = tmpFile.Exec(schema)
+	err = database.Migrate(tmpFile, filepath.Join("..", "..", "migrations", database.DriverSQLite))
 	require.NoError(t, err)
 
 	cleanup := func() {
@@ -234,4 +202,55 @@ func TestSeedDatabase(t *testing.T) {
 	assert.Equal(t, 4, labelCount)
 	assert.Equal(t, 20, issueCount)
 	assert.True(t, relationshipCount >= 20)
-}
\ No newline at end of file
+}
+
+func TestReseedIsVisibleToSync(t *testing.T) {
+	db, cleanup := setupSeedTest(t)
+	defer cleanup()
+
+	// Override database.DB for testing
+	originalDB := database.DB
+	database.DB = db
+	defer func() { database.DB = originalDB }()
+
+	ctx := context.Background()
+	repo := database.NewRepository(db)
+	require.NoError(t, seedDatabase())
+	var oldIssueIDs []string
+	rows, err := db.Query("SELECT id FROM issues")
+	require.NoError(t, err)
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		oldIssueIDs = append(oldIssueIDs, id)
+	}
+	rows.Close()
+	token, err := repo.GetChangeSeq(ctx)
+	require.NoError(t, err)
+	assert.True(t, token > 0, "Expected seeding to be recorded in the change log")
+	version, err := repo.GetBoardVersion(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, seedDatabase())
+
+	// A client syncing from before the reseed drops the old issues
+	changes, err := repo.GetChanges(ctx, token, 1000)
+	require.NoError(t, err)
+	tombstones := make(map[string]bool)
+	created := 0
+	for _, c := range changes {
+		if c.Entity == models.EntityIssue && c.Deleted {
+			tombstones[c.EntityID] = true
+		} else if c.Entity == models.EntityIssue {
+			created++
+		}
+	}
+	for _, id := range oldIssueIDs {
+		assert.True(t, tombstones[id], "Expected a tombstone for issue %s", id)
+	}
+	assert.Equal(t, 20, created)
+
+	after, err := repo.GetBoardVersion(ctx)
+	require.NoError(t, err)
+	assert.True(t, after.Version > version.Version, "Expected the board version to advance")
+}
@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/issues": {
-            "get": {
-                "description": "Get a list of issues, optionally filtered by status, assignee, or priority",
+        "/auth/login": {
+            "post": {
+                "description": "Starts a session for a local account. The session cookie is HTTP-only; the returned\ncsrf_token must be sent in the X-CSRF-Token header of every non-GET request.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,49 +34,267 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "issues"
+                    "auth"
                 ],
-                "summary": "Get all issues",
+                "summary": "Log in with email and password",
                 "parameters": [
+                    {
+                        "description": "Email and password",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.LoginRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.SessionInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Ends the current session. Requires the X-CSRF-Token header.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log out",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by assignee ID",
-                        "name": "assignee",
-                        "in": "query"
+                        "description": "CSRF token of the session",
+                        "name": "X-CSRF-Token",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Filter by priority",
-                        "name": "priority",
-                        "in": "query"
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Redirect target of the issuer. Exchanges the code, verifies the ID token, links or\ncreates the user, starts a session and redirects to the frontend.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Complete an OIDC login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Filter by priority",
-                        "name": "priority",
-                        "in": "query"
+                        "description": "State from the login redirect",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the frontend",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects the browser to the configured issuer (authorization code flow with PKCE)",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in with the OIDC provider",
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the issuer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "OIDC login is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/session": {
+            "get": {
+                "description": "Returns the logged-in user and the session's CSRF token, e.g. after a page reload",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get the current session",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.SessionInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/board/version": {
+            "get": {
+                "description": "Get the board-wide change counter, which every write to issues, labels or users advances.\nPolling clients compare it (or send If-None-Match) to learn that nothing changed without listing issues.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "board"
+                ],
+                "summary": "Get the board version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the client holds",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.BoardVersion"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
+                ]
+            }
+        },
+        "/fields": {
+            "get": {
+                "description": "List the custom field definitions, ordered by key",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "fields"
+                ],
+                "summary": "List custom fields",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/api_internal_models.Issue"
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CustomField"
                             }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
@@ -87,7 +305,7 @@ const docTemplate = `{
                 ]
             },
             "post": {
-                "description": "Create a new issue with the provided details",
+                "description": "Define an issue attribute of type text, number, date, select, multi_select or user.\nSelect fields need options; the key and type cannot change later.",
                 "consumes": [
                     "application/json"
                 ],
@@ -95,37 +313,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "issues"
+                    "fields"
                 ],
-                "summary": "Create a new issue",
+                "summary": "Define a custom field",
                 "parameters": [
                     {
-                        "description": "Issue content",
-                        "name": "issue",
+                        "description": "Field definition",
+                        "name": "field",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_internal_models.CreateIssueRequest"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CreateFieldRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/api_internal_models.Issue"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CustomField"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already in use",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
@@ -136,23 +372,19 @@ const docTemplate = `{
                 ]
             }
         },
-        "/issues/{id}": {
+        "/fields/{id}": {
             "get": {
-                "description": "Get details of a specific issue by ID",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "issues"
+                    "fields"
                 ],
-                "summary": "Get a specific issue",
+                "summary": "Get a custom field",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Issue ID",
+                        "description": "Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -162,19 +394,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_internal_models.Issue"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CustomField"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
@@ -185,15 +417,15 @@ const docTemplate = `{
                 ]
             },
             "delete": {
-                "description": "Delete an issue by ID",
+                "description": "Delete a custom field together with its values on every issue",
                 "tags": [
-                    "issues"
+                    "fields"
                 ],
-                "summary": "Delete an issue",
+                "summary": "Delete a custom field",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Issue ID",
+                        "description": "Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -203,10 +435,16 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
@@ -217,7 +455,7 @@ const docTemplate = `{
                 ]
             },
             "patch": {
-                "description": "Update details of an existing issue",
+                "description": "Rename a custom field or change its options. Options can be added and reordered\nbut not removed, so that existing values stay valid.",
                 "consumes": [
                     "application/json"
                 ],
@@ -225,24 +463,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "issues"
+                    "fields"
                 ],
-                "summary": "Update an issue",
+                "summary": "Update a custom field",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Issue ID",
+                        "description": "Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Issue updates",
-                        "name": "issue",
+                        "description": "Field changes",
+                        "name": "field",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_internal_models.UpdateIssueRequest"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.UpdateFieldRequest"
                         }
                     }
                 ],
@@ -250,19 +488,31 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api_internal_models.Issue"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CustomField"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
@@ -273,54 +523,30 @@ const docTemplate = `{
                 ]
             }
         },
-        "/issues/{id}/move": {
-            "patch": {
-                "description": "Move an issue to a new status and/or order",
-                "consumes": [
-                    "application/json"
-                ],
+        "/issue-types": {
+            "get": {
+                "description": "List the issue types with their icon, color and rules, in display order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "issues"
-                ],
-                "summary": "Move an issue",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Issue ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Move details",
-                        "name": "move",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api_internal_models.MoveIssueRequest"
-                        }
-                    }
+                    "issue-types"
                 ],
+                "summary": "List issue types",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueType"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
@@ -331,9 +557,52 @@ const docTemplate = `{
                 ]
             }
         },
-        "/labels": {
+        "/issue-types/{key}": {
             "get": {
-                "description": "Get a list of all labels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issue-types"
+                ],
+                "summary": "Get an issue type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type key (bug, feature, task or epic)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueType"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace the display and rules of an issue type. Required fields are description, assignee_id,\nlabel_ids or cf.\u003ckey\u003e; statuses restricts the statuses its issues may be in, all if empty.\nRules apply to issues as they are created or changed, not to existing issues.",
                 "consumes": [
                     "application/json"
                 ],
@@ -341,178 +610,2829 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "labels"
+                    "issue-types"
+                ],
+                "summary": "Update an issue type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Type key (bug, feature, task or epic)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Issue type",
+                        "name": "type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueTypeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueType"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues": {
+            "get": {
+                "description": "Get a list of issues, optionally filtered by status, assignee, priority, or labels.\nPassing cursor or limit switches to keyset pagination and an {items, next_cursor, total_count} envelope;\notherwise a bare array is returned and page/page_size select an offset page.\nX-Total-Count and RFC 8288 Link headers are set on paginated responses.\nThe envelope also lists the issue count and WIP limits of each status column.\nArchived issues are left out unless include_archived is true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Get all issues",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name (e.g., ?labels=bug)",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return archived issues",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several",
+                        "name": "cf.key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated issue attributes to return (id is always included)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resources to embed: assignee, labels, comments_count, children (default assignee, labels)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort keys, '-' prefix for descending (order_index, priority, status, assignee, title, created_at, updated_at, cf.\u003ckey\u003e)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Keyset page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset page number (legacy)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset page size (legacy)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all labels",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/api_internal_models.Label"
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                            }
+                        },
+                        "headers": {
+                            "X-Column-Counts": {
+                                "type": "string",
+                                "description": "JSON array of the per-column counts and limits, for the plain array response"
                             }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new issue with the provided details.\nWith template_id, the template's title prefix is added and its description, status, priority, type and labels fill in the fields left empty.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Create a new issue",
+                "parameters": [
+                    {
+                        "description": "Issue content",
+                        "name": "issue",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CreateIssueRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                        },
+                        "headers": {
+                            "X-WIP-Warning": {
+                                "type": "string",
+                                "description": "WIP limits exceeded by the change"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "WIP limit exceeded (strict mode)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown assignee, labels, parent or template, or a sub-issue cycle",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
                         }
                     }
                 },
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ]
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}": {
+            "get": {
+                "description": "Get details of a specific issue by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Get a specific issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Move an issue to the trash. It can be restored until it is purged after the trash retention period.",
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Delete an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Update details of an existing issue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Update an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Issue updates",
+                        "name": "issue",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.UpdateIssueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                        },
+                        "headers": {
+                            "X-WIP-Warning": {
+                                "type": "string",
+                                "description": "WIP limits exceeded by the change"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "WIP limit exceeded (strict mode)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown assignee, labels or parent, or a sub-issue cycle",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}/archive": {
+            "post": {
+                "description": "Take an issue off the board. It stays readable by ID and is listed with include_archived=true.\nArchiving an archived issue changes nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Archive an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}/comments": {
+            "get": {
+                "description": "List the comments of an issue, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "List the comments of an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Comment"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Add a comment to an issue. The author is the authenticated user, if any.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Comment on an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment to add",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CreateCommentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}/move": {
+            "patch": {
+                "description": "Move an issue to a new status and/or order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Move an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Move details (status and order_index)",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.UpdateIssueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.MoveIssueResponse"
+                        },
+                        "headers": {
+                            "X-WIP-Warning": {
+                                "type": "string",
+                                "description": "WIP limits exceeded by the move"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "WIP limit exceeded (strict mode)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}/permanent": {
+            "delete": {
+                "description": "Delete an issue, live or in the trash, together with its labels and history. It cannot be restored.",
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Permanently delete an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}/restore": {
+            "post": {
+                "description": "Move an issue out of the trash, back to its status column and position",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Restore an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                        }
+                    },
+                    "404": {
+                        "description": "Not in the trash",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/issues/{id}/unarchive": {
+            "post": {
+                "description": "Put an archived issue back on the board in its status column. Unarchiving a live issue changes nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "Unarchive an issue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issue ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/labels": {
+            "get": {
+                "description": "Get a list of all labels",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "labels"
+                ],
+                "summary": "Get all labels",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Label"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/reports/cumulative-flow": {
+            "get": {
+                "description": "Number of issues in each status at the end of each day or week, reconstructed from status history.\nIssues that predate history tracking are placed using their created_at and updated_at.\nAccepts the same filters as the issue list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get cumulative flow diagram data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive for YYYY-MM-DD (default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size: day (default) or week",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several",
+                        "name": "cf.key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CumulativeFlow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Count issues grouped by status, priority, assignee and label, optionally with a 2-D pivot.\nAccepts the same filters as the issue list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get issue counts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several",
+                        "name": "cf.key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated dimensions to group by (status, priority, assignee, label); default all",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two dimensions to cross-tabulate, e.g. status,assignee",
+                        "name": "pivot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats/created-vs-closed": {
+            "get": {
+                "description": "Count issues created and closed (moved to Done or Canceled) per day or week.\nAccepts the same filters as the issue list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get created vs closed issues over time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive for YYYY-MM-DD (default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size: day (default) or week",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several",
+                        "name": "cf.key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CreatedVsClosedReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats/cycle-time": {
+            "get": {
+                "description": "Percentiles (in hours) of cycle time (first move to In Progress until Done) and\nlead time (created until Done) for issues completed in the range, from status history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get cycle and lead time percentiles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive for YYYY-MM-DD (default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several",
+                        "name": "cf.key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.FlowTimes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats/throughput": {
+            "get": {
+                "description": "Number of issues each assignee completed (moved to Done) in the range.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get throughput per assignee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive for YYYY-MM-DD (default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several",
+                        "name": "cf.key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.ThroughputReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/sync": {
+            "get": {
+                "description": "Get the issues, labels and users created, updated or deleted since a sync token.\nWithout since the response is a full snapshot of the board (full is true).\nEach entity appears once, in its current state; deleted entities are listed as tombstones.\nStore next and pass it as since on the next sync; has_more means more changes are waiting.\nA token newer than the server's change log, e.g. after a restore, gets 410 and calls for a full sync.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Sync changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync token from the previous response",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum changed entities to return (default 500, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.SyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "410": {
+                        "description": "Sync token no longer valid",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Apply a batch of issue mutations queued by an offline client, in order, and report the result of each.\nA mutation runs as the equivalent create, update, move or delete request, with the same validation, WIP limits and permissions, and is rejected with that request's problem if it fails.\nA mutation with a base sync token conflicts, and is not applied, when the issue changed after base; the result carries the server's copy.\nMutations are applied independently: a rejected or conflicting one does not stop the rest.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Apply queued mutations",
+                "parameters": [
+                    {
+                        "description": "Queued mutations",
+                        "name": "mutations",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.SyncRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.SyncResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/templates": {
+            "get": {
+                "description": "List all issue templates, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List issue templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a template whose defaults are applied to issues created with its template_id.\nEmpty status, priority and type leave them to the create request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create an issue template",
+                "parameters": [
+                    {
+                        "description": "Template content",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.TemplateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Name already in use",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown labels",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/templates/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get an issue template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueTemplate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace every field of a template. Issues already created from it are unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Replace an issue template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template content",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Name already in use",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown labels",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a template. Issues already created from it are unchanged.",
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Delete an issue template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/trash": {
+            "get": {
+                "description": "List deleted issues, most recently deleted first, until they are restored or purged.\nAccepts the filters, fieldsets, sort and pagination parameters of GET /issues.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "issues"
+                ],
+                "summary": "List the trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by assignee ID",
+                        "name": "assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by label name",
+                        "name": "labels",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort keys (default -updated_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Keyset page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get a list of all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.User"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a user, optionally with a local account (email and password) and a global role.\nRequires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "User details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.CreateUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to retries with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "description": "Set a user's global role. Requires the admin role.\nproject_id is rejected until the board has projects, since no route would consult the role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Assign a role to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role assignment",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.SetRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.RoleAssignment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Problem"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "github_com_abhir9_issue-board_api_internal_models.AssigneeThroughput": {
+            "type": "object",
+            "properties": {
+                "assignee_id": {
+                    "type": "string"
+                },
+                "completed": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.BoardVersion": {
+            "type": "object",
+            "properties": {
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.ColumnCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "per_assignee_limit": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.Comment": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "description": "the caller that wrote it, if known",
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issue_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CreateCommentRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CreateFieldRequest": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CreateIssueRequest": {
+            "type": "object",
+            "properties": {
+                "assignee_id": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "values by custom field key",
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "label_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "description": "defaults for the fields left out",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "defaults to task",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Role"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CreatedVsClosedReport": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.FlowBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CumulativeFlow": {
+            "type": "object",
+            "properties": {
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.FlowSeries"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.CustomField": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "description": "choices of select and multi_select fields, in display order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.FieldValues": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "github_com_abhir9_issue-board_api_internal_models.FlowBucket": {
+            "type": "object",
+            "properties": {
+                "closed": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.FlowSeries": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.FlowTimes": {
+            "type": "object",
+            "properties": {
+                "cycle_time": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Percentiles"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "lead_time": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Percentiles"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.Issue": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "set while the issue is archived",
+                    "type": "string"
+                },
+                "assignee": {
+                    "description": "For response population",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.User"
+                        }
+                    ]
+                },
+                "assignee_id": {
+                    "type": "string"
+                },
+                "children": {
+                    "description": "sub-issues, set when expanded",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.IssueRef"
+                    }
+                },
+                "comments_count": {
+                    "description": "set when expanded",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "values by custom field key",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.FieldValues"
+                        }
+                    ]
+                },
+                "deleted_at": {
+                    "description": "set while the issue is in the trash",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "labels": {
+                    "description": "For response population",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Label"
+                    }
+                },
+                "order_index": {
+                    "type": "number"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Low, Medium, High, Critical",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Backlog, Todo, In Progress, Done, Canceled",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "bug, feature, task, epic",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.IssueList": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "set by the API, not the store",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.ColumnCount"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.IssueRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.IssueStats": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.StatGroup"
+                        }
+                    }
+                },
+                "pivot": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Pivot"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.IssueTemplate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "markdown skeleton",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title_prefix": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.IssueType": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required_fields": {
+                    "description": "must be set on issues of the type",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "statuses": {
+                    "description": "statuses issues of the type may be in; empty allows all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.IssueTypeRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.Label": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
             }
         },
-        "/users": {
-            "get": {
-                "description": "Get a list of all users",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Get all users",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/api_internal_models.User"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
+        "github_com_abhir9_issue-board_api_internal_models.MoveIssueResponse": {
+            "type": "object",
+            "properties": {
+                "issue": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
                 },
-                "security": [
-                    {
-                        "ApiKeyAuth": []
+                "next": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                },
+                "previous": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.Mutation": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "description": "sync token the change was made against",
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "the create, update or move request body",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
-                ]
+                },
+                "id": {
+                    "description": "the issue, except for create",
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                }
             }
-        }
-    },
-    "definitions": {
-        "api_internal_models.CreateIssueRequest": {
+        },
+        "github_com_abhir9_issue-board_api_internal_models.MutationResult": {
             "type": "object",
             "properties": {
-                "assignee_id": {
+                "client_id": {
                     "type": "string"
                 },
-                "description": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "issue": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                },
+                "problem": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "result": {
                     "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.Percentiles": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
                 },
-                "label_ids": {
+                "p50": {
+                    "type": "number"
+                },
+                "p75": {
+                    "type": "number"
+                },
+                "p90": {
+                    "type": "number"
+                },
+                "p95": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.Pivot": {
+            "type": "object",
+            "properties": {
+                "cells": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.PivotCell"
                     }
                 },
-                "priority": {
+                "columns": {
                     "type": "string"
                 },
-                "status": {
+                "rows": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.PivotCell": {
+            "type": "object",
+            "properties": {
+                "column": {
                     "type": "string"
                 },
-                "title": {
+                "count": {
+                    "type": "integer"
+                },
+                "row": {
                     "type": "string"
                 }
             }
         },
-        "api_internal_models.Issue": {
+        "github_com_abhir9_issue-board_api_internal_models.Role": {
+            "type": "string",
+            "enum": [
+                "viewer",
+                "member",
+                "maintainer",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "RoleViewer",
+                "RoleMember",
+                "RoleMaintainer",
+                "RoleAdmin"
+            ]
+        },
+        "github_com_abhir9_issue-board_api_internal_models.RoleAssignment": {
             "type": "object",
             "properties": {
-                "assignee": {
-                    "description": "For response population",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/api_internal_models.User"
-                        }
-                    ]
+                "project_id": {
+                    "type": "string"
                 },
-                "assignee_id": {
+                "role": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Role"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.SessionInfo": {
+            "type": "object",
+            "properties": {
+                "csrf_token": {
                     "type": "string"
                 },
-                "created_at": {
+                "expires_at": {
                     "type": "string"
                 },
-                "description": {
+                "user": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.User"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.SetRoleRequest": {
+            "type": "object",
+            "properties": {
+                "project_id": {
                     "type": "string"
                 },
-                "id": {
+                "role": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Role"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.StatGroup": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "assignee name",
                     "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.SyncRequest": {
+            "type": "object",
+            "properties": {
+                "mutations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Mutation"
+                    }
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.SyncResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Tombstone"
+                    }
+                },
+                "full": {
+                    "description": "a snapshot replacing everything the client holds",
+                    "type": "boolean"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Issue"
+                    }
                 },
                 "labels": {
-                    "description": "For response population",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/api_internal_models.Label"
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.Label"
                     }
                 },
-                "order_index": {
-                    "type": "number"
+                "next": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.User"
+                    }
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.SyncResult": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.MutationResult"
+                    }
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.TemplateRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "label_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
                 },
                 "priority": {
-                    "description": "Low, Medium, High, Critical",
                     "type": "string"
                 },
                 "status": {
-                    "description": "Backlog, Todo, In Progress, Done, Canceled",
                     "type": "string"
                 },
-                "title": {
+                "title_prefix": {
                     "type": "string"
                 },
-                "updated_at": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "api_internal_models.Label": {
+        "github_com_abhir9_issue-board_api_internal_models.ThroughputReport": {
             "type": "object",
             "properties": {
-                "color": {
-                    "type": "string"
+                "assignees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_models.AssigneeThroughput"
+                    }
                 },
-                "id": {
+                "from": {
                     "type": "string"
                 },
-                "name": {
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "api_internal_models.MoveIssueRequest": {
+        "github_com_abhir9_issue-board_api_internal_models.Tombstone": {
             "type": "object",
             "properties": {
-                "order_index": {
-                    "type": "number"
+                "deleted_at": {
+                    "type": "string"
                 },
-                "status": {
+                "entity": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_abhir9_issue-board_api_internal_models.UpdateFieldRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
                     "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "api_internal_models.UpdateIssueRequest": {
+        "github_com_abhir9_issue-board_api_internal_models.UpdateIssueRequest": {
             "type": "object",
             "properties": {
                 "assignee_id": {
                     "type": "string"
                 },
+                "custom_fields": {
+                    "description": "CustomFields sets the given fields, by key; null clears one",
+                    "type": "object"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -522,6 +3442,13 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "order_index": {
+                    "type": "number"
+                },
+                "parent_id": {
+                    "description": "\"\" detaches the issue from its parent",
+                    "type": "string"
+                },
                 "priority": {
                     "type": "string"
                 },
@@ -530,10 +3457,13 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "api_internal_models.User": {
+        "github_com_abhir9_issue-board_api_internal_models.User": {
             "type": "object",
             "properties": {
                 "avatar_url": {
@@ -546,6 +3476,109 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "github_com_abhir9_issue-board_api_internal_utils.Code": {
+            "type": "string",
+            "enum": [
+                "INVALID_BODY",
+                "BODY_TOO_LARGE",
+                "VALIDATION_FAILED",
+                "INVALID_QUERY",
+                "UNKNOWN_REFERENCE",
+                "UNAUTHENTICATED",
+                "INVALID_LOGIN",
+                "LOGIN_FAILED",
+                "INVALID_LOGIN_STATE",
+                "CSRF_TOKEN_INVALID",
+                "INSUFFICIENT_SCOPE",
+                "PERMISSION_DENIED",
+                "ROUTE_NOT_FOUND",
+                "METHOD_NOT_ALLOWED",
+                "ISSUE_NOT_FOUND",
+                "USER_NOT_FOUND",
+                "TEMPLATE_NOT_FOUND",
+                "FIELD_NOT_FOUND",
+                "ISSUE_TYPE_NOT_FOUND",
+                "OIDC_NOT_CONFIGURED",
+                "EMAIL_TAKEN",
+                "TEMPLATE_NAME_TAKEN",
+                "FIELD_KEY_TAKEN",
+                "CONFLICT",
+                "CONSTRAINT_VIOLATION",
+                "WIP_LIMIT_EXCEEDED",
+                "RATE_LIMITED",
+                "INVALID_IDEMPOTENCY_KEY",
+                "IDEMPOTENCY_KEY_REUSED",
+                "IDEMPOTENCY_IN_PROGRESS",
+                "SYNC_TOKEN_EXPIRED",
+                "INTERNAL_ERROR",
+                "IDENTITY_PROVIDER_UNAVAILABLE"
+            ],
+            "x-enum-varnames": [
+                "CodeInvalidBody",
+                "CodeBodyTooLarge",
+                "CodeValidationFailed",
+                "CodeInvalidQuery",
+                "CodeUnknownReference",
+                "CodeUnauthenticated",
+                "CodeInvalidLogin",
+                "CodeLoginFailed",
+                "CodeInvalidLoginState",
+                "CodeCSRFTokenInvalid",
+                "CodeInsufficientScope",
+                "CodePermissionDenied",
+                "CodeRouteNotFound",
+                "CodeMethodNotAllowed",
+                "CodeIssueNotFound",
+                "CodeUserNotFound",
+                "CodeTemplateNotFound",
+                "CodeFieldNotFound",
+                "CodeIssueTypeNotFound",
+                "CodeOIDCNotConfigured",
+                "CodeEmailTaken",
+                "CodeTemplateNameTaken",
+                "CodeFieldKeyTaken",
+                "CodeConflict",
+                "CodeConstraintViolation",
+                "CodeWIPLimitExceeded",
+                "CodeRateLimited",
+                "CodeInvalidIdempotencyKey",
+                "CodeIdempotencyKeyReused",
+                "CodeIdempotencyInProgress",
+                "CodeSyncTokenExpired",
+                "CodeInternal",
+                "CodeIdentityProviderDown"
+            ]
+        },
+        "github_com_abhir9_issue-board_api_internal_utils.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/github_com_abhir9_issue-board_api_internal_utils.Code"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -553,6 +3586,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "X-API-Key",
             "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT bearer token: \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
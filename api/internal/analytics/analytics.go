@@ -0,0 +1,269 @@
+// Package analytics computes board metrics from issues and their status
+// history. The functions are pure so they behave the same for every Store.
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// Statuses that count as closed for created-vs-closed charts
+var closedStatuses = models.ClosedStatuses
+
+const (
+	doneStatus       = "Done"
+	inProgressStatus = "In Progress"
+)
+
+// Interval is the width of a time series bucket
+type Interval string
+
+const (
+	Day  Interval = "day"
+	Week Interval = "week"
+)
+
+// ParseInterval parses an interval parameter, defaulting to Day
+func ParseInterval(s string) (Interval, error) {
+	switch Interval(s) {
+	case "", Day:
+		return Day, nil
+	case Week:
+		return Week, nil
+	default:
+		return "", fmt.Errorf("invalid interval %q, allowed: day, week", s)
+	}
+}
+
+// Truncate returns the start of the bucket containing t. Weeks start on Monday.
+func (i Interval) Truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	if i == Week {
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// Next returns the start of the bucket following the one starting at t
+func (i Interval) Next(t time.Time) time.Time {
+	if i == Week {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// Buckets returns the start of every bucket overlapping [from, to)
+func (i Interval) Buckets(from, to time.Time) []time.Time {
+	var starts []time.Time
+	for t := i.Truncate(from); t.Before(to); t = i.Next(t) {
+		starts = append(starts, t)
+	}
+	return starts
+}
+
+// CreatedVsClosed counts issues created and closed per bucket in [from, to).
+// An issue is closed when it moves from an open status to Done or Canceled,
+// or is created closed.
+func CreatedVsClosed(issues []models.Issue, history []models.StatusChange, from, to time.Time, interval Interval) []models.FlowBucket {
+	starts := interval.Buckets(from, to)
+	buckets := make([]models.FlowBucket, len(starts))
+	index := make(map[time.Time]int, len(starts))
+	for i, start := range starts {
+		buckets[i].Start = start
+		index[start] = i
+	}
+
+	bucketOf := func(t time.Time) (int, bool) {
+		if t.Before(from) || !t.Before(to) {
+			return 0, false
+		}
+		i, ok := index[interval.Truncate(t.In(from.Location()))]
+		return i, ok
+	}
+
+	byIssue := groupHistory(history)
+	for _, issue := range issues {
+		if i, ok := bucketOf(issue.CreatedAt); ok {
+			buckets[i].Created++
+		}
+		for _, c := range Timeline(issue, byIssue[issue.ID]) {
+			if !isClosed(c.To) || (c.From != nil && isClosed(*c.From)) {
+				continue
+			}
+			if i, ok := bucketOf(c.ChangedAt); ok {
+				buckets[i].Closed++
+			}
+		}
+	}
+	return buckets
+}
+
+// completion is when an issue last entered Done, if it is currently Done
+type completion struct {
+	issue   models.Issue
+	started *time.Time // first move to In Progress, if any
+	doneAt  time.Time
+}
+
+// completions returns the issues that are Done and were completed in [from, to)
+func completions(issues []models.Issue, history []models.StatusChange, from, to time.Time) []completion {
+	byIssue := groupHistory(history)
+
+	var done []completion
+	for _, issue := range issues {
+		if issue.Status != doneStatus {
+			continue
+		}
+		var c completion
+		found := false
+		for _, change := range Timeline(issue, byIssue[issue.ID]) {
+			at := change.ChangedAt
+			if change.To == inProgressStatus && c.started == nil {
+				c.started = &at
+			}
+			if change.To == doneStatus {
+				c.doneAt = at
+				found = true
+			}
+		}
+		if !found || c.doneAt.Before(from) || !c.doneAt.Before(to) {
+			continue
+		}
+		c.issue = issue
+		done = append(done, c)
+	}
+	return done
+}
+
+// FlowTimes computes cycle and lead time percentiles of the issues completed
+// in [from, to). Cycle time runs from the first move to In Progress to the
+// last move to Done; issues that skipped In Progress only count for lead time.
+func FlowTimes(issues []models.Issue, history []models.StatusChange, from, to time.Time) models.FlowTimes {
+	var cycle, lead []time.Duration
+	for _, c := range completions(issues, history, from, to) {
+		lead = append(lead, c.doneAt.Sub(c.issue.CreatedAt))
+		if c.started != nil && !c.started.After(c.doneAt) {
+			cycle = append(cycle, c.doneAt.Sub(*c.started))
+		}
+	}
+	return models.FlowTimes{From: from, To: to, CycleTime: Summarize(cycle), LeadTime: Summarize(lead)}
+}
+
+// Throughput counts the issues completed in [from, to) per current assignee,
+// busiest first
+func Throughput(issues []models.Issue, history []models.StatusChange, from, to time.Time) []models.AssigneeThroughput {
+	counts := make(map[string]*models.AssigneeThroughput)
+	var result []*models.AssigneeThroughput
+	for _, c := range completions(issues, history, from, to) {
+		key := ""
+		if c.issue.AssigneeID != nil {
+			key = *c.issue.AssigneeID
+		}
+		t, ok := counts[key]
+		if !ok {
+			t = &models.AssigneeThroughput{AssigneeID: c.issue.AssigneeID}
+			if c.issue.Assignee != nil {
+				t.Name = c.issue.Assignee.Name
+			}
+			counts[key] = t
+			result = append(result, t)
+		}
+		t.Completed++
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Completed != result[j].Completed {
+			return result[i].Completed > result[j].Completed
+		}
+		return keyLess(result[i].AssigneeID, result[j].AssigneeID)
+	})
+
+	out := make([]models.AssigneeThroughput, len(result))
+	for i, t := range result {
+		out[i] = *t
+	}
+	return out
+}
+
+// Summarize returns nearest-rank percentiles of durations in hours
+func Summarize(durations []time.Duration) models.Percentiles {
+	p := models.Percentiles{Count: len(durations)}
+	if len(durations) == 0 {
+		return p
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	at := func(pct float64) float64 {
+		rank := int(math.Ceil(pct / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return math.Round(sorted[rank-1].Hours()*100) / 100
+	}
+	p.P50, p.P75, p.P90, p.P95 = at(50), at(75), at(90), at(95)
+	return p
+}
+
+// SortGroups orders the groups of a stats dimension: statuses and priorities
+// in workflow order, other dimensions by key, with nil keys last
+func SortGroups(dim string, groups []models.StatGroup) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return GroupLess(dim, groups[i].Key, groups[j].Key)
+	})
+}
+
+// GroupLess reports whether key a sorts before key b for a stats dimension
+func GroupLess(dim string, a, b *string) bool {
+	var order []string
+	switch dim {
+	case models.GroupByStatus:
+		order = models.ValidStatuses
+	case models.GroupByPriority:
+		order = models.ValidPriorities
+	}
+	if order != nil && a != nil && b != nil {
+		return indexOf(order, *a) < indexOf(order, *b)
+	}
+	return keyLess(a, b)
+}
+
+func keyLess(a, b *string) bool {
+	switch {
+	case a == nil:
+		return false
+	case b == nil:
+		return true
+	default:
+		return *a < *b
+	}
+}
+
+func indexOf(values []string, v string) int {
+	for i, s := range values {
+		if s == v {
+			return i
+		}
+	}
+	return len(values)
+}
+
+// groupHistory splits status changes by issue, keeping their order
+func groupHistory(history []models.StatusChange) map[string][]models.StatusChange {
+	byIssue := make(map[string][]models.StatusChange)
+	for _, c := range history {
+		byIssue[c.IssueID] = append(byIssue[c.IssueID], c)
+	}
+	return byIssue
+}
+
+func isClosed(status string) bool {
+	return indexOf(closedStatuses, status) < len(closedStatuses)
+}
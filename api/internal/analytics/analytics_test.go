@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+var base = time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC) // a Monday
+
+func at(days, hours int) time.Time {
+	return base.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour)
+}
+
+func change(issueID, from, to string, t time.Time) models.StatusChange {
+	c := models.StatusChange{IssueID: issueID, To: to, ChangedAt: t}
+	if from != "" {
+		c.From = &from
+	}
+	return c
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// fixture: issue a went Todo -> In Progress -> Done, b went straight to Done,
+// c was canceled, d is still open
+func fixture() ([]models.Issue, []models.StatusChange) {
+	alice := &models.User{ID: "u1", Name: "Alice"}
+	issues := []models.Issue{
+		{ID: "a", Status: "Done", AssigneeID: ptr("u1"), Assignee: alice, CreatedAt: at(0, 0)},
+		{ID: "b", Status: "Done", AssigneeID: ptr("u1"), Assignee: alice, CreatedAt: at(0, 0)},
+		{ID: "c", Status: "Canceled", CreatedAt: at(1, 0)},
+		{ID: "d", Status: "Todo", CreatedAt: at(8, 0)},
+	}
+	history := []models.StatusChange{
+		change("a", "", "Todo", at(0, 0)),
+		change("a", "Todo", "In Progress", at(1, 0)),
+		change("a", "In Progress", "Done", at(1, 10)),
+		change("b", "", "Todo", at(0, 0)),
+		change("b", "Todo", "Done", at(2, 0)),
+		change("c", "", "Todo", at(1, 0)),
+		change("c", "Todo", "Canceled", at(2, 0)),
+		change("c", "Canceled", "Done", at(2, 1)), // closed to closed does not count twice
+		change("d", "", "Todo", at(8, 0)),
+	}
+	return issues, history
+}
+
+func TestParseInterval(t *testing.T) {
+	for _, tt := range []struct {
+		in      string
+		want    Interval
+		wantErr bool
+	}{
+		{in: "", want: Day},
+		{in: "day", want: Day},
+		{in: "week", want: Week},
+		{in: "month", wantErr: true},
+	} {
+		got, err := ParseInterval(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseInterval(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestIntervalTruncate(t *testing.T) {
+	sunday := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	if got := Day.Truncate(sunday); !got.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected start of day, got %v", got)
+	}
+	if got := Week.Truncate(sunday); !got.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the previous Monday, got %v", got)
+	}
+}
+
+func TestCreatedVsClosed(t *testing.T) {
+	issues, history := fixture()
+
+	t.Run("Daily", func(t *testing.T) {
+		buckets := CreatedVsClosed(issues, history, Day.Truncate(at(0, 0)), Day.Truncate(at(3, 0)), Day)
+		want := []models.FlowBucket{
+			{Start: Day.Truncate(at(0, 0)), Created: 2, Closed: 0},
+			{Start: Day.Truncate(at(1, 0)), Created: 1, Closed: 1},
+			{Start: Day.Truncate(at(2, 0)), Created: 0, Closed: 2},
+		}
+		if len(buckets) != len(want) {
+			t.Fatalf("Expected %d buckets, got %v", len(want), buckets)
+		}
+		for i := range want {
+			if !buckets[i].Start.Equal(want[i].Start) || buckets[i].Created != want[i].Created || buckets[i].Closed != want[i].Closed {
+				t.Errorf("Bucket %d: expected %+v, got %+v", i, want[i], buckets[i])
+			}
+		}
+	})
+
+	t.Run("Weekly", func(t *testing.T) {
+		buckets := CreatedVsClosed(issues, history, at(0, -9), at(14, 0), Week)
+		if len(buckets) != 3 {
+			t.Fatalf("Expected 3 buckets, got %v", buckets)
+		}
+		if buckets[0].Created != 3 || buckets[0].Closed != 3 || buckets[1].Created != 1 || buckets[1].Closed != 0 {
+			t.Errorf("Unexpected weekly buckets: %+v", buckets)
+		}
+	})
+}
+
+func TestFlowTimes(t *testing.T) {
+	issues, history := fixture()
+
+	got := FlowTimes(issues, history, at(0, 0), at(10, 0))
+	if got.CycleTime.Count != 1 || got.CycleTime.P50 != 10 {
+		t.Errorf("Expected a single 10h cycle time, got %+v", got.CycleTime)
+	}
+	if got.LeadTime.Count != 2 || got.LeadTime.P50 != 34 || got.LeadTime.P95 != 48 {
+		t.Errorf("Expected lead times of 34h and 48h, got %+v", got.LeadTime)
+	}
+
+	// Only issues completed within the range count
+	got = FlowTimes(issues, history, at(2, 0), at(10, 0))
+	if got.LeadTime.Count != 1 || got.CycleTime.Count != 0 {
+		t.Errorf("Expected only issue b, got %+v", got)
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	issues, history := fixture()
+	issues = append(issues, models.Issue{ID: "e", Status: "Done", CreatedAt: at(0, 0)})
+	history = append(history, change("e", "Todo", "Done", at(3, 0)))
+
+	got := Throughput(issues, history, at(0, 0), at(10, 0))
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 assignees, got %+v", got)
+	}
+	if got[0].AssigneeID == nil || *got[0].AssigneeID != "u1" || got[0].Name != "Alice" || got[0].Completed != 2 {
+		t.Errorf("Expected Alice with 2 completed first, got %+v", got[0])
+	}
+	if got[1].AssigneeID != nil || got[1].Completed != 1 {
+		t.Errorf("Expected unassigned with 1 completed, got %+v", got[1])
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	if got := Summarize(nil); got.Count != 0 || got.P50 != 0 {
+		t.Errorf("Expected empty summary, got %+v", got)
+	}
+
+	var durations []time.Duration
+	for i := 10; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Hour)
+	}
+	got := Summarize(durations)
+	if got.Count != 10 || got.P50 != 5 || got.P75 != 8 || got.P90 != 9 || got.P95 != 10 {
+		t.Errorf("Unexpected percentiles: %+v", got)
+	}
+}
+
+func TestSortGroups(t *testing.T) {
+	groups := []models.StatGroup{{Key: nil}, {Key: ptr("Done")}, {Key: ptr("Backlog")}, {Key: ptr("In Progress")}}
+	SortGroups(models.GroupByStatus, groups)
+	want := []string{"Backlog", "In Progress", "Done"}
+	for i, w := range want {
+		if groups[i].Key == nil || *groups[i].Key != w {
+			t.Fatalf("Expected workflow order %v then nil, got %+v", want, groups)
+		}
+	}
+	if groups[3].Key != nil {
+		t.Errorf("Expected nil key last, got %v", *groups[3].Key)
+	}
+}
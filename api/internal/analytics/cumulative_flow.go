@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// Status assumed for issues that predate history tracking and have since
+// moved on, between their creation and last update
+const inferredStartStatus = "Todo"
+
+// Timeline returns the status changes of an issue from its creation onward,
+// filling the gaps left by issues that predate history tracking:
+//   - when the first recorded change has a from status, the issue is assumed
+//     to have been in that status since created_at
+//   - without any history, an issue whose updated_at is later than created_at
+//     and whose status is past Todo is assumed to have been in Todo until
+//     updated_at; otherwise it has been in its current status since created_at
+func Timeline(issue models.Issue, changes []models.StatusChange) []models.StatusChange {
+	if len(changes) > 0 {
+		if changes[0].From == nil {
+			return changes
+		}
+		start := models.StatusChange{IssueID: issue.ID, To: *changes[0].From, ChangedAt: issue.CreatedAt}
+		return append([]models.StatusChange{start}, changes...)
+	}
+
+	created := models.StatusChange{IssueID: issue.ID, To: issue.Status, ChangedAt: issue.CreatedAt}
+	if !issue.UpdatedAt.After(issue.CreatedAt) || indexOf(models.ValidStatuses, issue.Status) <= indexOf(models.ValidStatuses, inferredStartStatus) {
+		return []models.StatusChange{created}
+	}
+	from := inferredStartStatus
+	created.To = inferredStartStatus
+	return []models.StatusChange{
+		created,
+		{IssueID: issue.ID, From: &from, To: issue.Status, ChangedAt: issue.UpdatedAt},
+	}
+}
+
+// CumulativeFlow reconstructs how many issues were in each status at the end
+// of every bucket overlapping [from, to). Issues count from their creation on.
+func CumulativeFlow(issues []models.Issue, history []models.StatusChange, from, to time.Time, interval Interval) models.CumulativeFlow {
+	starts := interval.Buckets(from, to)
+	samples := make([]time.Time, len(starts))
+	for i, start := range starts {
+		samples[i] = interval.Next(start)
+		if samples[i].After(to) {
+			samples[i] = to
+		}
+	}
+
+	statusIndex := make(map[string]int, len(models.ValidStatuses))
+	series := make([]models.FlowSeries, len(models.ValidStatuses))
+	for i, status := range models.ValidStatuses {
+		statusIndex[status] = i
+		series[i] = models.FlowSeries{Status: status, Counts: make([]int, len(samples))}
+	}
+
+	byIssue := groupHistory(history)
+	for _, issue := range issues {
+		timeline := Timeline(issue, byIssue[issue.ID])
+		next := 0
+		current := ""
+		for j, sample := range samples {
+			// Status at a sample is the last change at or before it
+			for next < len(timeline) && !timeline[next].ChangedAt.After(sample) {
+				current = timeline[next].To
+				next++
+			}
+			if i, ok := statusIndex[current]; ok {
+				series[i].Counts[j]++
+			}
+		}
+	}
+
+	return models.CumulativeFlow{
+		Interval: string(interval),
+		From:     from,
+		To:       to,
+		Dates:    starts,
+		Series:   series,
+	}
+}
@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestTimeline(t *testing.T) {
+	t.Run("Recorded history", func(t *testing.T) {
+		issue := models.Issue{ID: "a", Status: "Done", CreatedAt: at(0, 0)}
+		changes := []models.StatusChange{change("a", "", "Todo", at(0, 0)), change("a", "Todo", "Done", at(1, 0))}
+		if got := Timeline(issue, changes); len(got) != 2 {
+			t.Errorf("Expected recorded history unchanged, got %+v", got)
+		}
+	})
+
+	t.Run("History started after creation", func(t *testing.T) {
+		issue := models.Issue{ID: "a", Status: "Done", CreatedAt: at(0, 0)}
+		got := Timeline(issue, []models.StatusChange{change("a", "In Progress", "Done", at(2, 0))})
+		if len(got) != 2 || got[0].To != "In Progress" || !got[0].ChangedAt.Equal(at(0, 0)) {
+			t.Errorf("Expected In Progress since creation, got %+v", got)
+		}
+	})
+
+	t.Run("No history, never updated", func(t *testing.T) {
+		issue := models.Issue{ID: "a", Status: "Done", CreatedAt: at(0, 0), UpdatedAt: at(0, 0)}
+		got := Timeline(issue, nil)
+		if len(got) != 1 || got[0].To != "Done" {
+			t.Errorf("Expected Done since creation, got %+v", got)
+		}
+	})
+
+	t.Run("No history, updated later", func(t *testing.T) {
+		issue := models.Issue{ID: "a", Status: "Done", CreatedAt: at(0, 0), UpdatedAt: at(3, 0)}
+		got := Timeline(issue, nil)
+		if len(got) != 2 || got[0].To != "Todo" || got[1].To != "Done" || !got[1].ChangedAt.Equal(at(3, 0)) {
+			t.Errorf("Expected Todo until updated_at then Done, got %+v", got)
+		}
+	})
+
+	t.Run("No history, still in backlog", func(t *testing.T) {
+		issue := models.Issue{ID: "a", Status: "Backlog", CreatedAt: at(0, 0), UpdatedAt: at(3, 0)}
+		got := Timeline(issue, nil)
+		if len(got) != 1 || got[0].To != "Backlog" {
+			t.Errorf("Expected Backlog since creation, got %+v", got)
+		}
+	})
+}
+
+func TestCumulativeFlow(t *testing.T) {
+	issues, history := fixture()
+	// e predates history tracking: created day 0, last updated (to Done) day 1
+	issues = append(issues, models.Issue{ID: "e", Status: "Done", CreatedAt: at(0, 0), UpdatedAt: at(1, 0)})
+
+	from := Day.Truncate(at(0, 0))
+	flow := CumulativeFlow(issues, history, from, from.AddDate(0, 0, 3), Day)
+	if flow.Interval != "day" || len(flow.Dates) != 3 || len(flow.Series) != len(models.ValidStatuses) {
+		t.Fatalf("Unexpected shape: %+v", flow)
+	}
+
+	counts := func(status string) []int {
+		for _, s := range flow.Series {
+			if s.Status == status {
+				return s.Counts
+			}
+		}
+		t.Fatalf("Missing series %s", status)
+		return nil
+	}
+	expect := func(status string, want ...int) {
+		got := counts(status)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: expected %v, got %v", status, want, got)
+				return
+			}
+		}
+	}
+
+	// End of day 0: a, b, e in Todo; day 1: a and e Done, b and c Todo;
+	// day 2: a, b, c (Canceled then Done) and e Done
+	expect("Todo", 3, 2, 0)
+	expect("In Progress", 0, 0, 0)
+	expect("Done", 0, 2, 4)
+	expect("Canceled", 0, 0, 0)
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// JWK is a public JSON Web Key (RFC 7517). RSA and P-256 EC keys are supported.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKey decodes the key
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	b64 := base64.RawURLEncoding
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x: %w", err)
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// Key returns the key with ID kid. An empty kid matches a set with a single key.
+func (s JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	for _, k := range s.Keys {
+		if k.Kid == kid || (kid == "" && len(s.Keys) == 1) {
+			return k.PublicKey()
+		}
+	}
+	return nil, invalidToken("unknown key ID %q", kid)
+}
+
+// minRefreshInterval limits how often an unknown key ID triggers a refetch
+const minRefreshInterval = time.Minute
+
+// RemoteKeySet fetches a JWKS over HTTP and caches it. Unknown key IDs cause a
+// refetch so the issuer can rotate keys.
+type RemoteKeySet struct {
+	URL    string
+	Client *http.Client
+
+	mu        sync.Mutex
+	keys      JWKS
+	fetchedAt time.Time
+}
+
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, err := s.keys.Key(ctx, kid); err == nil {
+		return key, nil
+	}
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < minRefreshInterval {
+		return nil, invalidToken("unknown key ID %q", kid)
+	}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+	return s.keys.Key(ctx, kid)
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) error {
+	var keys JWKS
+	if err := getJSON(ctx, s.Client, s.URL, &keys); err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// FileKeySet reads a JWKS from a file and reloads it whenever the file
+// changes, so keys are rotated by rewriting the file: add the new key, switch
+// signers to its kid, then drop the old key once its tokens have expired.
+type FileKeySet struct {
+	Path string
+
+	mu      sync.Mutex
+	keys    JWKS
+	modTime time.Time
+	size    int64
+}
+
+func (s *FileKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s.keys.Key(ctx, kid)
+}
+
+// Load reads the file if it changed since the last read
+func (s *FileKeySet) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *FileKeySet) load() error {
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	var keys JWKS
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("failed to parse JWKS %s: %w", s.Path, err)
+	}
+	for _, k := range keys.Keys {
+		if _, err := k.PublicKey(); err != nil {
+			return fmt.Errorf("invalid key %q in JWKS %s: %w", k.Kid, s.Path, err)
+		}
+	}
+	s.keys, s.modTime, s.size = keys, info.ModTime(), info.Size()
+	return nil
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
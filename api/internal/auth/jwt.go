@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for malformed, unsigned, expired or otherwise
+// unacceptable tokens. The wrapped message says which check failed.
+var ErrInvalidToken = errors.New("invalid token")
+
+func invalidToken(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidToken, fmt.Sprintf(format, args...))
+}
+
+// Claims is the payload of a JSON Web Token
+type Claims map[string]interface{}
+
+// String returns a string claim, or "" if it is missing or not a string
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Time returns a NumericDate claim such as exp
+func (c Claims) Time(name string) (time.Time, bool) {
+	n, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(n), 0), true
+}
+
+// HasAudience reports whether aud, a string or an array, contains audience
+func (c Claims) HasAudience(audience string) bool {
+	switch aud := c["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// KeySet resolves the key that verifies tokens signed with key ID kid. Keys
+// are *rsa.PublicKey or *ecdsa.PublicKey, or []byte for HS256 secrets.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// Secret is a KeySet holding a single HS256 secret, used whatever the kid
+type Secret []byte
+
+func (s Secret) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	return []byte(s), nil
+}
+
+// Verifier checks the signature and registered claims of JWTs
+type Verifier struct {
+	Keys     KeySet
+	Issuer   string        // required iss, if set
+	Audience string        // required aud entry, if set
+	Leeway   time.Duration // allowed clock skew for exp and nbf
+	Now      func() time.Time
+}
+
+// Verify parses a compact JWT and returns its claims if the signature is
+// valid and exp, nbf, iss and aud are acceptable. Tokens without exp are
+// rejected.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidToken("malformed header")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("malformed signature")
+	}
+
+	key, err := v.Keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidToken("malformed claims")
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) checkClaims(c Claims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	exp, ok := c.Time("exp")
+	if !ok {
+		return invalidToken("missing exp")
+	}
+	if !now.Before(exp.Add(v.Leeway)) {
+		return invalidToken("token expired")
+	}
+	if nbf, ok := c.Time("nbf"); ok && now.Add(v.Leeway).Before(nbf) {
+		return invalidToken("token not valid yet")
+	}
+	if v.Issuer != "" && c.String("iss") != v.Issuer {
+		return invalidToken("unexpected issuer %q", c.String("iss"))
+	}
+	if v.Audience != "" && !c.HasAudience(v.Audience) {
+		return invalidToken("unexpected audience")
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, input string, sig []byte) error {
+	digest := sha256.Sum256([]byte(input))
+	switch alg {
+	case "HS256":
+		k, ok := key.([]byte)
+		if !ok {
+			return invalidToken("key does not match alg %s", alg)
+		}
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return invalidToken("bad signature")
+		}
+	case "RS256":
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidToken("key does not match alg %s", alg)
+		}
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) != nil {
+			return invalidToken("bad signature")
+		}
+	case "ES256":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok || k.Curve != elliptic.P256() {
+			return invalidToken("key does not match alg %s", alg)
+		}
+		if len(sig) != 64 {
+			return invalidToken("bad signature")
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return invalidToken("bad signature")
+		}
+	default:
+		return invalidToken("unsupported alg %q", alg)
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
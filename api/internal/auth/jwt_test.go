@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sign builds a compact JWT signed with an RSA or P-256 key
+func sign(t *testing.T, key crypto.Signer, alg, kid string, claims Claims) string {
+	t.Helper()
+	b64 := base64.RawURLEncoding
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return input + "." + b64.EncodeToString(sig)
+}
+
+// signHS256 builds a compact JWT signed with an HMAC secret
+func signHS256(secret []byte, kid string, claims Claims) string {
+	b64 := base64.RawURLEncoding
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + b64.EncodeToString(mac.Sum(nil))
+}
+
+func rsaJWK(kid string, k *rsa.PublicKey) JWK {
+	b64 := base64.RawURLEncoding
+	return JWK{Kty: "RSA", Kid: kid, N: b64.EncodeToString(k.N.Bytes()), E: b64.EncodeToString(big.NewInt(int64(k.E)).Bytes())}
+}
+
+func ecJWK(kid string, k *ecdsa.PublicKey) JWK {
+	b64 := base64.RawURLEncoding
+	return JWK{Kty: "EC", Kid: kid, Crv: "P-256", X: b64.EncodeToString(k.X.Bytes()), Y: b64.EncodeToString(k.Y.Bytes())}
+}
+
+func TestVerifier(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	v := &Verifier{
+		Keys:     JWKS{Keys: []JWK{rsaJWK("rsa", &rsaKey.PublicKey), ecJWK("ec", &ecKey.PublicKey)}},
+		Issuer:   "https://issuer.example.com",
+		Audience: "board",
+		Leeway:   time.Minute,
+		Now:      func() time.Time { return now },
+	}
+	claims := func(overrides Claims) Claims {
+		c := Claims{
+			"iss": "https://issuer.example.com",
+			"aud": []interface{}{"other", "board"},
+			"sub": "user-1",
+			"exp": float64(now.Add(time.Hour).Unix()),
+		}
+		for k, val := range overrides {
+			if val == nil {
+				delete(c, k)
+			} else {
+				c[k] = val
+			}
+		}
+		return c
+	}
+
+	t.Run("Valid Tokens", func(t *testing.T) {
+		for _, token := range []string{
+			sign(t, rsaKey, "RS256", "rsa", claims(nil)),
+			sign(t, ecKey, "ES256", "ec", claims(nil)),
+		} {
+			got, err := v.Verify(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Verify() failed: %v", err)
+			}
+			if got.String("sub") != "user-1" {
+				t.Errorf("Expected sub user-1, got %v", got)
+			}
+		}
+	})
+
+	t.Run("Rejected Tokens", func(t *testing.T) {
+		otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		valid := sign(t, rsaKey, "RS256", "rsa", claims(nil))
+		payload := strings.Split(valid, ".")[1]
+		unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa"}`)) + "." + payload + "."
+
+		for name, token := range map[string]string{
+			"expired":            sign(t, rsaKey, "RS256", "rsa", claims(Claims{"exp": float64(now.Add(-2 * time.Minute).Unix())})),
+			"no exp":             sign(t, rsaKey, "RS256", "rsa", claims(Claims{"exp": nil})),
+			"not yet valid":      sign(t, rsaKey, "RS256", "rsa", claims(Claims{"nbf": float64(now.Add(2 * time.Minute).Unix())})),
+			"wrong issuer":       sign(t, rsaKey, "RS256", "rsa", claims(Claims{"iss": "https://evil.example.com"})),
+			"wrong audience":     sign(t, rsaKey, "RS256", "rsa", claims(Claims{"aud": "other"})),
+			"wrong key":          sign(t, otherKey, "RS256", "rsa", claims(nil)),
+			"unknown kid":        sign(t, rsaKey, "RS256", "missing", claims(nil)),
+			"alg mismatch":       sign(t, rsaKey, "ES256", "rsa", claims(nil)),
+			"alg none":           unsigned,
+			"malformed":          "not-a-jwt",
+			"tampered signature": valid[:len(valid)-4] + "AAAA",
+		} {
+			if _, err := v.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Leeway", func(t *testing.T) {
+		token := sign(t, rsaKey, "RS256", "rsa", claims(Claims{"exp": float64(now.Add(-30 * time.Second).Unix())}))
+		if _, err := v.Verify(context.Background(), token); err != nil {
+			t.Errorf("Expected token within leeway to pass, got %v", err)
+		}
+	})
+}
+
+func TestVerifierHS256(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	v := &Verifier{Keys: Secret(secret), Audience: "board"}
+
+	if _, err := v.Verify(context.Background(), signHS256(secret, "", Claims{"aud": "board", "exp": exp})); err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"wrong secret":   signHS256([]byte("another secret"), "", Claims{"aud": "board", "exp": exp}),
+		"wrong audience": signHS256(secret, "", Claims{"aud": "other", "exp": exp}),
+		"RS256":          sign(t, rsaKey, "RS256", "", Claims{"aud": "board", "exp": exp}),
+	} {
+		if _, err := v.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	// A public key published in a JWKS must not be usable as an HMAC secret
+	rsaOnly := &Verifier{Keys: JWKS{Keys: []JWK{rsaJWK("rsa", &rsaKey.PublicKey)}}}
+	if _, err := rsaOnly.Verify(context.Background(), signHS256([]byte("guess"), "rsa", Claims{"exp": exp})); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected HS256 token against an RSA key to fail, got %v", err)
+	}
+}
+
+func TestFileKeySetRotation(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	version := 0
+	write := func(keys ...JWK) {
+		t.Helper()
+		data, _ := json.Marshal(JWKS{Keys: keys})
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("Failed to write JWKS: %v", err)
+		}
+		// Make the change visible on filesystems with coarse timestamps
+		version++
+		next := time.Now().Add(time.Duration(version) * time.Second)
+		os.Chtimes(path, next, next)
+	}
+
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	oldToken := sign(t, oldKey, "RS256", "2024-01", Claims{"exp": exp})
+	newToken := sign(t, newKey, "ES256", "2024-02", Claims{"exp": exp})
+
+	keys := &FileKeySet{Path: path}
+	v := &Verifier{Keys: keys}
+	verify := func(token string) error {
+		_, err := v.Verify(context.Background(), token)
+		return err
+	}
+
+	write(rsaJWK("2024-01", &oldKey.PublicKey))
+	if err := keys.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if err := verify(oldToken); err != nil {
+		t.Errorf("Expected old key to verify, got %v", err)
+	}
+	if err := verify(newToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected unknown kid to fail, got %v", err)
+	}
+
+	write(rsaJWK("2024-01", &oldKey.PublicKey), ecJWK("2024-02", &newKey.PublicKey))
+	if verify(oldToken) != nil || verify(newToken) != nil {
+		t.Error("Expected both keys to verify during rotation")
+	}
+
+	write(ecJWK("2024-02", &newKey.PublicKey))
+	if err := verify(oldToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected retired key to fail, got %v", err)
+	}
+	if err := verify(newToken); err != nil {
+		t.Errorf("Expected new key to verify, got %v", err)
+	}
+}
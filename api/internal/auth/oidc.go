@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OIDCConfig configures a relying party of an OpenID Connect issuer
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // optional for public clients, which rely on PKCE
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Provider runs the authorization-code flow with PKCE against an OIDC issuer.
+// The issuer's endpoints are discovered on first use.
+type Provider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	meta     *providerMetadata
+	verifier *Verifier
+}
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider creates a Provider. client defaults to http.DefaultClient.
+func NewProvider(cfg OIDCConfig, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// discover fetches and caches the issuer's metadata
+func (p *Provider) discover(ctx context.Context) (*providerMetadata, *Verifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, p.verifier, nil
+	}
+
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	var meta providerMetadata
+	if err := getJSON(ctx, p.client, issuer+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, nil, fmt.Errorf("failed to discover OIDC issuer: %w", err)
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != issuer {
+		return nil, nil, fmt.Errorf("OIDC discovery returned issuer %q, expected %q", meta.Issuer, p.cfg.Issuer)
+	}
+
+	p.meta = &meta
+	p.verifier = &Verifier{
+		Keys:     &RemoteKeySet{URL: meta.JWKSURI, Client: p.client},
+		Issuer:   meta.Issuer,
+		Audience: p.cfg.ClientID,
+		Leeway:   time.Minute,
+	}
+	return p.meta, p.verifier, nil
+}
+
+// AuthCodeURL returns the issuer URL that starts a login. codeVerifier is
+// kept by the caller and sent back in Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	meta, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {PKCEChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the verified ID token
+// claims. The token's nonce must match the one sent in AuthCodeURL.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Claims, error) {
+	meta, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	claims, err := verifier.Verify(ctx, body.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.String("nonce") != nonce {
+		return nil, invalidToken("nonce mismatch")
+	}
+	if claims.String("sub") == "" {
+		return nil, invalidToken("missing sub")
+	}
+	return claims, nil
+}
+
+// LoginState is what a pending login remembers between redirect and callback
+type LoginState struct {
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
+
+// LoginStates holds pending logins by state parameter. They live in process
+// memory, so the callback must reach the instance that started the login.
+type LoginStates struct {
+	TTL time.Duration
+
+	mu     sync.Mutex
+	states map[string]LoginState
+}
+
+// Start creates a pending login and returns its state parameter
+func (s *LoginStates) Start() (string, LoginState, error) {
+	state, err := NewToken()
+	if err != nil {
+		return "", LoginState{}, err
+	}
+	verifier, err := NewToken()
+	if err != nil {
+		return "", LoginState{}, err
+	}
+	nonce, err := NewToken()
+	if err != nil {
+		return "", LoginState{}, err
+	}
+
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = 10 * time.Minute
+	}
+	ls := LoginState{CodeVerifier: verifier, Nonce: nonce, ExpiresAt: time.Now().Add(ttl)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = make(map[string]LoginState)
+	}
+	for k, v := range s.states {
+		if time.Now().After(v.ExpiresAt) {
+			delete(s.states, k)
+		}
+	}
+	s.states[state] = ls
+	return state, ls, nil
+}
+
+// Take returns and removes a pending login. Each state can be used once.
+func (s *LoginStates) Take(state string) (LoginState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ls, ok := s.states[state]
+	delete(s.states, state)
+	if !ok || time.Now().After(ls.ExpiresAt) {
+		return LoginState{}, false
+	}
+	return ls, true
+}
@@ -0,0 +1,126 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/auth/mockidp"
+)
+
+// authorize follows the issuer's redirect and returns the callback parameters
+func authorize(t *testing.T, authURL string) url.Values {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("Authorization request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Expected redirect from issuer, got %d", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Invalid callback URL: %v", err)
+	}
+	return callback.Query()
+}
+
+func TestProviderAuthCodeFlow(t *testing.T) {
+	idp := mockidp.New("board")
+	defer idp.Close()
+	idp.SetUser(mockidp.User{Subject: "alice", Email: "alice@example.com", Name: "Alice"})
+
+	p := auth.NewProvider(auth.OIDCConfig{
+		Issuer:      idp.Issuer(),
+		ClientID:    "board",
+		RedirectURL: "http://localhost:8080/api/auth/oidc/callback",
+	}, idp.Client())
+	ctx := context.Background()
+
+	start := func(t *testing.T) (code, verifier, nonce string) {
+		var states auth.LoginStates
+		state, pending, err := states.Start()
+		if err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+		authURL, err := p.AuthCodeURL(ctx, state, pending.Nonce, pending.CodeVerifier)
+		if err != nil {
+			t.Fatalf("AuthCodeURL() failed: %v", err)
+		}
+		params := authorize(t, authURL)
+		if params.Get("state") != state {
+			t.Fatalf("Expected state to round-trip, got %q", params.Get("state"))
+		}
+		return params.Get("code"), pending.CodeVerifier, pending.Nonce
+	}
+
+	t.Run("Exchange", func(t *testing.T) {
+		code, verifier, nonce := start(t)
+		claims, err := p.Exchange(ctx, code, verifier, nonce)
+		if err != nil {
+			t.Fatalf("Exchange() failed: %v", err)
+		}
+		if claims.String("sub") != "alice" || claims.String("email") != "alice@example.com" {
+			t.Errorf("Unexpected claims: %v", claims)
+		}
+
+		// Codes are single use
+		if _, err := p.Exchange(ctx, code, verifier, nonce); err == nil {
+			t.Error("Expected a reused code to fail")
+		}
+	})
+
+	t.Run("Wrong PKCE Verifier", func(t *testing.T) {
+		code, _, nonce := start(t)
+		if _, err := p.Exchange(ctx, code, "wrong-verifier", nonce); err == nil {
+			t.Error("Expected PKCE verification to fail")
+		}
+	})
+
+	t.Run("Nonce Mismatch", func(t *testing.T) {
+		code, verifier, _ := start(t)
+		if _, err := p.Exchange(ctx, code, verifier, "other-nonce"); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Unknown Client", func(t *testing.T) {
+		other := auth.NewProvider(auth.OIDCConfig{Issuer: idp.Issuer(), ClientID: "someone-else", RedirectURL: "http://localhost/cb"}, idp.Client())
+		authURL, err := other.AuthCodeURL(ctx, "s", "n", "v")
+		if err != nil {
+			t.Fatalf("AuthCodeURL() failed: %v", err)
+		}
+		resp, err := idp.Client().Get(authURL)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected unknown client to be rejected, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestLoginStates(t *testing.T) {
+	var states auth.LoginStates
+	state, pending, err := states.Start()
+	if err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+
+	got, ok := states.Take(state)
+	if !ok || got.CodeVerifier != pending.CodeVerifier || got.Nonce != pending.Nonce {
+		t.Errorf("Expected pending login, got %+v, %v", got, ok)
+	}
+	if _, ok := states.Take(state); ok {
+		t.Error("Expected state to be single use")
+	}
+	if _, ok := states.Take("unknown"); ok {
+		t.Error("Expected unknown state to be rejected")
+	}
+}
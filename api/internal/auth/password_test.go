@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHashing(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$") {
+		t.Errorf("Expected an argon2id PHC string, got %q", hash)
+	}
+
+	other, _ := HashPassword("correct horse")
+	if other == hash {
+		t.Error("Expected a random salt per hash")
+	}
+
+	if ok, err := CheckPassword(hash, "correct horse"); !ok || err != nil {
+		t.Errorf("Expected password to match, got %v, %v", ok, err)
+	}
+	if ok, err := CheckPassword(hash, "wrong horse"); ok || err != nil {
+		t.Errorf("Expected mismatch, got %v, %v", ok, err)
+	}
+}
+
+func TestCheckPasswordBcrypt(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("legacy"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt failed: %v", err)
+	}
+
+	if ok, err := CheckPassword(string(hash), "legacy"); !ok || err != nil {
+		t.Errorf("Expected bcrypt password to match, got %v, %v", ok, err)
+	}
+	if ok, err := CheckPassword(string(hash), "other"); ok || err != nil {
+		t.Errorf("Expected bcrypt mismatch, got %v, %v", ok, err)
+	}
+}
+
+func TestCheckPasswordUnknownFormat(t *testing.T) {
+	for _, hash := range []string{"", "plaintext", "$argon2id$v=19$broken", "$argon2id$v=18$m=1,t=1,p=1$c2FsdA$a2V5"} {
+		if ok, err := CheckPassword(hash, "plaintext"); ok || err == nil {
+			t.Errorf("CheckPassword(%q) = %v, %v; want an error", hash, ok, err)
+		}
+	}
+}
@@ -2,30 +2,43 @@ package config
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Auth     AuthConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Auth        AuthConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	Trash       TrashConfig
+	Archive     ArchiveConfig
+	WIP         models.WIPLimits
 }
 
 type ServerConfig struct {
-	Port              string
-	Host              string
-	ReadTimeout       time.Duration
-	WriteTimeout      time.Duration
-	ShutdownTimeout   time.Duration
-	EnableKeepAlive   bool
-	KeepAliveURL      string
-	AllowedOrigins    []string
+	Port            string
+	Host            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownTimeout time.Duration
+	EnableKeepAlive bool
+	KeepAliveURL    string
+	AllowedOrigins  []string
+	MaxBodyBytes    int64 // larger request bodies are rejected with 413
 }
 
 type DatabaseConfig struct {
+	Driver          string // sqlite, postgres or memory
 	Path            string
+	URL             string // PostgreSQL connection string
 	MigrationDir    string
 	MaxOpenConns    int
 	MaxIdleConns    int
@@ -33,7 +46,85 @@ type DatabaseConfig struct {
 }
 
 type AuthConfig struct {
-	APIKey string
+	APIKey     string
+	APIKeyRole models.Role // role of requests authenticated with APIKey
+	Session    SessionConfig
+	OIDC       OIDCConfig
+	JWT        JWTConfig
+}
+
+// SessionConfig configures browser sessions created at login
+type SessionConfig struct {
+	TTL          time.Duration
+	SecureCookie bool
+	SameSite     http.SameSite
+	DefaultRole  models.Role // role of users without a role assignment
+}
+
+// OIDCConfig configures login with an external OpenID Connect issuer
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // this API's /api/auth/oidc/callback URL
+	PostLoginURL string // where the browser is sent after login
+	Scopes       []string
+}
+
+// Enabled reports whether OIDC login is configured
+func (c OIDCConfig) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// RateLimitConfig sets per-caller request budgets. A zero budget disables
+// limiting of that kind of request. The IP budgets are spent before
+// authentication, by every caller sharing an address.
+type RateLimitConfig struct {
+	Read    int // GET requests per Window
+	Write   int // POST, PUT, PATCH and DELETE requests per Window
+	IPRead  int // GET requests per Window and client IP
+	IPWrite int // other requests per Window and client IP
+	Window  time.Duration
+	MaxKeys int // callers tracked at once
+}
+
+// IdempotencyConfig sets how long responses to POST requests with an
+// Idempotency-Key are kept for replay. A zero TTL disables replays.
+type IdempotencyConfig struct {
+	TTL     time.Duration
+	MaxKeys int // responses kept at once
+}
+
+// TrashConfig sets how long deleted issues stay restorable. A background job
+// purges older ones every PurgeInterval; a zero Retention keeps them forever.
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// ArchiveConfig sets when closed issues leave the board. A background job
+// archives those closed for longer than After every Interval. After is zero,
+// and the job off, unless the operator sets it.
+type ArchiveConfig struct {
+	After    time.Duration
+	Interval time.Duration
+}
+
+// JWTConfig configures bearer token authentication for service callers.
+// Tokens are verified with either an HS256 secret or the RS256/ES256 keys of
+// a JWKS file.
+type JWTConfig struct {
+	Secret      string
+	JWKSFile    string
+	Issuer      string
+	Audience    string
+	Leeway      time.Duration
+	DefaultRole models.Role // role of subjects without a role assignment
+}
+
+// Enabled reports whether bearer tokens are accepted
+func (c JWTConfig) Enabled() bool {
+	return c.Secret != "" || c.JWKSFile != ""
 }
 
 // Load loads configuration from environment variables with defaults
@@ -48,27 +139,156 @@ func Load() (*Config, error) {
 			EnableKeepAlive: getEnv("ENABLE_KEEP_ALIVE", "false") == "true" || getEnv("RENDER", "") != "",
 			KeepAliveURL:    getKeepAliveURL(),
 			AllowedOrigins:  getAllowedOrigins(),
+			MaxBodyBytes:    int64(getInt("MAX_BODY_BYTES", 1<<20)),
 		},
 		Database: DatabaseConfig{
+			Driver:          getEnv("STORAGE", "sqlite"),
 			Path:            getEnv("DATABASE_PATH", "./issues.db"),
+			URL:             getEnv("DATABASE_URL", ""),
 			MigrationDir:    getEnv("MIGRATION_DIR", "./migrations"),
 			MaxOpenConns:    getInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getInt("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 		},
+		RateLimit: RateLimitConfig{
+			Read:    getInt("RATE_LIMIT_READ", 600),
+			Write:   getInt("RATE_LIMIT_WRITE", 60),
+			IPRead:  getInt("RATE_LIMIT_IP_READ", 3000),
+			IPWrite: getInt("RATE_LIMIT_IP_WRITE", 300),
+			Window:  getDuration("RATE_LIMIT_WINDOW", time.Minute),
+			MaxKeys: getInt("RATE_LIMIT_MAX_KEYS", 10000),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:     getDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+			MaxKeys: getInt("IDEMPOTENCY_MAX_KEYS", 10000),
+		},
+		Trash: TrashConfig{
+			Retention:     getDuration("TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval: getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		},
+		Archive: ArchiveConfig{
+			After:    getDuration("ARCHIVE_AFTER", 0),
+			Interval: getDuration("ARCHIVE_INTERVAL", time.Hour),
+		},
 		Auth: AuthConfig{
 			APIKey: getEnv("API_KEY", ""),
+			Session: SessionConfig{
+				TTL:          getDuration("SESSION_TTL", 24*time.Hour),
+				SecureCookie: getEnv("SESSION_COOKIE_SECURE", "true") == "true",
+			},
+			OIDC: OIDCConfig{
+				Issuer:       getEnv("OIDC_ISSUER", ""),
+				ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+				ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+				PostLoginURL: getEnv("OIDC_POST_LOGIN_URL", "/"),
+				Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "openid profile email")),
+			},
+			JWT: JWTConfig{
+				Secret:   getEnv("JWT_HS256_SECRET", ""),
+				JWKSFile: getEnv("JWT_JWKS_FILE", ""),
+				Issuer:   getEnv("JWT_ISSUER", ""),
+				Audience: getEnv("JWT_AUDIENCE", ""),
+				Leeway:   getDuration("JWT_LEEWAY", 30*time.Second),
+			},
 		},
 	}
 
+	var err error
+	if cfg.Server.MaxBodyBytes <= 0 {
+		return nil, fmt.Errorf("MAX_BODY_BYTES must be positive")
+	}
+	if cfg.RateLimit.Read < 0 || cfg.RateLimit.Write < 0 || cfg.RateLimit.IPRead < 0 || cfg.RateLimit.IPWrite < 0 || cfg.RateLimit.Window <= 0 {
+		return nil, fmt.Errorf("RATE_LIMIT_READ, RATE_LIMIT_WRITE, RATE_LIMIT_IP_READ and RATE_LIMIT_IP_WRITE must not be negative and RATE_LIMIT_WINDOW must be positive")
+	}
+	if cfg.Idempotency.TTL < 0 || cfg.Idempotency.MaxKeys <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_TTL must not be negative and IDEMPOTENCY_MAX_KEYS must be positive")
+	}
+	if cfg.Trash.Retention < 0 || cfg.Trash.PurgeInterval <= 0 {
+		return nil, fmt.Errorf("TRASH_RETENTION must not be negative and TRASH_PURGE_INTERVAL must be positive")
+	}
+	if cfg.Archive.After < 0 || cfg.Archive.Interval <= 0 {
+		return nil, fmt.Errorf("ARCHIVE_AFTER must not be negative and ARCHIVE_INTERVAL must be positive")
+	}
+	if cfg.WIP.Status, err = getLimits("WIP_LIMITS"); err != nil {
+		return nil, err
+	}
+	if cfg.WIP.PerAssignee, err = getLimits("WIP_ASSIGNEE_LIMITS"); err != nil {
+		return nil, err
+	}
+	cfg.WIP.Strict = getEnv("WIP_STRICT", "false") == "true"
+
+	if cfg.Auth.APIKeyRole, err = models.ParseRole(getEnv("API_KEY_ROLE", string(models.RoleAdmin))); err != nil {
+		return nil, fmt.Errorf("invalid API_KEY_ROLE: %w", err)
+	}
+	if cfg.Auth.Session.DefaultRole, err = models.ParseRole(getEnv("DEFAULT_USER_ROLE", string(models.RoleViewer))); err != nil {
+		return nil, fmt.Errorf("invalid DEFAULT_USER_ROLE: %w", err)
+	}
+	if cfg.Auth.Session.SameSite, err = getSameSite("SESSION_COOKIE_SAMESITE"); err != nil {
+		return nil, err
+	}
+	if cfg.Auth.Session.SameSite == http.SameSiteNoneMode && !cfg.Auth.Session.SecureCookie {
+		return nil, fmt.Errorf("SESSION_COOKIE_SAMESITE=none requires SESSION_COOKIE_SECURE=true")
+	}
+	if cfg.Auth.OIDC.Enabled() && (cfg.Auth.OIDC.ClientID == "" || cfg.Auth.OIDC.RedirectURL == "") {
+		return nil, fmt.Errorf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+	}
+	if cfg.Auth.JWT.DefaultRole, err = models.ParseRole(getEnv("JWT_DEFAULT_ROLE", string(models.RoleViewer))); err != nil {
+		return nil, fmt.Errorf("invalid JWT_DEFAULT_ROLE: %w", err)
+	}
+	if jwt := cfg.Auth.JWT; jwt.Enabled() {
+		switch {
+		case jwt.Secret != "" && jwt.JWKSFile != "":
+			return nil, fmt.Errorf("set either JWT_HS256_SECRET or JWT_JWKS_FILE, not both")
+		case jwt.Secret != "" && len(jwt.Secret) < 32:
+			return nil, fmt.Errorf("JWT_HS256_SECRET must be at least 32 bytes")
+		case jwt.Issuer == "" || jwt.Audience == "":
+			return nil, fmt.Errorf("JWT_ISSUER and JWT_AUDIENCE are required when JWT authentication is enabled")
+		}
+	}
+
+	// Session cookies are sent cross-origin, so origins must be explicit
+	for _, origin := range cfg.Server.AllowedOrigins {
+		if origin == "*" {
+			return nil, fmt.Errorf("ALLOWED_ORIGINS must list origins explicitly: credentials are allowed, so \"*\" is not")
+		}
+	}
+
 	// Validate required fields
 	if cfg.Auth.APIKey == "" {
 		return nil, fmt.Errorf("API_KEY environment variable is required")
 	}
 
+	switch cfg.Database.Driver {
+	case "sqlite", "memory":
+	case "postgres":
+		if cfg.Database.URL == "" {
+			return nil, fmt.Errorf("DATABASE_URL environment variable is required for postgres storage")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE %q: must be sqlite, postgres or memory", cfg.Database.Driver)
+	}
+
 	return cfg, nil
 }
 
+// DSN returns the data source name for the configured driver
+func (d DatabaseConfig) DSN() string {
+	if d.Driver == "postgres" {
+		return d.URL
+	}
+	return d.Path
+}
+
+// MigrationPath returns the dialect-specific migration directory, e.g. ./migrations/sqlite
+func (d DatabaseConfig) MigrationPath() string {
+	driver := d.Driver
+	if driver == "" {
+		driver = "sqlite"
+	}
+	return filepath.Join(d.MigrationDir, driver)
+}
+
 func getEnv(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -100,6 +320,47 @@ func getDuration(key string, defaultVal time.Duration) time.Duration {
 	return duration
 }
 
+// getSameSite parses a SameSite cookie mode, defaulting to lax
+func getSameSite(key string) (http.SameSite, error) {
+	switch strings.ToLower(getEnv(key, "lax")) {
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, fmt.Errorf("invalid %s: must be lax, strict or none", key)
+	}
+}
+
+// getLimits parses per-status limits such as "In Progress=3,Todo=10"
+func getLimits(key string) (map[string]int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil, nil
+	}
+
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(val, ",") {
+		status, limit, ok := strings.Cut(entry, "=")
+		status = strings.TrimSpace(status)
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if !ok || err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid %s entry %q: expected Status=N with N >= 1", key, entry)
+		}
+		valid := false
+		for _, s := range models.ValidStatuses {
+			valid = valid || s == status
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid %s status %q: must be one of %v", key, status, models.ValidStatuses)
+		}
+		limits[status] = n
+	}
+	return limits, nil
+}
+
 func getKeepAliveURL() string {
 	if url := os.Getenv("RENDER_EXTERNAL_URL"); url != "" {
 		return url
@@ -117,5 +378,11 @@ func getAllowedOrigins() []string {
 		return []string{"http://localhost:3000", "https://issue-board-front.netlify.app"}
 	}
 	// Split by comma if multiple origins
-	return []string{origins}
+	var list []string
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			list = append(list, origin)
+		}
+	}
+	return list
 }
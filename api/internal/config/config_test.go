@@ -4,6 +4,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
 )
 
 func TestLoad(t *testing.T) {
@@ -72,6 +74,10 @@ func TestLoad(t *testing.T) {
 		if cfg.Database.Path != "./issues.db" {
 			t.Errorf("Expected default db path './issues.db', got '%s'", cfg.Database.Path)
 		}
+
+		if cfg.Server.MaxBodyBytes != 1<<20 {
+			t.Errorf("Expected default max body size 1MiB, got %d", cfg.Server.MaxBodyBytes)
+		}
 	})
 
 	t.Run("Load with custom database settings", func(t *testing.T) {
@@ -99,6 +105,262 @@ func TestLoad(t *testing.T) {
 	})
 }
 
+func TestLoadStorage(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	t.Run("Defaults to sqlite", func(t *testing.T) {
+		t.Setenv("STORAGE", "")
+		t.Setenv("MIGRATION_DIR", "")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.Database.Driver != "sqlite" {
+			t.Errorf("Expected driver 'sqlite', got '%s'", cfg.Database.Driver)
+		}
+		if cfg.Database.DSN() != cfg.Database.Path {
+			t.Errorf("Expected sqlite DSN to be the database path, got '%s'", cfg.Database.DSN())
+		}
+		if cfg.Database.MigrationPath() != "migrations/sqlite" {
+			t.Errorf("Expected migration path 'migrations/sqlite', got '%s'", cfg.Database.MigrationPath())
+		}
+	})
+
+	t.Run("Postgres", func(t *testing.T) {
+		t.Setenv("STORAGE", "postgres")
+		t.Setenv("DATABASE_URL", "postgres://localhost/issues")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.Database.DSN() != "postgres://localhost/issues" {
+			t.Errorf("Expected postgres DSN to be DATABASE_URL, got '%s'", cfg.Database.DSN())
+		}
+		if cfg.Database.MigrationPath() != "migrations/postgres" {
+			t.Errorf("Expected migration path 'migrations/postgres', got '%s'", cfg.Database.MigrationPath())
+		}
+	})
+
+	t.Run("Postgres requires DATABASE_URL", func(t *testing.T) {
+		t.Setenv("STORAGE", "postgres")
+		t.Setenv("DATABASE_URL", "")
+
+		if _, err := Load(); err == nil {
+			t.Error("Expected error when DATABASE_URL is missing")
+		}
+	})
+
+	t.Run("Unsupported storage", func(t *testing.T) {
+		t.Setenv("STORAGE", "mysql")
+
+		if _, err := Load(); err == nil {
+			t.Error("Expected error for unsupported storage")
+		}
+	})
+}
+
+func TestLoadAPIKeyRole(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Auth.APIKeyRole != models.RoleAdmin {
+		t.Errorf("Expected default role 'admin', got '%s'", cfg.Auth.APIKeyRole)
+	}
+
+	t.Setenv("API_KEY_ROLE", "viewer")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Auth.APIKeyRole != models.RoleViewer {
+		t.Errorf("Expected role 'viewer', got '%s'", cfg.Auth.APIKeyRole)
+	}
+
+	t.Setenv("API_KEY_ROLE", "owner")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for unknown role")
+	}
+}
+
+func TestLoadRateLimit(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	want := RateLimitConfig{Read: 600, Write: 60, IPRead: 3000, IPWrite: 300, Window: time.Minute, MaxKeys: 10000}
+	if cfg.RateLimit != want {
+		t.Errorf("Expected default rate limits %+v, got %+v", want, cfg.RateLimit)
+	}
+
+	t.Setenv("RATE_LIMIT_WRITE", "0")
+	t.Setenv("RATE_LIMIT_WINDOW", "10s")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.RateLimit.Write != 0 || cfg.RateLimit.Window != 10*time.Second {
+		t.Errorf("Unexpected rate limits: %+v", cfg.RateLimit)
+	}
+
+	t.Setenv("RATE_LIMIT_IP_WRITE", "-1")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a negative IP budget")
+	}
+
+	t.Setenv("RATE_LIMIT_IP_WRITE", "0")
+	t.Setenv("RATE_LIMIT_READ", "-1")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a negative budget")
+	}
+}
+
+func TestLoadIdempotency(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	want := IdempotencyConfig{TTL: 24 * time.Hour, MaxKeys: 10000}
+	if cfg.Idempotency != want {
+		t.Errorf("Expected default idempotency settings %+v, got %+v", want, cfg.Idempotency)
+	}
+
+	t.Setenv("IDEMPOTENCY_TTL", "-1h")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a negative TTL")
+	}
+}
+
+func TestLoadTrash(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	want := TrashConfig{Retention: 30 * 24 * time.Hour, PurgeInterval: time.Hour}
+	if cfg.Trash != want {
+		t.Errorf("Expected default trash settings %+v, got %+v", want, cfg.Trash)
+	}
+
+	t.Setenv("TRASH_RETENTION", "0")
+	if cfg, err := Load(); err != nil || cfg.Trash.Retention != 0 {
+		t.Errorf("Expected a zero retention to be allowed, got %v", err)
+	}
+
+	t.Setenv("TRASH_PURGE_INTERVAL", "0s")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a zero purge interval")
+	}
+}
+
+func TestLoadArchive(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	want := ArchiveConfig{After: 0, Interval: time.Hour}
+	if cfg.Archive != want {
+		t.Errorf("Expected auto-archiving to be off by default, got %+v", cfg.Archive)
+	}
+
+	t.Setenv("ARCHIVE_AFTER", "336h")
+	if cfg, err := Load(); err != nil || cfg.Archive.After != 14*24*time.Hour {
+		t.Errorf("Expected auto-archiving after 336h, got %v", err)
+	}
+
+	t.Setenv("ARCHIVE_AFTER", "-1h")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a negative ARCHIVE_AFTER")
+	}
+}
+
+func TestLoadJWT(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Auth.JWT.Enabled() {
+		t.Error("Expected JWT authentication to be disabled by default")
+	}
+
+	t.Setenv("JWT_HS256_SECRET", "0123456789abcdef0123456789abcdef")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error without JWT_ISSUER and JWT_AUDIENCE")
+	}
+
+	t.Setenv("JWT_ISSUER", "https://auth.example.com")
+	t.Setenv("JWT_AUDIENCE", "issue-board")
+	t.Setenv("JWT_DEFAULT_ROLE", "member")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.Auth.JWT.Enabled() || cfg.Auth.JWT.DefaultRole != models.RoleMember || cfg.Auth.JWT.Leeway != 30*time.Second {
+		t.Errorf("Unexpected JWT config: %+v", cfg.Auth.JWT)
+	}
+
+	t.Setenv("JWT_JWKS_FILE", "/etc/issue-board/jwks.json")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error with both a secret and a JWKS file")
+	}
+
+	t.Setenv("JWT_JWKS_FILE", "")
+	t.Setenv("JWT_HS256_SECRET", "short")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for a short secret")
+	}
+}
+
+func TestLoadWIP(t *testing.T) {
+	t.Setenv("API_KEY", "test-key")
+
+	t.Run("Limits", func(t *testing.T) {
+		t.Setenv("WIP_LIMITS", "In Progress=3, Todo=10")
+		t.Setenv("WIP_ASSIGNEE_LIMITS", "In Progress=1")
+		t.Setenv("WIP_STRICT", "true")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.WIP.Status["In Progress"] != 3 || cfg.WIP.Status["Todo"] != 10 {
+			t.Errorf("Expected status limits, got %v", cfg.WIP.Status)
+		}
+		if cfg.WIP.PerAssignee["In Progress"] != 1 || !cfg.WIP.Strict {
+			t.Errorf("Expected strict per-assignee limit, got %+v", cfg.WIP)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if cfg.WIP.Enabled() || cfg.WIP.Strict {
+			t.Errorf("Expected no WIP limits, got %+v", cfg.WIP)
+		}
+	})
+
+	t.Run("Invalid limits", func(t *testing.T) {
+		for _, val := range []string{"In Progress", "In Progress=0", "In Progress=many", "Doing=3"} {
+			t.Setenv("WIP_LIMITS", val)
+			if _, err := Load(); err == nil {
+				t.Errorf("Expected error for WIP_LIMITS=%q", val)
+			}
+		}
+	})
+}
+
 func TestGetEnv(t *testing.T) {
 	t.Run("Get existing env var", func(t *testing.T) {
 		os.Setenv("TEST_VAR", "test-value")
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// GetUser returns a user by ID, or nil if it does not exist
+func (r *Repository) GetUser(ctx context.Context, id string) (*models.User, error) {
+	var u models.User
+	var avatarURL sql.NullString
+	err := r.DB.QueryRowContext(ctx, r.rebind("SELECT id, name, avatar_url FROM users WHERE id = ?"), id).Scan(&u.ID, &u.Name, &avatarURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	u.AvatarURL = avatarURL.String
+	return &u, nil
+}
+
+func (r *Repository) CreateAccount(ctx context.Context, a models.Account) error {
+	query := "INSERT INTO accounts (user_id, email, password_hash) VALUES (?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, r.rebind(query), a.UserID, strings.ToLower(a.Email), a.PasswordHash); err != nil {
+		return r.writeError("failed to create account", err)
+	}
+	return nil
+}
+
+// GetAccountByEmail returns the account with the given email, or nil
+func (r *Repository) GetAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
+	var a models.Account
+	query := "SELECT user_id, email, password_hash FROM accounts WHERE email = ?"
+	err := r.DB.QueryRowContext(ctx, r.rebind(query), strings.ToLower(email)).Scan(&a.UserID, &a.Email, &a.PasswordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return &a, nil
+}
+
+// GetIdentity returns the user linked to an issuer's subject, or nil
+func (r *Repository) GetIdentity(ctx context.Context, issuer, subject string) (*models.Identity, error) {
+	id := models.Identity{Issuer: issuer, Subject: subject}
+	query := "SELECT user_id FROM user_identities WHERE issuer = ? AND subject = ?"
+	err := r.DB.QueryRowContext(ctx, r.rebind(query), issuer, subject).Scan(&id.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+	return &id, nil
+}
+
+func (r *Repository) CreateIdentity(ctx context.Context, id models.Identity) error {
+	query := "INSERT INTO user_identities (issuer, subject, user_id) VALUES (?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, r.rebind(query), id.Issuer, id.Subject, id.UserID); err != nil {
+		return r.writeError("failed to create identity", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreateSession(ctx context.Context, s models.Session) error {
+	query := "INSERT INTO sessions (id, user_id, csrf_token, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, r.rebind(query), s.ID, s.UserID, s.CSRFToken, s.CreatedAt, s.ExpiresAt); err != nil {
+		return r.writeError("failed to create session", err)
+	}
+	return nil
+}
+
+// GetSession returns a session by ID, or nil. Expired sessions are returned
+// too; callers check ExpiresAt.
+func (r *Repository) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	var s models.Session
+	query := "SELECT id, user_id, csrf_token, created_at, expires_at FROM sessions WHERE id = ?"
+	err := r.DB.QueryRowContext(ctx, r.rebind(query), id).Scan(&s.ID, &s.UserID, &s.CSRFToken, &s.CreatedAt, &s.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *Repository) DeleteSession(ctx context.Context, id string) error {
+	if _, err := r.DB.ExecContext(ctx, r.rebind("DELETE FROM sessions WHERE id = ?"), id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// ListComments returns the comments of an issue, oldest first
+func (r *Repository) ListComments(ctx context.Context, issueID string) ([]models.Comment, error) {
+	query := "SELECT id, issue_id, author_id, body, created_at FROM issue_comments WHERE issue_id = ? ORDER BY created_at, id"
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		var c models.Comment
+		var authorID sql.NullString
+		if err := rows.Scan(&c.ID, &c.IssueID, &authorID, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		if authorID.Valid {
+			c.AuthorID = &authorID.String
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+	return comments, nil
+}
+
+// CreateComment adds a comment to an issue and records the issue as
+// changed. It returns ErrNotFound if the issue does not exist or is in the
+// trash.
+func (r *Repository) CreateComment(ctx context.Context, c models.Comment) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, r.rebind("SELECT 1 FROM issues WHERE id = ? AND deleted_at IS NULL"), c.IssueID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("issue %s: %w", c.IssueID, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	query := "INSERT INTO issue_comments (id, issue_id, author_id, body, created_at) VALUES (?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, r.rebind(query), c.ID, c.IssueID, c.AuthorID, c.Body, c.CreatedAt); err != nil {
+		return r.writeError("failed to create comment", err)
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, c.IssueID, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// commentCounts returns the number of comments of the given issues, keyed
+// by issue ID. Issues without comments are left out.
+func (r *Repository) commentCounts(ctx context.Context, issueIDs []string) (map[string]int, error) {
+	query := fmt.Sprintf("SELECT issue_id, COUNT(*) FROM issue_comments WHERE issue_id IN (%s) GROUP BY issue_id", placeholders(len(issueIDs)))
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), stringArgs(issueIDs)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(issueIDs))
+	for rows.Next() {
+		var issueID string
+		var n int
+		if err := rows.Scan(&issueID, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[issueID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment counts: %w", err)
+	}
+	return counts, nil
+}
+
+// childrenOf returns the live sub-issues of the given issues in board
+// order, keyed by parent ID
+func (r *Repository) childrenOf(ctx context.Context, issueIDs []string) (map[string][]models.IssueRef, error) {
+	query := fmt.Sprintf(`
+		SELECT parent_id, id, title, status, type
+		FROM issues
+		WHERE parent_id IN (%s) AND deleted_at IS NULL
+		ORDER BY order_index, id
+	`, placeholders(len(issueIDs)))
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), stringArgs(issueIDs)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sub-issues: %w", err)
+	}
+	defer rows.Close()
+
+	children := make(map[string][]models.IssueRef)
+	for rows.Next() {
+		var parentID string
+		var c models.IssueRef
+		if err := rows.Scan(&parentID, &c.ID, &c.Title, &c.Status, &c.Type); err != nil {
+			return nil, fmt.Errorf("failed to scan sub-issue: %w", err)
+		}
+		children[parentID] = append(children[parentID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sub-issues: %w", err)
+	}
+	return children, nil
+}
+
+// checkParent returns a *ReferenceError if parentID is not a live issue, or
+// a constraint violation if it would make issue id its own ancestor. A nil
+// parentID passes.
+func (r *Repository) checkParent(ctx context.Context, q queryer, id string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+	for current, first := *parentID, true; current != ""; first = false {
+		if current == id {
+			return fmt.Errorf("%w: issue %s cannot be its own ancestor", ErrConstraint, id)
+		}
+		next, found, err := r.parentOf(ctx, q, current, first)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if first {
+				return &ReferenceError{ParentID: *parentID}
+			}
+			return nil
+		}
+		current = next
+	}
+	return nil
+}
+
+// parentOf returns the parent ID of an issue, or "" if it has none, and
+// whether the issue exists. Trashed issues count as missing when live is set.
+func (r *Repository) parentOf(ctx context.Context, q queryer, id string, live bool) (string, bool, error) {
+	query := "SELECT parent_id FROM issues WHERE id = ?"
+	if live {
+		query += " AND deleted_at IS NULL"
+	}
+	rows, err := q.QueryContext(ctx, r.rebind(query), id)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get parent issue: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", false, rows.Err()
+	}
+	var parentID sql.NullString
+	if err := rows.Scan(&parentID); err != nil {
+		return "", false, fmt.Errorf("failed to scan parent issue: %w", err)
+	}
+	return parentID.String, true, nil
+}
+
+// parentError converts a foreign key violation caused by a parent purged
+// concurrently into a *ReferenceError. It returns nil for other errors.
+func (r *Repository) parentError(ctx context.Context, err error, id string, parentID *string) error {
+	if parentID == nil || r.dialect == nil || !r.dialect.ForeignKeyViolation(err) {
+		return nil
+	}
+	var refErr *ReferenceError
+	if checkErr := r.checkParent(ctx, r.DB, id, parentID); errors.As(checkErr, &refErr) {
+		return checkErr
+	}
+	return nil
+}
+
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
@@ -5,15 +5,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 var DB *sql.DB
 
+// sqlDrivers maps storage drivers to their database/sql driver names
+var sqlDrivers = map[string]string{
+	DriverSQLite:   "sqlite3",
+	DriverPostgres: "pgx",
+}
+
 func InitDB(dataSourceName string) error {
+	return Open(DriverSQLite, dataSourceName)
+}
+
+// Open initializes DB for the given storage driver (sqlite or postgres)
+func Open(driver, dataSourceName string) error {
+	sqlDriver, ok := sqlDrivers[driver]
+	if !ok {
+		return fmt.Errorf("unsupported database driver: %s", driver)
+	}
+
+	if driver == DriverSQLite {
+		dataSourceName = sqliteForeignKeys(dataSourceName)
+	}
+
 	var err error
-	DB, err = sql.Open("sqlite3", dataSourceName)
+	DB, err = sql.Open(sqlDriver, dataSourceName)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -25,25 +47,101 @@ func InitDB(dataSourceName string) error {
 	return nil
 }
 
+// sqliteForeignKeys enables foreign key enforcement, which SQLite leaves off
+// by default and only applies per connection, on every connection of the pool
+func sqliteForeignKeys(dsn string) string {
+	if strings.Contains(dsn, "_foreign_keys=") || strings.Contains(dsn, "_fk=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_foreign_keys=on"
+	}
+	return dsn + "?_foreign_keys=on"
+}
+
 func RunMigrations(migrationDir string) error {
+	return runMigrations(DB, migrationDir)
+}
+
+// Migrate applies the migrations in migrationDir that db has not run yet
+func Migrate(db *sql.DB, migrationDir string) error {
+	return runMigrations(db, migrationDir)
+}
+
+// schemaMigrationsTable records the migrations applied to a database, so
+// each runs once. Migrations written before it existed are idempotent and
+// are recorded the first time they run against an existing database.
+const schemaMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)
+`
+
+func runMigrations(db *sql.DB, migrationDir string) error {
 	files, err := os.ReadDir(migrationDir)
 	if err != nil {
 		return fmt.Errorf("failed to read migration directory: %w", err)
 	}
 
+	if _, err := db.Exec(schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	d := dialectOf(db)
+
 	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".sql" {
-			content, err := os.ReadFile(filepath.Join(migrationDir, file.Name()))
-			if err != nil {
-				return fmt.Errorf("failed to read migration file %s: %w", file.Name(), err)
-			}
-
-			_, err = DB.Exec(string(content))
-			if err != nil {
-				return fmt.Errorf("failed to execute migration %s: %w", file.Name(), err)
-			}
-			fmt.Printf("Applied migration: %s\n", file.Name())
+		if filepath.Ext(file.Name()) != ".sql" || applied[file.Name()] {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(migrationDir, file.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", file.Name(), err)
+		}
+
+		// A migration and its record commit together, so a failed one is
+		// retried in full on the next start
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %s: %w", file.Name(), err)
+		}
+		if _, err := tx.Exec(string(content)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to execute migration %s: %w", file.Name(), err)
+		}
+		if _, err := tx.Exec(d.Rebind("INSERT INTO schema_migrations (version) VALUES (?)"), file.Name()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %s: %w", file.Name(), err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %s: %w", file.Name(), err)
 		}
+		fmt.Printf("Applied migration: %s\n", file.Name())
 	}
 	return nil
 }
+
+// appliedMigrations returns the file names recorded in schema_migrations
+func appliedMigrations(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating migrations: %w", err)
+	}
+	return applied, nil
+}
@@ -43,6 +43,25 @@ func TestInitDB(t *testing.T) {
 		DB.Close()
 	})
 
+	t.Run("Foreign keys are enforced", func(t *testing.T) {
+		if err := InitDB(filepath.Join(t.TempDir(), "test.db")); err != nil {
+			t.Fatalf("Failed to init file database: %v", err)
+		}
+		defer DB.Close()
+
+		// Every pooled connection must have the pragma, not just the first
+		DB.SetMaxIdleConns(0)
+		for i := 0; i < 3; i++ {
+			var enabled int
+			if err := DB.QueryRow("PRAGMA foreign_keys").Scan(&enabled); err != nil {
+				t.Fatalf("Failed to read pragma: %v", err)
+			}
+			if enabled != 1 {
+				t.Fatalf("Expected foreign_keys=1, got %d", enabled)
+			}
+		}
+	})
+
 	t.Run("Invalid database path", func(t *testing.T) {
 		err := InitDB("/invalid/path/that/does/not/exist/db.sqlite")
 		if err == nil {
@@ -51,6 +70,19 @@ func TestInitDB(t *testing.T) {
 	})
 }
 
+func TestSQLiteForeignKeys(t *testing.T) {
+	for dsn, want := range map[string]string{
+		"./issues.db":                       "./issues.db?_foreign_keys=on",
+		"file:test.db?cache=shared":         "file:test.db?cache=shared&_foreign_keys=on",
+		"file:test.db?_foreign_keys=off":    "file:test.db?_foreign_keys=off",
+		"file:test.db?_fk=1&parseTime=true": "file:test.db?_fk=1&parseTime=true",
+	} {
+		if got := sqliteForeignKeys(dsn); got != want {
+			t.Errorf("sqliteForeignKeys(%q) = %q, want %q", dsn, got, want)
+		}
+	}
+}
+
 func TestRunMigrations(t *testing.T) {
 	t.Run("Run valid migrations", func(t *testing.T) {
 		// Create temp dir for migrations
@@ -179,6 +211,65 @@ func TestRunMigrations(t *testing.T) {
 		}
 	})
 
+	t.Run("Apply each migration once", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		// Not idempotent, so a second run would fail
+		migrationPath := filepath.Join(tempDir, "001_alter.sql")
+		err := os.WriteFile(migrationPath, []byte("CREATE TABLE items (id TEXT); ALTER TABLE items ADD COLUMN name TEXT;"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create migration file: %v", err)
+		}
+
+		err = InitDB(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("Failed to init database: %v", err)
+		}
+		defer DB.Close()
+
+		for i := 0; i < 2; i++ {
+			if err := RunMigrations(tempDir); err != nil {
+				t.Fatalf("Run %d failed: %v", i+1, err)
+			}
+		}
+
+		var version string
+		err = DB.QueryRow("SELECT version FROM schema_migrations").Scan(&version)
+		if err != nil || version != "001_alter.sql" {
+			t.Errorf("Expected 001_alter.sql to be recorded, got %q (%v)", version, err)
+		}
+	})
+
+	t.Run("Failed migration is not recorded", func(t *testing.T) {
+		tempDir := t.TempDir()
+
+		migrationPath := filepath.Join(tempDir, "001_partial.sql")
+		err := os.WriteFile(migrationPath, []byte("CREATE TABLE partial (id TEXT); INVALID SQL STATEMENT;"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create migration file: %v", err)
+		}
+
+		err = InitDB(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("Failed to init database: %v", err)
+		}
+		defer DB.Close()
+
+		if err := RunMigrations(tempDir); err == nil {
+			t.Fatal("Expected error for invalid SQL in migration")
+		}
+
+		var count int
+		DB.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
+		if count != 0 {
+			t.Errorf("Expected no recorded migrations, got %d", count)
+		}
+		DB.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='partial'").Scan(&count)
+		if count != 0 {
+			t.Error("Expected the failed migration to be rolled back")
+		}
+	})
+
 	t.Run("Skip non-SQL files", func(t *testing.T) {
 		tempDir := t.TempDir()
 
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// dialect captures the SQL differences between the supported databases.
+// Queries in this package are written with SQLite-style "?" placeholders
+// and rewritten by the dialect before execution.
+type dialect interface {
+	Name() string
+	Rebind(query string) string
+	// ForeignKeyViolation reports whether err is a failed foreign key constraint
+	ForeignKeyViolation(err error) bool
+	// ConstraintKind returns ErrConflict if err is a failed unique or primary
+	// key constraint, ErrConstraint for other failed constraints, else nil
+	ConstraintKind(err error) error
+}
+
+// dialectOf returns the dialect of an open database
+func dialectOf(db *sql.DB) dialect {
+	if _, ok := db.Driver().(*sqlite3.SQLiteDriver); ok {
+		return sqliteDialect{}
+	}
+	return postgresDialect{}
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return DriverSQLite }
+
+func (sqliteDialect) Rebind(query string) string { return query }
+
+func (sqliteDialect) ForeignKeyViolation(err error) bool {
+	var e sqlite3.Error
+	return errors.As(err, &e) && e.ExtendedCode == sqlite3.ErrConstraintForeignKey
+}
+
+func (sqliteDialect) ConstraintKind(err error) error {
+	var e sqlite3.Error
+	if !errors.As(err, &e) || e.Code != sqlite3.ErrConstraint {
+		return nil
+	}
+	switch e.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return ErrConflict
+	}
+	return ErrConstraint
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return DriverPostgres }
+
+// Rebind replaces each "?" placeholder outside of string literals with $1, $2, ...
+func (postgresDialect) Rebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+
+	n := 0
+	inQuote := false
+	for _, c := range query {
+		switch {
+		case c == '\'':
+			inQuote = !inQuote
+			b.WriteRune(c)
+		case c == '?' && !inQuote:
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func (postgresDialect) ForeignKeyViolation(err error) bool {
+	var e *pgconn.PgError
+	return errors.As(err, &e) && e.Code == "23503"
+}
+
+func (postgresDialect) ConstraintKind(err error) error {
+	var e *pgconn.PgError
+	if !errors.As(err, &e) {
+		return nil
+	}
+	switch e.Code {
+	case "23505": // unique_violation
+		return ErrConflict
+	case "23502", "23503", "23514": // not_null, foreign_key and check violations
+		return ErrConstraint
+	}
+	return nil
+}
+
+func dialectFor(driver string) (dialect, error) {
+	switch driver {
+	case DriverSQLite, "":
+		return sqliteDialect{}, nil
+	case DriverPostgres:
+		return postgresDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT * FROM issues WHERE id = ?", "SELECT * FROM issues WHERE id = $1"},
+		{"INSERT INTO t (a, b) VALUES (?, ?)", "INSERT INTO t (a, b) VALUES ($1, $2)"},
+		{"SELECT '?' FROM t WHERE a IN (?,?)", "SELECT '?' FROM t WHERE a IN ($1,$2)"},
+	}
+
+	for _, tt := range tests {
+		if got := (postgresDialect{}).Rebind(tt.query); got != tt.want {
+			t.Errorf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestDialectFor(t *testing.T) {
+	for _, driver := range []string{"", DriverSQLite, DriverPostgres} {
+		if _, err := dialectFor(driver); err != nil {
+			t.Errorf("dialectFor(%q) returned error: %v", driver, err)
+		}
+	}
+	if _, err := dialectFor("mysql"); err == nil {
+		t.Error("Expected error for unsupported driver")
+	}
+}
+
+func TestPostgresConstraintKind(t *testing.T) {
+	tests := []struct {
+		code string
+		want error
+	}{
+		{"23505", ErrConflict},
+		{"23503", ErrConstraint},
+		{"23514", ErrConstraint},
+		{"23502", ErrConstraint},
+		{"40001", nil},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("exec: %w", &pgconn.PgError{Code: tt.code})
+		if got := (postgresDialect{}).ConstraintKind(err); got != tt.want {
+			t.Errorf("ConstraintKind(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+	if got := (postgresDialect{}).ConstraintKind(errors.New("boom")); got != nil {
+		t.Errorf("Expected nil for a non-database error, got %v", got)
+	}
+}
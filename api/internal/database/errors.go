@@ -0,0 +1,72 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// Sentinel errors returned, wrapped, by the stores. Callers test for them
+// with errors.Is.
+var (
+	// ErrNotFound means the record to change does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the write clashes with an existing record, e.g. a
+	// duplicate ID or email
+	ErrConflict = errors.New("conflict")
+	// ErrConstraint means the write breaks a constraint: an unknown
+	// reference, a disallowed value or a missing required column
+	ErrConstraint = errors.New("constraint violation")
+)
+
+// ReferenceError is returned when an issue refers to an assignee, labels or
+// a parent issue that do not exist. Nothing is written. It matches ErrConstraint.
+type ReferenceError struct {
+	AssigneeID string   // unknown assignee, if any
+	LabelIDs   []string // unknown labels
+	ParentID   string   // unknown or trashed parent issue, if any
+}
+
+func (e *ReferenceError) Error() string {
+	var parts []string
+	if e.AssigneeID != "" {
+		parts = append(parts, "unknown assignee: "+e.AssigneeID)
+	}
+	if len(e.LabelIDs) > 0 {
+		parts = append(parts, "unknown labels: "+strings.Join(e.LabelIDs, ", "))
+	}
+	if e.ParentID != "" {
+		parts = append(parts, "unknown parent: "+e.ParentID)
+	}
+	if len(parts) == 0 {
+		return "unknown reference"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Is makes a ReferenceError match ErrConstraint
+func (e *ReferenceError) Is(target error) bool {
+	return target == ErrConstraint
+}
+
+// WIPError is returned by a store enforcing strict WIP limits when a write
+// would take a column over its limit. Nothing is written. It matches
+// ErrConflict.
+type WIPError struct {
+	Violations []models.WIPViolation
+}
+
+func (e *WIPError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%q limit of %d exceeded (%d issues)", v.Status, v.Limit, v.Count)
+	}
+	return "WIP limit exceeded: " + strings.Join(parts, "; ")
+}
+
+// Is makes a WIPError match ErrConflict
+func (e *WIPError) Is(target error) bool {
+	return target == ErrConflict
+}
@@ -0,0 +1,278 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// customFieldsUpdate is the UpdateIssue key setting custom field values
+const customFieldsUpdate = "custom_fields"
+
+// ListFields returns all custom field definitions ordered by key
+func (r *Repository) ListFields(ctx context.Context) ([]models.CustomField, error) {
+	rows, err := r.DB.QueryContext(ctx, "SELECT id, key, name, type, options, created_at, updated_at FROM custom_fields ORDER BY key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := make([]models.CustomField, 0)
+	for rows.Next() {
+		f, err := scanField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+// GetField returns a custom field definition by ID, or nil if it does not exist
+func (r *Repository) GetField(ctx context.Context, id string) (*models.CustomField, error) {
+	row := r.DB.QueryRowContext(ctx, r.rebind("SELECT id, key, name, type, options, created_at, updated_at FROM custom_fields WHERE id = ?"), id)
+	f, err := scanField(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// scanField scans a custom_fields row, decoding its options
+func scanField(row interface{ Scan(dest ...any) error }) (models.CustomField, error) {
+	var f models.CustomField
+	var options string
+	if err := row.Scan(&f.ID, &f.Key, &f.Name, &f.Type, &options, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return f, err
+		}
+		return f, fmt.Errorf("failed to scan custom field: %w", err)
+	}
+	if err := json.Unmarshal([]byte(options), &f.Options); err != nil {
+		return f, fmt.Errorf("failed to decode options of custom field %s: %w", f.ID, err)
+	}
+	if len(f.Options) == 0 {
+		f.Options = nil
+	}
+	return f, nil
+}
+
+// CreateField stores a custom field definition. A key already in use
+// returns ErrConflict.
+func (r *Repository) CreateField(ctx context.Context, f models.CustomField) error {
+	options, err := encodeOptions(f.Options)
+	if err != nil {
+		return err
+	}
+	query := "INSERT INTO custom_fields (id, key, name, type, options, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, r.rebind(query), f.ID, f.Key, f.Name, f.Type, options, f.CreatedAt, f.UpdatedAt); err != nil {
+		return r.writeError("failed to create custom field", err)
+	}
+	return nil
+}
+
+// UpdateField replaces the name and options of a custom field. Its key and
+// type never change. It returns ErrNotFound if the field does not exist.
+func (r *Repository) UpdateField(ctx context.Context, f models.CustomField) error {
+	options, err := encodeOptions(f.Options)
+	if err != nil {
+		return err
+	}
+	query := "UPDATE custom_fields SET name = ?, options = ?, updated_at = ? WHERE id = ?"
+	result, err := r.DB.ExecContext(ctx, r.rebind(query), f.Name, options, f.UpdatedAt, f.ID)
+	if err != nil {
+		return r.writeError("failed to update custom field", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("custom field %s: %w", f.ID, ErrNotFound)
+	}
+	return nil
+}
+
+func encodeOptions(options []string) (string, error) {
+	if options == nil {
+		options = []string{}
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode options: %w", err)
+	}
+	return string(data), nil
+}
+
+// DeleteField deletes a custom field together with its values. Every issue
+// that had a value is recorded as changed.
+func (r *Repository) DeleteField(ctx context.Context, id string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, r.rebind("SELECT DISTINCT issue_id FROM issue_field_values WHERE field_id = ? ORDER BY issue_id"), id)
+	if err != nil {
+		return fmt.Errorf("failed to query custom field values: %w", err)
+	}
+	var issueIDs []string
+	for rows.Next() {
+		var issueID string
+		if err := rows.Scan(&issueID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan custom field value: %w", err)
+		}
+		issueIDs = append(issueIDs, issueID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating custom field values: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, r.rebind("DELETE FROM custom_fields WHERE id = ?"), id)
+	if err != nil {
+		return r.writeError("failed to delete custom field", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("custom field %s: %w", id, ErrNotFound)
+	}
+
+	for _, issueID := range issueIDs {
+		if err := r.recordChange(ctx, tx, models.EntityIssue, issueID, false); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// fieldValues returns the custom field values of the given issues, keyed by
+// issue ID
+func (r *Repository) fieldValues(ctx context.Context, issueIDs []string) (map[string]models.FieldValues, error) {
+	values := make(map[string]models.FieldValues)
+	if len(issueIDs) == 0 {
+		return values, nil
+	}
+	args := make([]interface{}, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT v.issue_id, f.key, f.type, v.value
+		FROM issue_field_values v
+		JOIN custom_fields f ON f.id = v.field_id
+		WHERE v.issue_id IN (%s)
+		ORDER BY v.issue_id, f.key, v.value
+	`, placeholders(len(issueIDs)))
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom field values: %w", err)
+	}
+	defer rows.Close()
+
+	stored := make(map[[2]string][]string)
+	types := make(map[string]string)
+	for rows.Next() {
+		var issueID, key, fieldType, value string
+		if err := rows.Scan(&issueID, &key, &fieldType, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field value: %w", err)
+		}
+		stored[[2]string{issueID, key}] = append(stored[[2]string{issueID, key}], value)
+		types[key] = fieldType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom field values: %w", err)
+	}
+
+	for k, v := range stored {
+		issueID, key := k[0], k[1]
+		if values[issueID] == nil {
+			values[issueID] = make(models.FieldValues)
+		}
+		values[issueID][key] = models.CustomField{Key: key, Type: types[key]}.FieldValue(v)
+	}
+	return values, nil
+}
+
+// writeFieldValues sets the custom field values of an issue within tx. A
+// nil value clears the field, and fields left out are unchanged. An unknown
+// key is a constraint violation.
+func (r *Repository) writeFieldValues(ctx context.Context, tx *sql.Tx, issueID string, values models.FieldValues) error {
+	if len(values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	args := make([]interface{}, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+		args = append(args, key)
+	}
+
+	query := fmt.Sprintf("SELECT id, key, type FROM custom_fields WHERE key IN (%s)", placeholders(len(keys)))
+	rows, err := tx.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	fields := make(map[string]models.CustomField, len(keys))
+	for rows.Next() {
+		var f models.CustomField
+		if err := rows.Scan(&f.ID, &f.Key, &f.Type); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields[f.Key] = f
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating custom fields: %w", err)
+	}
+
+	var unknown []string
+	for _, key := range keys {
+		if _, ok := fields[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: unknown custom fields: %s", ErrConstraint, strings.Join(unknown, ", "))
+	}
+
+	for key, value := range values {
+		f := fields[key]
+		if _, err := tx.ExecContext(ctx, r.rebind("DELETE FROM issue_field_values WHERE issue_id = ? AND field_id = ?"), issueID, f.ID); err != nil {
+			return fmt.Errorf("failed to delete custom field values: %w", err)
+		}
+		var number interface{}
+		if n, ok := value.(float64); ok && f.Type == models.FieldNumber {
+			number = n
+		}
+		for _, s := range models.StoredValues(value) {
+			query := "INSERT INTO issue_field_values (issue_id, field_id, value, number) VALUES (?, ?, ?, ?)"
+			if _, err := tx.ExecContext(ctx, r.rebind(query), issueID, f.ID, s, number); err != nil {
+				return r.writeError("failed to insert custom field value", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1437 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// MemoryStore is a thread-safe, non-persistent Store. It mirrors the
+// filtering, ordering, pagination and label semantics of Repository and is
+// intended for tests and demo deployments.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	issues      map[string]*models.Issue
+	issueLabels map[string][]string // issue ID -> label IDs
+	history     []models.StatusChange
+	users       []models.User
+	roles       map[roleScope]models.Role
+	accounts    map[string]models.Account // email -> account
+	identities  map[[2]string]string      // issuer, subject -> user ID
+	sessions    map[string]models.Session
+	labels      []models.Label
+	version     models.BoardVersion
+	changes     []models.Change
+	templates   map[string]models.IssueTemplate
+	fields      map[string]models.CustomField
+	issueTypes  map[string]models.IssueType
+	comments    map[string][]models.Comment // by issue ID, oldest first
+	wip         models.WIPLimits
+}
+
+// roleScope keys a role assignment by user and project
+type roleScope struct {
+	userID    string
+	projectID string
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		issues:      make(map[string]*models.Issue),
+		issueLabels: make(map[string][]string),
+		roles:       make(map[roleScope]models.Role),
+		accounts:    make(map[string]models.Account),
+		identities:  make(map[[2]string]string),
+		sessions:    make(map[string]models.Session),
+		templates:   make(map[string]models.IssueTemplate),
+		fields:      make(map[string]models.CustomField),
+		issueTypes:  defaultIssueTypes(),
+		comments:    make(map[string][]models.Comment),
+	}
+}
+
+// defaultIssueTypes returns the issue types the schema migration seeds
+func defaultIssueTypes() map[string]models.IssueType {
+	now := time.Now()
+	types := make(map[string]models.IssueType, len(models.DefaultIssueTypes))
+	for _, t := range models.DefaultIssueTypes {
+		t.UpdatedAt = now
+		types[t.Key] = copyIssueType(t)
+	}
+	return types
+}
+
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// GetIssues retrieves issues with optional filters and pagination
+func (m *MemoryStore) GetIssues(ctx context.Context, status []string, assigneeID string, priority []string, labels []string, page, pageSize int) ([]models.Issue, error) {
+	list, err := m.ListIssues(ctx, models.IssueQuery{
+		IssueFilter: models.IssueFilter{Status: status, AssigneeID: assigneeID, Priority: priority, Labels: labels},
+		Page:        page,
+		PageSize:    pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListIssues retrieves a page of issues in the requested sort order
+func (m *MemoryStore) ListIssues(ctx context.Context, q models.IssueQuery) (*models.IssueList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := q.SortKeys()
+
+	// Hydrate up front so assignee names are available for sorting
+	type entry struct {
+		issue  models.Issue
+		values []interface{}
+	}
+	var matched []entry
+	for _, issue := range m.filterIssues(q.IssueFilter) {
+		i := m.hydrate(issue)
+		matched = append(matched, entry{issue: i, values: issueCursor(i, keys).Values})
+	}
+	sort.Slice(matched, func(a, b int) bool {
+		return comparePositions(keys, matched[a].values, matched[a].issue.ID, matched[b].values, matched[b].issue.ID) < 0
+	})
+	total := len(matched)
+
+	if q.Cursor != nil {
+		values, err := cursorValues(q.Cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		start := sort.Search(len(matched), func(i int) bool {
+			return comparePositions(keys, values, q.Cursor.ID, matched[i].values, matched[i].issue.ID) < 0
+		})
+		matched = matched[start:]
+	}
+
+	var next *string
+	switch {
+	case q.Keyset() && q.Limit > 0:
+		if len(matched) > q.Limit {
+			matched = matched[:q.Limit]
+			c := issueCursor(matched[q.Limit-1].issue, keys).Encode()
+			next = &c
+		}
+	case !q.Keyset() && q.PageSize > 0:
+		offset := (q.Page - 1) * q.PageSize
+		if offset < 0 {
+			offset = 0
+		}
+		if offset >= len(matched) {
+			matched = nil
+		} else {
+			end := offset + q.PageSize
+			if end > len(matched) {
+				end = len(matched)
+			}
+			matched = matched[offset:end]
+		}
+	}
+
+	expand := q.Expansions()
+	issues := make([]models.Issue, 0, len(matched))
+	for _, e := range matched {
+		i := e.issue
+		if !q.WantsField("description") {
+			i.Description = ""
+		}
+		if !expand.Assignee {
+			i.Assignee = nil
+		}
+		if !q.WantsField("custom_fields") {
+			i.CustomFields = nil
+		}
+		if expand.Labels {
+			i.Labels = m.labelsFor(i.ID)
+			if i.Labels == nil {
+				i.Labels = []models.Label{}
+			}
+		}
+		if expand.CommentsCount {
+			count := len(m.comments[i.ID])
+			i.CommentsCount = &count
+		}
+		if expand.Children {
+			i.Children = m.childrenOf(i.ID)
+		}
+		issues = append(issues, i)
+	}
+
+	list := &models.IssueList{Items: issues, NextCursor: next, TotalCount: len(issues)}
+	if q.Keyset() || q.PageSize > 0 {
+		list.TotalCount = total
+	}
+	return list, nil
+}
+
+// filterIssues returns the issues matching f. Callers must hold the read lock.
+func (m *MemoryStore) filterIssues(f models.IssueFilter) []*models.Issue {
+	var matched []*models.Issue
+	for _, issue := range m.issues {
+		if (issue.DeletedAt != nil) != f.Trashed {
+			continue
+		}
+		if issue.ArchivedAt != nil && !f.IncludeArchived {
+			continue
+		}
+		if len(f.IDs) > 0 && !contains(f.IDs, issue.ID) {
+			continue
+		}
+		if len(f.Status) > 0 && !contains(f.Status, issue.Status) {
+			continue
+		}
+		if f.AssigneeID != "" && (issue.AssigneeID == nil || *issue.AssigneeID != f.AssigneeID) {
+			continue
+		}
+		if len(f.Priority) > 0 && !contains(f.Priority, issue.Priority) {
+			continue
+		}
+		if len(f.Types) > 0 && !contains(f.Types, issue.Type) {
+			continue
+		}
+		if len(f.Labels) > 0 && !m.hasAnyLabelName(issue.ID, f.Labels) {
+			continue
+		}
+		if !matchesFields(issue, f.CustomFields) {
+			continue
+		}
+		matched = append(matched, issue)
+	}
+	return matched
+}
+
+func (m *MemoryStore) GetIssue(ctx context.Context, id string) (*models.Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	issue, ok := m.issues[id]
+	if !ok || issue.DeletedAt != nil {
+		return nil, nil
+	}
+	i := m.hydrate(issue)
+	i.Labels = m.labelsFor(id)
+	return &i, nil
+}
+
+func (m *MemoryStore) CreateIssue(ctx context.Context, issue models.Issue) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.issues[issue.ID]; exists {
+		return fmt.Errorf("failed to create issue: %w: duplicate id %s", ErrConflict, issue.ID)
+	}
+	issue.Type = issueType(issue)
+	if err := checkIssue(&issue); err != nil {
+		return fmt.Errorf("failed to create issue: %w", err)
+	}
+	labelIDs := make([]string, len(issue.Labels))
+	for i, l := range issue.Labels {
+		labelIDs[i] = l.ID
+	}
+	if err := m.checkReferences(issue.AssigneeID, labelIDs); err != nil {
+		return err
+	}
+	if err := m.checkParent(issue.ID, issue.ParentID); err != nil {
+		return err
+	}
+	if err := m.checkWIP(nil, issue.Status, issue.AssigneeID); err != nil {
+		return err
+	}
+	if err := checkLabels(labelIDs); err != nil {
+		return err
+	}
+	if err := m.checkFields(issue.CustomFields); err != nil {
+		return err
+	}
+
+	stored := issue
+	stored.AssigneeID = copyString(issue.AssigneeID)
+	stored.ParentID = copyString(issue.ParentID)
+	stored.CommentsCount = nil
+	stored.Children = nil
+	stored.Assignee = nil
+	stored.Labels = nil
+	stored.DeletedAt = nil
+	stored.ArchivedAt = nil
+	stored.CustomFields = mergeFieldValues(nil, issue.CustomFields)
+	m.issues[issue.ID] = &stored
+	if len(labelIDs) > 0 {
+		m.issueLabels[issue.ID] = labelIDs
+	}
+	m.history = append(m.history, models.StatusChange{IssueID: issue.ID, To: issue.Status, ChangedAt: issue.CreatedAt})
+	m.recordChange(models.EntityIssue, issue.ID, false)
+	return nil
+}
+
+func (m *MemoryStore) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}) error {
+	return m.UpdateIssueWithLabels(ctx, id, updates, nil)
+}
+
+func (m *MemoryStore) UpdateIssueWithLabels(ctx context.Context, id string, updates map[string]interface{}, labelIDs []string) error {
+	if len(updates) == 0 && labelIDs == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok || issue.DeletedAt != nil {
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+
+	// Apply to a copy so a bad column leaves the issue untouched
+	updated := *issue
+	for column, value := range updates {
+		if err := setIssueColumn(&updated, column, value); err != nil {
+			return fmt.Errorf("failed to update issue: %w", err)
+		}
+	}
+	if err := checkIssue(&updated); err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+	if err := m.checkReferences(updatedAssignee(updates), labelIDs); err != nil {
+		return err
+	}
+	if err := m.checkParent(id, updatedParent(updates)); err != nil {
+		return err
+	}
+	if _, statusChanged := updates["status"]; statusChanged || updatedAssignee(updates) != nil {
+		if err := m.checkWIP(issue, updated.Status, updated.AssigneeID); err != nil {
+			return err
+		}
+	}
+	if err := checkLabels(labelIDs); err != nil {
+		return err
+	}
+	if err := m.checkFields(updatedFields(updates)); err != nil {
+		return err
+	}
+
+	if updated.Status != issue.Status {
+		from := issue.Status
+		m.history = append(m.history, models.StatusChange{IssueID: id, From: &from, To: updated.Status, ChangedAt: changedAt(updates)})
+	}
+	*issue = updated
+	if labelIDs != nil {
+		m.setLabels(id, labelIDs)
+	}
+	m.recordChange(models.EntityIssue, id, false)
+	return nil
+}
+
+func (m *MemoryStore) UpdateIssueLabels(ctx context.Context, issueID string, labelIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if issue, ok := m.issues[issueID]; !ok || issue.DeletedAt != nil {
+		return fmt.Errorf("issue %s: %w", issueID, ErrNotFound)
+	}
+	if err := m.checkReferences(nil, labelIDs); err != nil {
+		return err
+	}
+	if err := checkLabels(labelIDs); err != nil {
+		return err
+	}
+	m.setLabels(issueID, labelIDs)
+	m.recordChange(models.EntityIssue, issueID, false)
+	return nil
+}
+
+// setLabels replaces the labels of an issue. Callers must hold the write lock.
+func (m *MemoryStore) setLabels(issueID string, labelIDs []string) {
+	if len(labelIDs) == 0 {
+		delete(m.issueLabels, issueID)
+		return
+	}
+	m.issueLabels[issueID] = append([]string(nil), labelIDs...)
+}
+
+// checkReferences enforces the schema's foreign keys, returning a
+// *ReferenceError like Repository. Callers must hold the lock.
+func (m *MemoryStore) checkReferences(assigneeID *string, labelIDs []string) error {
+	var refErr ReferenceError
+	if assigneeID != nil {
+		if _, ok := m.user(*assigneeID); !ok {
+			refErr.AssigneeID = *assigneeID
+		}
+	}
+	seen := make(map[string]bool, len(labelIDs))
+	for _, id := range labelIDs {
+		if _, ok := m.label(id); !ok && !seen[id] {
+			seen[id] = true
+			refErr.LabelIDs = append(refErr.LabelIDs, id)
+		}
+	}
+	if refErr.AssigneeID != "" || len(refErr.LabelIDs) > 0 {
+		return &refErr
+	}
+	return nil
+}
+
+// checkParent returns a *ReferenceError, like Repository, if parentID is
+// not a live issue, or a constraint violation if it would make issue id its
+// own ancestor. Callers must hold the lock.
+func (m *MemoryStore) checkParent(id string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+	if parent, ok := m.issues[*parentID]; !ok || parent.DeletedAt != nil {
+		return &ReferenceError{ParentID: *parentID}
+	}
+	for current := parentID; current != nil; {
+		if *current == id {
+			return fmt.Errorf("%w: issue %s cannot be its own ancestor", ErrConstraint, id)
+		}
+		issue, ok := m.issues[*current]
+		if !ok {
+			break
+		}
+		current = issue.ParentID
+	}
+	return nil
+}
+
+// childrenOf returns the live sub-issues of an issue in board order.
+// Callers must hold the read lock.
+func (m *MemoryStore) childrenOf(parentID string) []models.IssueRef {
+	var children []*models.Issue
+	for _, issue := range m.issues {
+		if issue.ParentID != nil && *issue.ParentID == parentID && issue.DeletedAt == nil {
+			children = append(children, issue)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].OrderIndex != children[j].OrderIndex {
+			return children[i].OrderIndex < children[j].OrderIndex
+		}
+		return children[i].ID < children[j].ID
+	})
+
+	refs := make([]models.IssueRef, len(children))
+	for i, c := range children {
+		refs[i] = models.IssueRef{ID: c.ID, Title: c.Title, Status: c.Status, Type: c.Type}
+	}
+	return refs
+}
+
+func (m *MemoryStore) ListComments(ctx context.Context, issueID string) ([]models.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	comments := make([]models.Comment, len(m.comments[issueID]))
+	for i, c := range m.comments[issueID] {
+		c.AuthorID = copyString(c.AuthorID)
+		comments[i] = c
+	}
+	return comments, nil
+}
+
+func (m *MemoryStore) CreateComment(ctx context.Context, c models.Comment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[c.IssueID]
+	if !ok || issue.DeletedAt != nil {
+		return fmt.Errorf("issue %s: %w", c.IssueID, ErrNotFound)
+	}
+	for _, existing := range m.comments[c.IssueID] {
+		if existing.ID == c.ID {
+			return fmt.Errorf("failed to create comment: %w: duplicate id %s", ErrConflict, c.ID)
+		}
+	}
+	c.AuthorID = copyString(c.AuthorID)
+	m.comments[c.IssueID] = append(m.comments[c.IssueID], c)
+	sort.SliceStable(m.comments[c.IssueID], func(i, j int) bool {
+		return m.comments[c.IssueID][i].CreatedAt.Before(m.comments[c.IssueID][j].CreatedAt)
+	})
+	m.recordChange(models.EntityIssue, c.IssueID, false)
+	return nil
+}
+
+// checkLabels enforces the issue_labels primary key
+func checkLabels(labelIDs []string) error {
+	seen := make(map[string]bool, len(labelIDs))
+	for _, id := range labelIDs {
+		if seen[id] {
+			return fmt.Errorf("failed to insert label: %w: duplicate label %s", ErrConflict, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func (m *MemoryStore) DeleteIssue(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok || issue.DeletedAt != nil {
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+	now := time.Now()
+	issue.DeletedAt = &now
+	issue.UpdatedAt = now
+	m.recordChange(models.EntityIssue, id, true)
+	return nil
+}
+
+func (m *MemoryStore) RestoreIssue(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok || issue.DeletedAt == nil {
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+	issue.DeletedAt = nil
+	issue.UpdatedAt = time.Now()
+	m.recordChange(models.EntityIssue, id, false)
+	return nil
+}
+
+func (m *MemoryStore) ArchiveIssue(ctx context.Context, id string) error {
+	return m.setArchived(id, true)
+}
+
+func (m *MemoryStore) UnarchiveIssue(ctx context.Context, id string) error {
+	return m.setArchived(id, false)
+}
+
+func (m *MemoryStore) setArchived(id string, archived bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	issue, ok := m.issues[id]
+	if !ok || issue.DeletedAt != nil {
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+	if (issue.ArchivedAt != nil) == archived {
+		return nil
+	}
+	now := time.Now()
+	issue.ArchivedAt = nil
+	if archived {
+		issue.ArchivedAt = &now
+	}
+	issue.UpdatedAt = now
+	m.recordChange(models.EntityIssue, id, false)
+	return nil
+}
+
+func (m *MemoryStore) ArchiveStale(ctx context.Context, statuses []string, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Archive in a stable order so the change log is deterministic
+	var ids []string
+	for id, issue := range m.issues {
+		if issue.DeletedAt == nil && issue.ArchivedAt == nil && contains(statuses, issue.Status) && m.closedAt(issue, statuses).Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	now := time.Now()
+	for _, id := range ids {
+		issue := m.issues[id]
+		issue.ArchivedAt = &now
+		issue.UpdatedAt = now
+		m.recordChange(models.EntityIssue, id, false)
+	}
+	return int64(len(ids)), nil
+}
+
+// closedAt returns when issue last moved into one of statuses, or its
+// updated_at without such a history entry, like Repository.ArchiveStale
+func (m *MemoryStore) closedAt(issue *models.Issue, statuses []string) time.Time {
+	var at time.Time
+	for _, c := range m.history {
+		if c.IssueID == issue.ID && contains(statuses, c.To) && c.ChangedAt.After(at) {
+			at = c.ChangedAt
+		}
+	}
+	if at.IsZero() {
+		return issue.UpdatedAt
+	}
+	return at
+}
+
+func (m *MemoryStore) PurgeIssue(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.issues[id]; !ok {
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+	m.purge(map[string]bool{id: true})
+	m.recordChange(models.EntityIssue, id, true)
+	return nil
+}
+
+func (m *MemoryStore) PurgeTrash(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make(map[string]bool)
+	for id, issue := range m.issues {
+		if issue.DeletedAt != nil && issue.DeletedAt.Before(cutoff) {
+			ids[id] = true
+		}
+	}
+	m.purge(ids)
+	return int64(len(ids)), nil
+}
+
+// purge removes issues with their labels and history, as the schema's
+// cascades do. Callers must hold the write lock.
+func (m *MemoryStore) purge(ids map[string]bool) {
+	if len(ids) == 0 {
+		return
+	}
+	for id := range ids {
+		delete(m.issues, id)
+		delete(m.issueLabels, id)
+		delete(m.comments, id)
+	}
+	for _, issue := range m.issues {
+		if issue.ParentID != nil && ids[*issue.ParentID] {
+			issue.ParentID = nil
+		}
+	}
+
+	history := m.history[:0]
+	for _, c := range m.history {
+		if !ids[c.IssueID] {
+			history = append(history, c)
+		}
+	}
+	m.history = history
+}
+
+func (m *MemoryStore) CountIssues(ctx context.Context, f models.IssueFilter, groupBy []string) ([]models.GroupCount, error) {
+	for _, dim := range groupBy {
+		if _, err := groupExpr(dim); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]*models.GroupCount)
+	var order []string
+	for _, issue := range m.filterIssues(f) {
+		// An issue contributes once to every combination of its dimension values
+		combos := [][]*string{{}}
+		for _, dim := range groupBy {
+			values := m.groupValues(issue, dim)
+			var next [][]*string
+			for _, combo := range combos {
+				for _, v := range values {
+					next = append(next, append(append([]*string{}, combo...), v))
+				}
+			}
+			combos = next
+		}
+
+		for _, combo := range combos {
+			key := groupKey(combo)
+			c, ok := counts[key]
+			if !ok {
+				c = &models.GroupCount{Keys: combo}
+				counts[key] = c
+				order = append(order, key)
+			}
+			c.Count++
+		}
+	}
+
+	if len(groupBy) == 0 && len(order) == 0 {
+		return []models.GroupCount{{Keys: []*string{}, Count: 0}}, nil
+	}
+
+	result := make([]models.GroupCount, 0, len(order))
+	for _, key := range order {
+		result = append(result, *counts[key])
+	}
+	return result, nil
+}
+
+// groupValues returns the values of a stats dimension for an issue; nil
+// stands for unassigned or unlabelled. Callers must hold the read lock.
+func (m *MemoryStore) groupValues(issue *models.Issue, dim string) []*string {
+	switch dim {
+	case models.GroupByStatus:
+		return []*string{copyString(&issue.Status)}
+	case models.GroupByPriority:
+		return []*string{copyString(&issue.Priority)}
+	case models.GroupByAssignee:
+		return []*string{copyString(issue.AssigneeID)}
+	default:
+		var names []*string
+		seen := make(map[string]bool)
+		for _, l := range m.labelsFor(issue.ID) {
+			if !seen[l.Name] {
+				seen[l.Name] = true
+				names = append(names, copyString(&l.Name))
+			}
+		}
+		if len(names) == 0 {
+			return []*string{nil}
+		}
+		return names
+	}
+}
+
+func groupKey(keys []*string) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if k == nil {
+			parts[i] = "\x00"
+		} else {
+			parts[i] = "=" + *k
+		}
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+func (m *MemoryStore) GetStatusHistory(ctx context.Context, f models.IssueFilter) ([]models.StatusChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matched := make(map[string]bool)
+	for _, issue := range m.filterIssues(f) {
+		matched[issue.ID] = true
+	}
+
+	var history []models.StatusChange
+	for _, c := range m.history {
+		if matched[c.IssueID] {
+			c.From = copyString(c.From)
+			history = append(history, c)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		if history[i].IssueID != history[j].IssueID {
+			return history[i].IssueID < history[j].IssueID
+		}
+		return history[i].ChangedAt.Before(history[j].ChangedAt)
+	})
+	return history, nil
+}
+
+func (m *MemoryStore) GetUsers(ctx context.Context) ([]models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.users) == 0 {
+		return nil, nil
+	}
+	users := make([]models.User, len(m.users))
+	copy(users, m.users)
+	return users, nil
+}
+
+func (m *MemoryStore) GetUsersByID(ctx context.Context, ids []string) ([]models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var users []models.User
+	for _, u := range m.users {
+		if contains(ids, u.ID) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (m *MemoryStore) CreateUser(ctx context.Context, user models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.user(user.ID); ok {
+		return fmt.Errorf("failed to create user: %w: duplicate id %s", ErrConflict, user.ID)
+	}
+	m.users = append(m.users, user)
+	m.recordChange(models.EntityUser, user.ID, false)
+	return nil
+}
+
+func (m *MemoryStore) GetUserRole(ctx context.Context, userID, projectID string) (models.Role, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if role, ok := m.roles[roleScope{userID, projectID}]; ok {
+		return role, nil
+	}
+	return m.roles[roleScope{userID, ""}], nil
+}
+
+func (m *MemoryStore) SetUserRole(ctx context.Context, a models.RoleAssignment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.user(a.UserID); !ok {
+		return fmt.Errorf("failed to set user role: %w: unknown user %s", ErrConstraint, a.UserID)
+	}
+	m.roles[roleScope{a.UserID, a.ProjectID}] = a.Role
+	return nil
+}
+
+func (m *MemoryStore) GetUser(ctx context.Context, id string) (*models.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.user(id)
+	if !ok {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+func (m *MemoryStore) CreateAccount(ctx context.Context, a models.Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a.Email = strings.ToLower(a.Email)
+	if _, ok := m.user(a.UserID); !ok {
+		return fmt.Errorf("failed to create account: %w: unknown user %s", ErrConstraint, a.UserID)
+	}
+	if _, ok := m.accounts[a.Email]; ok {
+		return fmt.Errorf("failed to create account: %w: duplicate email %s", ErrConflict, a.Email)
+	}
+	for _, existing := range m.accounts {
+		if existing.UserID == a.UserID {
+			return fmt.Errorf("failed to create account: %w: user %s already has one", ErrConflict, a.UserID)
+		}
+	}
+	m.accounts[a.Email] = a
+	return nil
+}
+
+func (m *MemoryStore) GetAccountByEmail(ctx context.Context, email string) (*models.Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a, ok := m.accounts[strings.ToLower(email)]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+func (m *MemoryStore) GetIdentity(ctx context.Context, issuer, subject string) (*models.Identity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	userID, ok := m.identities[[2]string{issuer, subject}]
+	if !ok {
+		return nil, nil
+	}
+	return &models.Identity{Issuer: issuer, Subject: subject, UserID: userID}, nil
+}
+
+func (m *MemoryStore) CreateIdentity(ctx context.Context, id models.Identity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]string{id.Issuer, id.Subject}
+	if _, ok := m.identities[key]; ok {
+		return fmt.Errorf("failed to create identity: %w: duplicate subject %s", ErrConflict, id.Subject)
+	}
+	if _, ok := m.user(id.UserID); !ok {
+		return fmt.Errorf("failed to create identity: %w: unknown user %s", ErrConstraint, id.UserID)
+	}
+	m.identities[key] = id.UserID
+	return nil
+}
+
+func (m *MemoryStore) CreateSession(ctx context.Context, s models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[s.ID]; ok {
+		return fmt.Errorf("failed to create session: %w: duplicate id", ErrConflict)
+	}
+	m.sessions[s.ID] = s
+	return nil
+}
+
+func (m *MemoryStore) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (m *MemoryStore) DeleteSession(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *MemoryStore) GetLabels(ctx context.Context) ([]models.Label, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.labels) == 0 {
+		return nil, nil
+	}
+	labels := make([]models.Label, len(m.labels))
+	copy(labels, m.labels)
+	return labels, nil
+}
+
+func (m *MemoryStore) CreateLabel(ctx context.Context, label models.Label) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.label(label.ID); ok {
+		return fmt.Errorf("failed to create label: %w: duplicate id %s", ErrConflict, label.ID)
+	}
+	m.labels = append(m.labels, label)
+	m.recordChange(models.EntityLabel, label.ID, false)
+	return nil
+}
+
+func (m *MemoryStore) ListTemplates(ctx context.Context) ([]models.IssueTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	templates := make([]models.IssueTemplate, 0, len(m.templates))
+	for _, t := range m.templates {
+		templates = append(templates, copyTemplate(t))
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+func (m *MemoryStore) GetTemplate(ctx context.Context, id string) (*models.IssueTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.templates[id]
+	if !ok {
+		return nil, nil
+	}
+	t = copyTemplate(t)
+	return &t, nil
+}
+
+func (m *MemoryStore) CreateTemplate(ctx context.Context, t models.IssueTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.templates[t.ID]; exists {
+		return fmt.Errorf("failed to write template: %w: duplicate id %s", ErrConflict, t.ID)
+	}
+	return m.putTemplate(t)
+}
+
+func (m *MemoryStore) UpdateTemplate(ctx context.Context, t models.IssueTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.templates[t.ID]
+	if !ok {
+		return fmt.Errorf("template %s: %w", t.ID, ErrNotFound)
+	}
+	t.CreatedAt = existing.CreatedAt
+	return m.putTemplate(t)
+}
+
+// putTemplate stores a template, enforcing the constraints of the
+// issue_templates tables. Callers must hold the write lock.
+func (m *MemoryStore) putTemplate(t models.IssueTemplate) error {
+	if err := m.checkReferences(nil, t.LabelIDs); err != nil {
+		return err
+	}
+	if err := checkLabels(t.LabelIDs); err != nil {
+		return err
+	}
+	if t.Type != "" && !contains(models.ValidTypes, t.Type) {
+		return fmt.Errorf("failed to write template: %w: invalid type %s", ErrConstraint, t.Type)
+	}
+	for id, other := range m.templates {
+		if id != t.ID && other.Name == t.Name {
+			return fmt.Errorf("failed to write template: %w: duplicate name %s", ErrConflict, t.Name)
+		}
+	}
+	m.templates[t.ID] = copyTemplate(t)
+	return nil
+}
+
+func (m *MemoryStore) DeleteTemplate(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.templates[id]; !ok {
+		return fmt.Errorf("template %s: %w", id, ErrNotFound)
+	}
+	delete(m.templates, id)
+	return nil
+}
+
+// copyTemplate returns a copy of t with its label IDs in the order the
+// Repository returns them
+func copyTemplate(t models.IssueTemplate) models.IssueTemplate {
+	if t.LabelIDs != nil {
+		t.LabelIDs = append([]string(nil), t.LabelIDs...)
+		sort.Strings(t.LabelIDs)
+	}
+	if len(t.LabelIDs) == 0 {
+		t.LabelIDs = nil
+	}
+	return t
+}
+
+func (m *MemoryStore) ListFields(ctx context.Context) ([]models.CustomField, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fields := make([]models.CustomField, 0, len(m.fields))
+	for _, f := range m.fields {
+		fields = append(fields, copyField(f))
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields, nil
+}
+
+func (m *MemoryStore) GetField(ctx context.Context, id string) (*models.CustomField, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, ok := m.fields[id]
+	if !ok {
+		return nil, nil
+	}
+	f = copyField(f)
+	return &f, nil
+}
+
+func (m *MemoryStore) CreateField(ctx context.Context, f models.CustomField) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.fields[f.ID]; exists {
+		return fmt.Errorf("failed to create custom field: %w: duplicate id %s", ErrConflict, f.ID)
+	}
+	if _, exists := m.fieldByKey(f.Key); exists {
+		return fmt.Errorf("failed to create custom field: %w: duplicate key %s", ErrConflict, f.Key)
+	}
+	if !contains(models.FieldTypes, f.Type) {
+		return fmt.Errorf("failed to create custom field: %w: CHECK constraint failed: invalid type %q", ErrConstraint, f.Type)
+	}
+	m.fields[f.ID] = copyField(f)
+	return nil
+}
+
+func (m *MemoryStore) UpdateField(ctx context.Context, f models.CustomField) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.fields[f.ID]
+	if !ok {
+		return fmt.Errorf("custom field %s: %w", f.ID, ErrNotFound)
+	}
+	existing.Name = f.Name
+	existing.Options = f.Options
+	existing.UpdatedAt = f.UpdatedAt
+	m.fields[f.ID] = copyField(existing)
+	return nil
+}
+
+// DeleteField deletes a custom field and, as the schema's cascade does,
+// its values
+func (m *MemoryStore) DeleteField(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.fields[id]
+	if !ok {
+		return fmt.Errorf("custom field %s: %w", id, ErrNotFound)
+	}
+	delete(m.fields, id)
+
+	var issueIDs []string
+	for issueID, issue := range m.issues {
+		if _, ok := issue.CustomFields[f.Key]; ok {
+			delete(issue.CustomFields, f.Key)
+			issueIDs = append(issueIDs, issueID)
+		}
+	}
+	sort.Strings(issueIDs)
+	for _, issueID := range issueIDs {
+		m.recordChange(models.EntityIssue, issueID, false)
+	}
+	return nil
+}
+
+// fieldByKey returns the custom field with the given key. Callers must
+// hold the lock.
+func (m *MemoryStore) fieldByKey(key string) (models.CustomField, bool) {
+	for _, f := range m.fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return models.CustomField{}, false
+}
+
+// checkFields returns a constraint violation, like Repository, if values
+// set a custom field that does not exist. Callers must hold the lock.
+func (m *MemoryStore) checkFields(values models.FieldValues) error {
+	var unknown []string
+	for key := range values {
+		if _, ok := m.fieldByKey(key); !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%w: unknown custom fields: %s", ErrConstraint, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// matchesFields reports whether an issue satisfies every custom field filter
+func matchesFields(issue *models.Issue, filters []models.CustomFieldFilter) bool {
+	for _, f := range filters {
+		matched := false
+		for _, v := range models.StoredValues(issue.CustomFields[f.Field.Key]) {
+			matched = matched || contains(f.Values, v)
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeFieldValues returns a copy of values with updates applied, where a
+// nil value or no options clears the field. It returns nil when no values are left.
+func mergeFieldValues(values, updates models.FieldValues) models.FieldValues {
+	merged := make(models.FieldValues, len(values)+len(updates))
+	for _, src := range []models.FieldValues{values, updates} {
+		for key, value := range src {
+			options, multi := value.([]string)
+			if value == nil || (multi && len(options) == 0) {
+				delete(merged, key)
+				continue
+			}
+			if multi {
+				options = append([]string(nil), options...)
+				sort.Strings(options)
+				value = options
+			}
+			merged[key] = value
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// copyField returns a copy of f shaped like the Repository returns it
+func copyField(f models.CustomField) models.CustomField {
+	if len(f.Options) == 0 {
+		f.Options = nil
+	} else {
+		f.Options = append([]string(nil), f.Options...)
+	}
+	return f
+}
+
+func (m *MemoryStore) ListIssueTypes(ctx context.Context) ([]models.IssueType, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	types := make([]models.IssueType, 0, len(m.issueTypes))
+	for _, key := range models.ValidTypes {
+		if t, ok := m.issueTypes[key]; ok {
+			types = append(types, copyIssueType(t))
+		}
+	}
+	return types, nil
+}
+
+func (m *MemoryStore) GetIssueType(ctx context.Context, key string) (*models.IssueType, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.issueTypes[key]
+	if !ok {
+		return nil, nil
+	}
+	t = copyIssueType(t)
+	return &t, nil
+}
+
+func (m *MemoryStore) UpdateIssueType(ctx context.Context, t models.IssueType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.issueTypes[t.Key]; !ok {
+		return fmt.Errorf("issue type %s: %w", t.Key, ErrNotFound)
+	}
+	m.issueTypes[t.Key] = copyIssueType(t)
+	return nil
+}
+
+// copyIssueType returns a copy of t shaped like the Repository returns it
+func copyIssueType(t models.IssueType) models.IssueType {
+	t.RequiredFields = append([]string{}, t.RequiredFields...)
+	t.Statuses = append([]string{}, t.Statuses...)
+	return t
+}
+
+func (m *MemoryStore) GetBoardVersion(ctx context.Context) (models.BoardVersion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.version, nil
+}
+
+// recordChange appends a write to the change log and advances the board
+// version. Callers must hold the write lock.
+func (m *MemoryStore) recordChange(entity, id string, deleted bool) {
+	now := time.Now().UTC()
+	m.version.Version++
+	m.version.UpdatedAt = now
+	m.changes = append(m.changes, models.Change{
+		Seq:       int64(len(m.changes)) + 1,
+		Entity:    entity,
+		EntityID:  id,
+		Deleted:   deleted,
+		ChangedAt: now,
+	})
+}
+
+func (m *MemoryStore) GetChangeSeq(ctx context.Context) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.changes)), nil
+}
+
+func (m *MemoryStore) GetEntityChangeSeq(ctx context.Context, entity, id string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.changes) - 1; i >= 0; i-- {
+		if c := m.changes[i]; c.Entity == entity && c.EntityID == id {
+			return c.Seq, nil
+		}
+	}
+	return 0, nil
+}
+
+func (m *MemoryStore) GetChanges(ctx context.Context, since int64, limit int) ([]models.Change, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Keep only the latest change to each entity
+	latest := make(map[[2]string]int64)
+	for _, c := range m.changes {
+		latest[[2]string{c.Entity, c.EntityID}] = c.Seq
+	}
+	var changes []models.Change
+	for _, c := range m.changes {
+		if c.Seq <= since || latest[[2]string{c.Entity, c.EntityID}] != c.Seq {
+			continue
+		}
+		if len(changes) == limit {
+			break
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// hydrate returns a copy of the issue with its assignee populated.
+// Callers must hold the read lock.
+func (m *MemoryStore) hydrate(issue *models.Issue) models.Issue {
+	i := *issue
+	i.AssigneeID = copyString(issue.AssigneeID)
+	i.ParentID = copyString(issue.ParentID)
+	if issue.DeletedAt != nil {
+		deletedAt := *issue.DeletedAt
+		i.DeletedAt = &deletedAt
+	}
+	if issue.ArchivedAt != nil {
+		archivedAt := *issue.ArchivedAt
+		i.ArchivedAt = &archivedAt
+	}
+	if i.AssigneeID != nil {
+		if u, ok := m.user(*i.AssigneeID); ok {
+			i.Assignee = &u
+		}
+	}
+	i.CustomFields = mergeFieldValues(nil, issue.CustomFields)
+	return i
+}
+
+// labelsFor returns the labels attached to an issue ordered by name, matching
+// the SQL implementation. Callers must hold the read lock.
+func (m *MemoryStore) labelsFor(issueID string) []models.Label {
+	var labels []models.Label
+	for _, id := range m.issueLabels[issueID] {
+		if l, ok := m.label(id); ok {
+			labels = append(labels, l)
+		}
+	}
+	sort.SliceStable(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+func (m *MemoryStore) hasAnyLabelName(issueID string, names []string) bool {
+	for _, id := range m.issueLabels[issueID] {
+		if l, ok := m.label(id); ok && contains(names, l.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryStore) user(id string) (models.User, bool) {
+	for _, u := range m.users {
+		if u.ID == id {
+			return u, true
+		}
+	}
+	return models.User{}, false
+}
+
+func (m *MemoryStore) label(id string) (models.Label, bool) {
+	for _, l := range m.labels {
+		if l.ID == id {
+			return l, true
+		}
+	}
+	return models.Label{}, false
+}
+
+// checkIssue enforces the CHECK constraints declared by the SQL schema
+func checkIssue(issue *models.Issue) error {
+	if !contains(models.ValidStatuses, issue.Status) {
+		return fmt.Errorf("%w: CHECK constraint failed: invalid status %q", ErrConstraint, issue.Status)
+	}
+	if !contains(models.ValidPriorities, issue.Priority) {
+		return fmt.Errorf("%w: CHECK constraint failed: invalid priority %q", ErrConstraint, issue.Priority)
+	}
+	if !contains(models.ValidTypes, issue.Type) {
+		return fmt.Errorf("%w: CHECK constraint failed: invalid type %q", ErrConstraint, issue.Type)
+	}
+	return nil
+}
+
+// setIssueColumn applies a Repository.UpdateIssue style column update
+func setIssueColumn(issue *models.Issue, column string, value interface{}) error {
+	switch column {
+	case "title", "description", "status", "priority", "type":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("invalid value for %s: %v", column, value)
+		}
+		switch column {
+		case "title":
+			issue.Title = s
+		case "description":
+			issue.Description = s
+		case "status":
+			issue.Status = s
+		case "priority":
+			issue.Priority = s
+		case "type":
+			issue.Type = s
+		}
+	case "parent_id":
+		switch v := value.(type) {
+		case string:
+			issue.ParentID = &v
+		case *string:
+			issue.ParentID = copyString(v)
+		case nil:
+			issue.ParentID = nil
+		default:
+			return fmt.Errorf("invalid value for %s: %v", column, value)
+		}
+	case "assignee_id":
+		switch v := value.(type) {
+		case string:
+			issue.AssigneeID = &v
+		case *string:
+			issue.AssigneeID = copyString(v)
+		case nil:
+			issue.AssigneeID = nil
+		default:
+			return fmt.Errorf("invalid value for %s: %v", column, value)
+		}
+	case "order_index":
+		f, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("invalid value for %s: %v", column, value)
+		}
+		issue.OrderIndex = f
+	case customFieldsUpdate:
+		values, ok := value.(models.FieldValues)
+		if !ok {
+			return fmt.Errorf("invalid value for %s: %v", column, value)
+		}
+		issue.CustomFields = mergeFieldValues(issue.CustomFields, values)
+	case "created_at", "updated_at":
+		t, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("invalid value for %s: %v", column, value)
+		}
+		if column == "created_at" {
+			issue.CreatedAt = t
+		} else {
+			issue.UpdatedAt = t
+		}
+	default:
+		return fmt.Errorf("no such column: %s", column)
+	}
+	return nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.CreateLabel(ctx, models.Label{ID: "bug", Name: "Bug", Color: "#FF0000"}); err != nil {
+		t.Fatalf("Failed to create label: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				id := fmt.Sprintf("issue-%d-%d", w, i)
+				err := store.CreateIssue(ctx, models.Issue{
+					ID:         id,
+					Title:      id,
+					Status:     "Todo",
+					Priority:   "Low",
+					CreatedAt:  time.Now(),
+					UpdatedAt:  time.Now(),
+					OrderIndex: float64(i),
+				})
+				if err != nil {
+					t.Errorf("Failed to create issue: %v", err)
+					return
+				}
+				store.UpdateIssueLabels(ctx, id, []string{"bug"})
+				store.UpdateIssue(ctx, id, map[string]interface{}{"status": "Done"})
+				store.GetIssues(ctx, []string{"Done"}, "", nil, []string{"Bug"}, 1, 10)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	issues, err := store.GetIssues(ctx, []string{"Done"}, "", nil, []string{"Bug"}, 1, 0)
+	if err != nil {
+		t.Fatalf("Failed to list issues: %v", err)
+	}
+	if len(issues) != 200 {
+		t.Errorf("Expected 200 issues, got %d", len(issues))
+	}
+}
+
+func TestMemoryStoreReturnsCopies(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	store.CreateIssue(ctx, models.Issue{ID: "1", Title: "Original", Status: "Todo", Priority: "Low"})
+
+	issue, _ := store.GetIssue(ctx, "1")
+	issue.Title = "Mutated"
+
+	again, _ := store.GetIssue(ctx, "1")
+	if again.Title != "Original" {
+		t.Errorf("Expected stored issue to be unaffected by caller mutation, got '%s'", again.Title)
+	}
+}
+
+func TestMemoryStoreEnforcesConstraints(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if err := store.CreateIssue(ctx, models.Issue{ID: "1", Title: "Bad", Status: "Nope", Priority: "Low"}); err == nil {
+		t.Error("Expected error for invalid status")
+	}
+
+	store.CreateIssue(ctx, models.Issue{ID: "2", Title: "Good", Status: "Todo", Priority: "Low"})
+	if err := store.CreateIssue(ctx, models.Issue{ID: "2", Title: "Dup", Status: "Todo", Priority: "Low"}); err == nil {
+		t.Error("Expected error for duplicate id")
+	}
+	if err := store.UpdateIssue(ctx, "2", map[string]interface{}{"priority": "Urgent"}); err == nil {
+		t.Error("Expected error for invalid priority")
+	}
+	if err := store.UpdateIssue(ctx, "2", map[string]interface{}{"bogus": 1}); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+
+	issue, _ := store.GetIssue(ctx, "2")
+	if issue.Priority != "Low" {
+		t.Errorf("Expected failed update to leave issue untouched, got priority '%s'", issue.Priority)
+	}
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// postgresDSN returns a server to run the conformance suite against. It uses
+// TEST_POSTGRES_DSN when set, otherwise it spawns a throwaway cluster with the
+// initdb/pg_ctl binaries found on PATH. The test is skipped if neither exists.
+func postgresDSN(t *testing.T) string {
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		return dsn
+	}
+
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		t.Skip("postgres not available: set TEST_POSTGRES_DSN or put initdb/pg_ctl on PATH")
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		t.Skip("postgres not available: pg_ctl not found on PATH")
+	}
+
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", dataDir, "-U", "postgres", "--auth=trust").CombinedOutput(); err != nil {
+		t.Fatalf("initdb failed: %v\n%s", err, out)
+	}
+
+	port := freePort(t)
+	opts := fmt.Sprintf("-p %d -k %s -c listen_addresses=''", port, dir)
+	if out, err := exec.Command(pgCtl, "-D", dataDir, "-o", opts, "-w", "start").CombinedOutput(); err != nil {
+		t.Fatalf("pg_ctl start failed: %v\n%s", err, out)
+	}
+	t.Cleanup(func() {
+		exec.Command(pgCtl, "-D", dataDir, "-m", "immediate", "-w", "stop").Run()
+	})
+
+	return fmt.Sprintf("host=%s port=%d user=postgres dbname=postgres sslmode=disable", dir, port)
+}
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestPostgresStoreConformance(t *testing.T) {
+	dsn := postgresDSN(t)
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open postgres: %v", err)
+	}
+	defer admin.Close()
+	if err := admin.Ping(); err != nil {
+		t.Fatalf("Failed to connect to postgres: %v", err)
+	}
+
+	var seq int64
+	runStoreSuite(t, func(t *testing.T) Store {
+		// Every test gets its own database so runs never observe each other
+		name := fmt.Sprintf("issue_board_test_%d_%d", os.Getpid(), atomic.AddInt64(&seq, 1))
+		if _, err := admin.Exec("CREATE DATABASE " + name); err != nil {
+			t.Fatalf("Failed to create database: %v", err)
+		}
+
+		connCfg, err := pgx.ParseConfig(dsn)
+		if err != nil {
+			t.Fatalf("Failed to parse dsn: %v", err)
+		}
+		connCfg.Database = name
+		db := stdlib.OpenDB(*connCfg)
+
+		t.Cleanup(func() {
+			db.Close()
+			admin.ExecContext(context.Background(), "DROP DATABASE IF EXISTS "+name)
+		})
+
+		if err := runMigrations(db, filepath.Join("..", "..", "migrations", DriverPostgres)); err != nil {
+			t.Fatalf("Failed to run migrations: %v", err)
+		}
+		return NewPostgresRepository(db)
+	})
+}
@@ -5,95 +5,142 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/abhir9/issue-board/api/internal/models"
 )
 
 type Repository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	dialect dialect
+	wip     models.WIPLimits
 }
 
+// NewRepository creates a repository backed by a SQLite database
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{DB: db}
+	return &Repository{DB: db, dialect: sqliteDialect{}}
 }
 
-// GetIssues retrieves issues with optional filters and pagination
-func (r *Repository) GetIssues(ctx context.Context, status []string, assigneeID string, priority []string, labels []string, page, pageSize int) ([]models.Issue, error) {
-	query := `
-		SELECT i.id, i.title, i.description, i.status, i.priority, i.assignee_id, i.created_at, i.updated_at, i.order_index,
-		       u.id, u.name, u.avatar_url
-		FROM issues i
-		LEFT JOIN users u ON i.assignee_id = u.id
-		WHERE 1=1
-	`
-	var args []interface{}
+// NewPostgresRepository creates a repository backed by a PostgreSQL database
+func NewPostgresRepository(db *sql.DB) *Repository {
+	return &Repository{DB: db, dialect: postgresDialect{}}
+}
 
-	if len(status) > 0 {
-		placeholders := make([]string, len(status))
-		for i, s := range status {
-			placeholders[i] = "?"
-			args = append(args, s)
-		}
-		query += fmt.Sprintf(" AND i.status IN (%s)", strings.Join(placeholders, ","))
+// rebind adapts a query written with "?" placeholders to the repository's dialect
+func (r *Repository) rebind(query string) string {
+	if r.dialect == nil {
+		return query
 	}
+	return r.dialect.Rebind(query)
+}
 
-	if assigneeID != "" {
-		query += " AND i.assignee_id = ?"
-		args = append(args, assigneeID)
+func (r *Repository) Ping(ctx context.Context) error {
+	return r.DB.PingContext(ctx)
+}
+
+// GetIssues retrieves issues with optional filters and pagination
+func (r *Repository) GetIssues(ctx context.Context, status []string, assigneeID string, priority []string, labels []string, page, pageSize int) ([]models.Issue, error) {
+	list, err := r.ListIssues(ctx, models.IssueQuery{
+		IssueFilter: models.IssueFilter{Status: status, AssigneeID: assigneeID, Priority: priority, Labels: labels},
+		Page:        page,
+		PageSize:    pageSize,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return list.Items, nil
+}
 
-	if len(priority) > 0 {
-		placeholders := make([]string, len(priority))
-		for i, p := range priority {
-			placeholders[i] = "?"
-			args = append(args, p)
-		}
-		query += fmt.Sprintf(" AND i.priority IN (%s)", strings.Join(placeholders, ","))
+// ListIssues retrieves a page of issues in the requested sort order (by default
+// order_index) using either offset or keyset pagination, together with the
+// total number of matches
+func (r *Repository) ListIssues(ctx context.Context, q models.IssueQuery) (*models.IssueList, error) {
+	keys := q.SortKeys()
+	expand := q.Expansions()
+
+	// Only load what was asked for: descriptions can be large and the users
+	// join is needed just for the assignee expansion or sorting by assignee
+	description := "i.description"
+	if !q.WantsField("description") {
+		description = "''"
+	}
+	joinUsers := expand.Assignee
+	for _, k := range keys {
+		joinUsers = joinUsers || k.Field == models.SortAssignee
+	}
+	userColumns, join := "NULL, NULL, NULL", ""
+	if joinUsers {
+		userColumns, join = "u.id, u.name, u.avatar_url", "LEFT JOIN users u ON i.assignee_id = u.id"
 	}
 
-	if len(labels) > 0 {
-		placeholders := make([]string, len(labels))
-		for i, l := range labels {
-			placeholders[i] = "?"
-			args = append(args, l)
+	query := fmt.Sprintf(`
+		SELECT i.id, i.title, %s, i.status, i.priority, i.type, i.assignee_id, i.parent_id, i.created_at, i.updated_at, i.order_index, i.deleted_at, i.archived_at,
+		       %s
+		FROM issues i
+		%s
+		WHERE 1=1
+	`, description, userColumns, join)
+	where, filterArgs := issueFilterClause(q.IssueFilter)
+	query += where
+	args := append([]interface{}{}, filterArgs...)
+
+	if q.Cursor != nil {
+		values, err := cursorValues(q.Cursor, keys)
+		if err != nil {
+			return nil, err
 		}
-		// Filter issues that have at least one of the specified labels (by label name)
-		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM issue_labels il JOIN labels l ON il.label_id = l.id WHERE il.issue_id = i.id AND l.name IN (%s))", strings.Join(placeholders, ","))
+		after, afterArgs := keysetClause(keys, values, q.Cursor.ID)
+		query += after
+		args = append(args, afterArgs...)
 	}
 
-	query += " ORDER BY i.order_index ASC"
+	query += orderByClause(keys)
 
-	// Add pagination
-	if pageSize > 0 {
-		offset := (page - 1) * pageSize
+	// Add pagination; keyset pages fetch one extra row to detect a next page
+	switch {
+	case q.Keyset() && q.Limit > 0:
+		query += " LIMIT ?"
+		args = append(args, q.Limit+1)
+	case !q.Keyset() && q.PageSize > 0:
+		offset := (q.Page - 1) * q.PageSize
 		query += " LIMIT ? OFFSET ?"
-		args = append(args, pageSize, offset)
+		args = append(args, q.PageSize, offset)
 	}
 
-	rows, err := r.DB.QueryContext(ctx, query, args...)
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query issues: %w", err)
 	}
 	defer rows.Close()
 
-	var issues []models.Issue
+	issues := make([]models.Issue, 0)
 	issueIDs := make([]string, 0)
-	
+
 	for rows.Next() {
 		var i models.Issue
 		var u models.User
-		var assigneeID sql.NullString
+		var assigneeID, parentID sql.NullString
 		var userID sql.NullString
 		var userName sql.NullString
 		var userAvatar sql.NullString
+		var deletedAt, archivedAt sql.NullTime
 
 		err := rows.Scan(
-			&i.ID, &i.Title, &i.Description, &i.Status, &i.Priority, &assigneeID, &i.CreatedAt, &i.UpdatedAt, &i.OrderIndex,
+			&i.ID, &i.Title, &i.Description, &i.Status, &i.Priority, &i.Type, &assigneeID, &parentID, &i.CreatedAt, &i.UpdatedAt, &i.OrderIndex, &deletedAt, &archivedAt,
 			&userID, &userName, &userAvatar,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan issue: %w", err)
 		}
+		if deletedAt.Valid {
+			i.DeletedAt = &deletedAt.Time
+		}
+		if archivedAt.Valid {
+			i.ArchivedAt = &archivedAt.Time
+		}
+		if parentID.Valid {
+			i.ParentID = &parentID.String
+		}
 
 		if assigneeID.Valid {
 			i.AssigneeID = &assigneeID.String
@@ -113,24 +160,175 @@ func (r *Repository) GetIssues(ctx context.Context, status []string, assigneeID
 		return nil, fmt.Errorf("error iterating issues: %w", err)
 	}
 
+	list := &models.IssueList{Items: issues, TotalCount: len(issues)}
+	more := q.Keyset() && q.Limit > 0 && len(issues) > q.Limit
+	if more {
+		list.Items = issues[:q.Limit]
+		issueIDs = issueIDs[:q.Limit]
+	}
+
+	// Custom field values are also needed for the cursor of a custom sort
+	sortsByField := false
+	for _, k := range keys {
+		sortsByField = sortsByField || k.Custom != nil
+	}
+	if q.WantsField("custom_fields") || (more && sortsByField) {
+		values, err := r.fieldValues(ctx, issueIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			list.Items[i].CustomFields = values[list.Items[i].ID]
+		}
+	}
+
+	if more {
+		next := issueCursor(list.Items[q.Limit-1], keys).Encode()
+		list.NextCursor = &next
+	}
+	if !q.WantsField("custom_fields") {
+		for i := range list.Items {
+			list.Items[i].CustomFields = nil
+		}
+	}
+
+	// The join may only have been made for sorting
+	if !expand.Assignee {
+		for i := range list.Items {
+			list.Items[i].Assignee = nil
+		}
+	}
+
 	// Fetch all labels for all issues in one query (solves N+1 problem)
-	if len(issueIDs) > 0 {
+	if expand.Labels && len(issueIDs) > 0 {
 		labelMap, err := r.GetLabelsForIssues(ctx, issueIDs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch labels: %w", err)
 		}
 
 		// Attach labels to issues
-		for i := range issues {
-			if labels, ok := labelMap[issues[i].ID]; ok {
-				issues[i].Labels = labels
+		for i := range list.Items {
+			if labels, ok := labelMap[list.Items[i].ID]; ok {
+				list.Items[i].Labels = labels
 			} else {
-				issues[i].Labels = []models.Label{}
+				list.Items[i].Labels = []models.Label{}
 			}
 		}
 	}
 
-	return issues, nil
+	if expand.CommentsCount && len(issueIDs) > 0 {
+		counts, err := r.commentCounts(ctx, issueIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			count := counts[list.Items[i].ID]
+			list.Items[i].CommentsCount = &count
+		}
+	}
+	if expand.Children && len(issueIDs) > 0 {
+		children, err := r.childrenOf(ctx, issueIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			list.Items[i].Children = children[list.Items[i].ID]
+			if list.Items[i].Children == nil {
+				list.Items[i].Children = []models.IssueRef{}
+			}
+		}
+	}
+
+	if q.Keyset() || q.PageSize > 0 {
+		total, err := r.countIssues(ctx, where, filterArgs)
+		if err != nil {
+			return nil, err
+		}
+		list.TotalCount = total
+	}
+
+	return list, nil
+}
+
+// countIssues counts the issues matching a filter clause built by issueFilterClause
+func (r *Repository) countIssues(ctx context.Context, where string, args []interface{}) (int, error) {
+	var total int
+	query := "SELECT COUNT(*) FROM issues i WHERE 1=1" + where
+	if err := r.DB.QueryRowContext(ctx, r.rebind(query), args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count issues: %w", err)
+	}
+	return total, nil
+}
+
+// issueFilterClause builds the " AND ..." conditions for an issue filter over alias i
+func issueFilterClause(f models.IssueFilter) (string, []interface{}) {
+	var where strings.Builder
+	var args []interface{}
+
+	if f.Trashed {
+		where.WriteString(" AND i.deleted_at IS NOT NULL")
+	} else {
+		where.WriteString(" AND i.deleted_at IS NULL")
+	}
+	if !f.IncludeArchived {
+		where.WriteString(" AND i.archived_at IS NULL")
+	}
+
+	if len(f.IDs) > 0 {
+		where.WriteString(fmt.Sprintf(" AND i.id IN (%s)", placeholders(len(f.IDs))))
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
+
+	if len(f.Status) > 0 {
+		where.WriteString(fmt.Sprintf(" AND i.status IN (%s)", placeholders(len(f.Status))))
+		for _, s := range f.Status {
+			args = append(args, s)
+		}
+	}
+
+	if f.AssigneeID != "" {
+		where.WriteString(" AND i.assignee_id = ?")
+		args = append(args, f.AssigneeID)
+	}
+
+	if len(f.Priority) > 0 {
+		where.WriteString(fmt.Sprintf(" AND i.priority IN (%s)", placeholders(len(f.Priority))))
+		for _, p := range f.Priority {
+			args = append(args, p)
+		}
+	}
+
+	if len(f.Types) > 0 {
+		where.WriteString(fmt.Sprintf(" AND i.type IN (%s)", placeholders(len(f.Types))))
+		for _, t := range f.Types {
+			args = append(args, t)
+		}
+	}
+
+	if len(f.Labels) > 0 {
+		// Filter issues that have at least one of the specified labels (by label name)
+		where.WriteString(fmt.Sprintf(" AND EXISTS (SELECT 1 FROM issue_labels il JOIN labels l ON il.label_id = l.id WHERE il.issue_id = i.id AND l.name IN (%s))", placeholders(len(f.Labels))))
+		for _, l := range f.Labels {
+			args = append(args, l)
+		}
+	}
+
+	for _, cf := range f.CustomFields {
+		// Any of the values; a multi-select value matches on any of its options
+		where.WriteString(fmt.Sprintf(" AND EXISTS (SELECT 1 FROM issue_field_values v WHERE v.issue_id = i.id AND v.field_id = ? AND v.value IN (%s))", placeholders(len(cf.Values))))
+		args = append(args, cf.Field.ID)
+		for _, v := range cf.Values {
+			args = append(args, v)
+		}
+	}
+
+	return where.String(), args
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
 }
 
 func (r *Repository) GetLabelsForIssue(ctx context.Context, issueID string) ([]models.Label, error) {
@@ -140,7 +338,7 @@ func (r *Repository) GetLabelsForIssue(ctx context.Context, issueID string) ([]m
 		JOIN issue_labels il ON l.id = il.label_id
 		WHERE il.issue_id = ?
 	`
-	rows, err := r.DB.QueryContext(ctx, query, issueID)
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query labels for issue: %w", err)
 	}
@@ -178,7 +376,7 @@ func (r *Repository) GetLabelsForIssues(ctx context.Context, issueIDs []string)
 		ORDER BY il.issue_id, l.name
 	`, strings.Join(placeholders, ","))
 
-	rows, err := r.DB.QueryContext(ctx, query, args...)
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query labels for issues: %w", err)
 	}
@@ -201,35 +399,92 @@ func (r *Repository) GetLabelsForIssues(ctx context.Context, issueIDs []string)
 	return labelMap, nil
 }
 
+// CreateIssue inserts an issue together with its labels (only their IDs are
+// used). It returns a *ReferenceError if the assignee or a label does not exist.
 func (r *Repository) CreateIssue(ctx context.Context, issue models.Issue) error {
+	labelIDs := make([]string, len(issue.Labels))
+	for i, l := range issue.Labels {
+		labelIDs[i] = l.ID
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.checkReferences(ctx, tx, issue.AssigneeID, labelIDs); err != nil {
+		return err
+	}
+	if err := r.checkParent(ctx, tx, issue.ID, issue.ParentID); err != nil {
+		return err
+	}
+	if err := r.checkWIP(ctx, tx, nil, issue.Status, issue.AssigneeID); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO issues (id, title, description, status, priority, assignee_id, created_at, updated_at, order_index)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO issues (id, title, description, status, priority, type, assignee_id, parent_id, created_at, updated_at, order_index)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.DB.ExecContext(ctx, query, issue.ID, issue.Title, issue.Description, issue.Status, issue.Priority, issue.AssigneeID, issue.CreatedAt, issue.UpdatedAt, issue.OrderIndex)
+	_, err = tx.ExecContext(ctx, r.rebind(query), issue.ID, issue.Title, issue.Description, issue.Status, issue.Priority, issueType(issue), issue.AssigneeID, issue.ParentID, issue.CreatedAt, issue.UpdatedAt, issue.OrderIndex)
 	if err != nil {
-		return fmt.Errorf("failed to create issue: %w", err)
+		if refErr := r.parentError(ctx, err, issue.ID, issue.ParentID); refErr != nil {
+			return refErr
+		}
+		if refErr := r.referenceError(ctx, err, issue.AssigneeID, nil); refErr != nil {
+			return refErr
+		}
+		return r.writeError("failed to create issue", err)
+	}
+
+	if err := r.insertLabels(ctx, tx, issue.ID, labelIDs); err != nil {
+		return err
+	}
+	if err := r.writeFieldValues(ctx, tx, issue.ID, issue.CustomFields); err != nil {
+		return err
+	}
+
+	if err := r.recordStatusChange(ctx, tx, issue.ID, nil, issue.Status, issue.CreatedAt); err != nil {
+		return err
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, issue.ID, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// recordStatusChange appends an entry to the issue status history
+func (r *Repository) recordStatusChange(ctx context.Context, tx *sql.Tx, issueID string, from *string, to string, at time.Time) error {
+	query := "INSERT INTO issue_status_history (issue_id, from_status, to_status, changed_at) VALUES (?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, r.rebind(query), issueID, from, to, at); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
 	}
 	return nil
 }
 
 func (r *Repository) GetIssue(ctx context.Context, id string) (*models.Issue, error) {
 	query := `
-		SELECT i.id, i.title, i.description, i.status, i.priority, i.assignee_id, i.created_at, i.updated_at, i.order_index,
+		SELECT i.id, i.title, i.description, i.status, i.priority, i.type, i.assignee_id, i.parent_id, i.created_at, i.updated_at, i.order_index, i.archived_at,
 		       u.id, u.name, u.avatar_url
 		FROM issues i
 		LEFT JOIN users u ON i.assignee_id = u.id
-		WHERE i.id = ?
+		WHERE i.id = ? AND i.deleted_at IS NULL
 	`
 	var i models.Issue
 	var u models.User
-	var assigneeID sql.NullString
+	var assigneeID, parentID sql.NullString
 	var userID sql.NullString
 	var userName sql.NullString
 	var userAvatar sql.NullString
+	var archivedAt sql.NullTime
 
-	err := r.DB.QueryRowContext(ctx, query, id).Scan(
-		&i.ID, &i.Title, &i.Description, &i.Status, &i.Priority, &assigneeID, &i.CreatedAt, &i.UpdatedAt, &i.OrderIndex,
+	err := r.DB.QueryRowContext(ctx, r.rebind(query), id).Scan(
+		&i.ID, &i.Title, &i.Description, &i.Status, &i.Priority, &i.Type, &assigneeID, &parentID, &i.CreatedAt, &i.UpdatedAt, &i.OrderIndex, &archivedAt,
 		&userID, &userName, &userAvatar,
 	)
 	if err == sql.ErrNoRows {
@@ -238,6 +493,12 @@ func (r *Repository) GetIssue(ctx context.Context, id string) (*models.Issue, er
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue: %w", err)
 	}
+	if archivedAt.Valid {
+		i.ArchivedAt = &archivedAt.Time
+	}
+	if parentID.Valid {
+		i.ParentID = &parentID.String
+	}
 
 	if assigneeID.Valid {
 		i.AssigneeID = &assigneeID.String
@@ -255,44 +516,162 @@ func (r *Repository) GetIssue(ctx context.Context, id string) (*models.Issue, er
 	}
 	i.Labels = labels
 
+	values, err := r.fieldValues(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	i.CustomFields = values[id]
+
 	return &i, nil
 }
 
 func (r *Repository) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.UpdateIssueWithLabels(ctx, id, updates, nil)
+}
+
+// UpdateIssueWithLabels applies column updates and, unless labelIDs is nil,
+// replaces the issue's labels in one transaction. Custom field values are
+// set through a "custom_fields" update holding models.FieldValues. It
+// returns a *ReferenceError if the new assignee or a label does not exist.
+func (r *Repository) UpdateIssueWithLabels(ctx context.Context, id string, updates map[string]interface{}, labelIDs []string) error {
 	// Dynamic update query
 	query := "UPDATE issues SET "
 	var args []interface{}
 	var parts []string
 
 	for k, v := range updates {
+		if k == customFieldsUpdate {
+			continue
+		}
 		parts = append(parts, fmt.Sprintf("%s = ?", k))
 		args = append(args, v)
 	}
+	fieldValues := updatedFields(updates)
 
-	if len(parts) == 0 {
+	if len(parts) == 0 && labelIDs == nil && len(fieldValues) == 0 {
 		return nil
 	}
 
-	query += strings.Join(parts, ", ") + " WHERE id = ?"
+	query += strings.Join(parts, ", ") + " WHERE id = ? AND deleted_at IS NULL"
 	args = append(args, id)
 
-	result, err := r.DB.ExecContext(ctx, query, args...)
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update issue: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	assigneeID := updatedAssignee(updates)
+	if err := r.checkReferences(ctx, tx, assigneeID, labelIDs); err != nil {
+		return err
+	}
+	parentID := updatedParent(updates)
+	if err := r.checkParent(ctx, tx, id, parentID); err != nil {
+		return err
+	}
+	if err := r.checkUpdateWIP(ctx, tx, id, updates); err != nil {
+		return err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("issue not found")
+	// Remember the previous status to record the transition
+	newStatus, statusChanged := updates["status"].(string)
+	var oldStatus string
+	if statusChanged || len(parts) == 0 {
+		err := tx.QueryRowContext(ctx, r.rebind("SELECT status FROM issues WHERE id = ? AND deleted_at IS NULL"), id).Scan(&oldStatus)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get issue status: %w", err)
+		}
+	}
+
+	if len(parts) > 0 {
+		result, err := tx.ExecContext(ctx, r.rebind(query), args...)
+		if err != nil {
+			if refErr := r.parentError(ctx, err, id, parentID); refErr != nil {
+				return refErr
+			}
+			if refErr := r.referenceError(ctx, err, assigneeID, nil); refErr != nil {
+				return refErr
+			}
+			return r.writeError("failed to update issue", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+		}
+	}
+
+	if labelIDs != nil {
+		if err := r.replaceLabels(ctx, tx, id, labelIDs); err != nil {
+			return err
+		}
+	}
+	if err := r.writeFieldValues(ctx, tx, id, fieldValues); err != nil {
+		return err
+	}
+
+	if statusChanged && newStatus != oldStatus {
+		if err := r.recordStatusChange(ctx, tx, id, &oldStatus, newStatus, changedAt(updates)); err != nil {
+			return err
+		}
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, id, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// updatedAssignee returns the assignee set by an UpdateIssue style update
+func updatedAssignee(updates map[string]interface{}) *string {
+	switch v := updates["assignee_id"].(type) {
+	case string:
+		return &v
+	case *string:
+		return v
+	}
+	return nil
+}
+
+// updatedParent returns the parent set by an UpdateIssue style update, or
+// nil if it is unchanged or cleared
+func updatedParent(updates map[string]interface{}) *string {
+	switch v := updates["parent_id"].(type) {
+	case string:
+		return &v
+	case *string:
+		return v
+	}
+	return nil
+}
+
+// updatedFields returns the custom field values set by an UpdateIssue style
+// update
+func updatedFields(updates map[string]interface{}) models.FieldValues {
+	values, _ := updates[customFieldsUpdate].(models.FieldValues)
+	return values
+}
+
+// changedAt returns the time an update happened: its updated_at value when
+// set, otherwise now
+func changedAt(updates map[string]interface{}) time.Time {
+	if t, ok := updates["updated_at"].(time.Time); ok {
+		return t
+	}
+	return time.Now()
+}
+
 func (r *Repository) UpdateIssueLabels(ctx context.Context, issueID string, labelIDs []string) error {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -300,39 +679,332 @@ func (r *Repository) UpdateIssueLabels(ctx context.Context, issueID string, labe
 	}
 	defer tx.Rollback()
 
-	// Delete existing
-	_, err = tx.ExecContext(ctx, "DELETE FROM issue_labels WHERE issue_id = ?", issueID)
+	var exists int
+	err = tx.QueryRowContext(ctx, r.rebind("SELECT 1 FROM issues WHERE id = ? AND deleted_at IS NULL"), issueID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("issue %s: %w", issueID, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check issue: %w", err)
+	}
+	if err := r.checkReferences(ctx, tx, nil, labelIDs); err != nil {
+		return err
+	}
+	if err := r.replaceLabels(ctx, tx, issueID, labelIDs); err != nil {
+		return err
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, issueID, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceLabels sets the labels of an issue within tx
+func (r *Repository) replaceLabels(ctx context.Context, tx *sql.Tx, issueID string, labelIDs []string) error {
+	_, err := tx.ExecContext(ctx, r.rebind("DELETE FROM issue_labels WHERE issue_id = ?"), issueID)
 	if err != nil {
 		return fmt.Errorf("failed to delete existing labels: %w", err)
 	}
+	return r.insertLabels(ctx, tx, issueID, labelIDs)
+}
 
-	// Insert new
-	if len(labelIDs) > 0 {
-		stmt, err := tx.PrepareContext(ctx, "INSERT INTO issue_labels (issue_id, label_id) VALUES (?, ?)")
+// insertLabels attaches labels to an issue within tx
+func (r *Repository) insertLabels(ctx context.Context, tx *sql.Tx, issueID string, labelIDs []string) error {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, r.rebind("INSERT INTO issue_labels (issue_id, label_id) VALUES (?, ?)"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, labelID := range labelIDs {
+		if _, err := stmt.ExecContext(ctx, issueID, labelID); err != nil {
+			if refErr := r.referenceError(ctx, err, nil, labelIDs); refErr != nil {
+				return refErr
+			}
+			return r.writeError("failed to insert label", err)
+		}
+	}
+	return nil
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// checkReferences returns a *ReferenceError naming the assignee and labels
+// that do not exist
+func (r *Repository) checkReferences(ctx context.Context, q queryer, assigneeID *string, labelIDs []string) error {
+	var refErr ReferenceError
+	if assigneeID != nil {
+		missing, err := r.missingIDs(ctx, q, "users", []string{*assigneeID})
 		if err != nil {
-			return fmt.Errorf("failed to prepare statement: %w", err)
+			return err
+		}
+		if len(missing) > 0 {
+			refErr.AssigneeID = *assigneeID
 		}
-		defer stmt.Close()
+	}
+	missing, err := r.missingIDs(ctx, q, "labels", labelIDs)
+	if err != nil {
+		return err
+	}
+	refErr.LabelIDs = missing
 
-		for _, labelID := range labelIDs {
-			_, err = stmt.ExecContext(ctx, issueID, labelID)
-			if err != nil {
-				return fmt.Errorf("failed to insert label: %w", err)
-			}
+	if refErr.AssigneeID != "" || len(refErr.LabelIDs) > 0 {
+		return &refErr
+	}
+	return nil
+}
+
+// missingIDs returns the IDs among ids that have no row in table
+func (r *Repository) missingIDs(ctx context.Context, q queryer, table string, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id IN (%s)", table, placeholders(len(ids)))
+	rows, err := q.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool, len(ids))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s: %w", table, err)
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if !found[id] {
+			found[id] = true
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// referenceError converts a foreign key violation into a *ReferenceError.
+// References are checked before writing, so a violation means a row was
+// deleted concurrently; they are looked up again outside the transaction to
+// name it. It returns nil for other errors.
+func (r *Repository) referenceError(ctx context.Context, err error, assigneeID *string, labelIDs []string) error {
+	if r.dialect == nil || !r.dialect.ForeignKeyViolation(err) {
+		return nil
+	}
+	if checkErr := r.checkReferences(ctx, r.DB, assigneeID, labelIDs); checkErr != nil {
+		return checkErr
+	}
+	return &ReferenceError{}
+}
+
+// writeError describes a failed write and wraps it with ErrConflict or
+// ErrConstraint when the database rejected it because of a constraint
+func (r *Repository) writeError(msg string, err error) error {
+	if r.dialect != nil {
+		if kind := r.dialect.ConstraintKind(err); kind != nil {
+			return fmt.Errorf("%s: %w: %w", msg, kind, err)
 		}
 	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// DeleteIssue moves an issue to the trash
+func (r *Repository) DeleteIssue(ctx context.Context, id string) error {
+	now := time.Now()
+	return r.setDeletedAt(ctx, id, "UPDATE issues SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL", &now, now)
+}
+
+// RestoreIssue moves an issue out of the trash. It returns ErrNotFound
+// unless the issue is in the trash.
+func (r *Repository) RestoreIssue(ctx context.Context, id string) error {
+	return r.setDeletedAt(ctx, id, "UPDATE issues SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL", nil, time.Now())
+}
+
+// setDeletedAt runs a trash or restore update of an issue and records the
+// change: a tombstone when the issue leaves the board, an update when it
+// returns
+func (r *Repository) setDeletedAt(ctx context.Context, id, query string, deletedAt *time.Time, now time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.rebind(query), deletedAt, now, id)
+	if err != nil {
+		return r.writeError("failed to update issue", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, id, deletedAt != nil); err != nil {
+		return err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	return nil
+}
+
+// ArchiveIssue takes an issue off the board. Archiving an archived issue
+// changes nothing.
+func (r *Repository) ArchiveIssue(ctx context.Context, id string) error {
+	now := time.Now()
+	return r.setArchivedAt(ctx, id, "UPDATE issues SET archived_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL AND archived_at IS NULL", &now, now)
+}
+
+// UnarchiveIssue puts an archived issue back on the board. Unarchiving a
+// live issue changes nothing.
+func (r *Repository) UnarchiveIssue(ctx context.Context, id string) error {
+	return r.setArchivedAt(ctx, id, "UPDATE issues SET archived_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL AND archived_at IS NOT NULL", nil, time.Now())
+}
+
+// setArchivedAt runs an archive or unarchive update of an issue and records
+// the change. It returns ErrNotFound if the issue does not exist or is in
+// the trash.
+func (r *Repository) setArchivedAt(ctx context.Context, id, query string, archivedAt *time.Time, now time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.rebind(query), archivedAt, now, id)
+	if err != nil {
+		return r.writeError("failed to update issue", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		// Either already in the requested state or not there at all
+		var exists int
+		err := tx.QueryRowContext(ctx, r.rebind("SELECT 1 FROM issues WHERE id = ? AND deleted_at IS NULL"), id).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check issue: %w", err)
+		}
+		return nil
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, id, false); err != nil {
+		return err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
-func (r *Repository) DeleteIssue(ctx context.Context, id string) error {
-	result, err := r.DB.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", id)
+// ArchiveStale archives the live issues in one of statuses that last moved
+// into one of them before before, and returns how many there were. Edits
+// after closing an issue do not keep it on the board.
+func (r *Repository) ArchiveStale(ctx context.Context, statuses []string, before time.Time) (int64, error) {
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete issue: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Issues without such a history entry fall back to updated_at
+	var args []interface{}
+	for i := 0; i < 2; i++ {
+		for _, s := range statuses {
+			args = append(args, s)
+		}
+	}
+	args = append(args, before)
+	query := fmt.Sprintf(`
+		SELECT i.id FROM issues i
+		WHERE i.deleted_at IS NULL AND i.archived_at IS NULL AND i.status IN (%[1]s)
+		AND COALESCE((
+			SELECT MAX(h.changed_at) FROM issue_status_history h
+			WHERE h.issue_id = i.id AND h.to_status IN (%[1]s)
+		), i.updated_at) < ?
+	`, placeholders(len(statuses)))
+	rows, err := tx.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query stale issues: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating stale issues: %w", err)
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, r.rebind("UPDATE issues SET archived_at = ?, updated_at = ? WHERE id = ?"), now, now, id); err != nil {
+			return 0, r.writeError("failed to archive issue", err)
+		}
+		if err := r.recordChange(ctx, tx, models.EntityIssue, id, false); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return int64(len(ids)), nil
+}
+
+// PurgeIssue permanently deletes an issue, whether or not it is in the
+// trash. Its labels and status history go with it.
+func (r *Repository) PurgeIssue(ctx context.Context, id string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.rebind("DELETE FROM issues WHERE id = ?"), id)
+	if err != nil {
+		return r.writeError("failed to delete issue", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -341,12 +1013,33 @@ func (r *Repository) DeleteIssue(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("issue not found")
+		return fmt.Errorf("issue %s: %w", id, ErrNotFound)
+	}
+	if err := r.recordChange(ctx, tx, models.EntityIssue, id, true); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
+// PurgeTrash permanently deletes the issues moved to the trash before
+// cutoff and returns how many there were. They already left the board when
+// trashed, so no change is recorded.
+func (r *Repository) PurgeTrash(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.DB.ExecContext(ctx, r.rebind("DELETE FROM issues WHERE deleted_at IS NOT NULL AND deleted_at < ?"), cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n, nil
+}
+
 func (r *Repository) GetUsers(ctx context.Context) ([]models.User, error) {
 	rows, err := r.DB.QueryContext(ctx, "SELECT id, name, avatar_url FROM users")
 	if err != nil {
@@ -374,6 +1067,46 @@ func (r *Repository) GetUsers(ctx context.Context) ([]models.User, error) {
 	return users, nil
 }
 
+// GetUsersByID returns the users with the given IDs, skipping unknown ones
+func (r *Repository) GetUsersByID(ctx context.Context, ids []string) ([]models.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := fmt.Sprintf("SELECT id, name, avatar_url FROM users WHERE id IN (%s)", placeholders(len(ids)))
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		var avatarURL sql.NullString
+		if err := rows.Scan(&u.ID, &u.Name, &avatarURL); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if avatarURL.Valid {
+			u.AvatarURL = avatarURL.String
+		}
+		users = append(users, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *Repository) CreateUser(ctx context.Context, user models.User) error {
+	return r.insertAndRecord(ctx, models.EntityUser, user.ID, "failed to create user", "INSERT INTO users (id, name, avatar_url) VALUES (?, ?, ?)", user.ID, user.Name, user.AvatarURL)
+}
+
 func (r *Repository) GetLabels(ctx context.Context) ([]models.Label, error) {
 	rows, err := r.DB.QueryContext(ctx, "SELECT id, name, color FROM labels")
 	if err != nil {
@@ -396,3 +1129,29 @@ func (r *Repository) GetLabels(ctx context.Context) ([]models.Label, error) {
 
 	return labels, nil
 }
+
+func (r *Repository) CreateLabel(ctx context.Context, label models.Label) error {
+	return r.insertAndRecord(ctx, models.EntityLabel, label.ID, "failed to create label", "INSERT INTO labels (id, name, color) VALUES (?, ?, ?)", label.ID, label.Name, label.Color)
+}
+
+// insertAndRecord runs a single-row insert of an entity and records the
+// change in one transaction. msg describes a failed insert.
+func (r *Repository) insertAndRecord(ctx context.Context, entity, id, msg, query string, args ...interface{}) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.rebind(query), args...); err != nil {
+		return r.writeError(msg, err)
+	}
+	if err := r.recordChange(ctx, tx, entity, id, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -16,43 +17,8 @@ func setupTestDB(t *testing.T) *Repository {
 		t.Fatalf("Failed to open in-memory db: %v", err)
 	}
 
-	schema := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		avatar_url TEXT
-	);
-
-	CREATE TABLE labels (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		color TEXT NOT NULL
-	);
-
-	CREATE TABLE issues (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		description TEXT,
-		status TEXT NOT NULL,
-		priority TEXT NOT NULL,
-		assignee_id TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		order_index REAL NOT NULL DEFAULT 0,
-		FOREIGN KEY (assignee_id) REFERENCES users(id)
-	);
-
-	CREATE TABLE issue_labels (
-		issue_id TEXT NOT NULL,
-		label_id TEXT NOT NULL,
-		PRIMARY KEY (issue_id, label_id),
-		FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
-		FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
-	);
-	`
-	_, err = db.Exec(schema)
-	if err != nil {
-		t.Fatalf("Failed to create schema: %v", err)
+	if err := runMigrations(db, filepath.Join("..", "..", "migrations", DriverSQLite)); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	return NewRepository(db)
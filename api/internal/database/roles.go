@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// GetUserRole returns the user's role in a project, falling back to their
+// global role. It returns an empty role if the user has neither.
+func (r *Repository) GetUserRole(ctx context.Context, userID, projectID string) (models.Role, error) {
+	query := `SELECT role FROM user_roles WHERE user_id = ? AND project_id IN (?, '')
+		ORDER BY CASE WHEN project_id = '' THEN 1 ELSE 0 END LIMIT 1`
+
+	var role string
+	err := r.DB.QueryRowContext(ctx, r.rebind(query), userID, projectID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query user role: %w", err)
+	}
+	return models.Role(role), nil
+}
+
+// SetUserRole assigns a role, replacing the user's previous role in the same scope
+func (r *Repository) SetUserRole(ctx context.Context, a models.RoleAssignment) error {
+	query := `INSERT INTO user_roles (user_id, project_id, role) VALUES (?, ?, ?)
+		ON CONFLICT (user_id, project_id) DO UPDATE SET role = excluded.role`
+
+	if _, err := r.DB.ExecContext(ctx, r.rebind(query), a.UserID, a.ProjectID, string(a.Role)); err != nil {
+		return r.writeError("failed to set user role", err)
+	}
+	return nil
+}
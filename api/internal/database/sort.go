@@ -0,0 +1,308 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// sortExpr returns the SQL expression ordering issues by a sort key.
+// Queries must alias issues as i and the joined assignee as u.
+func sortExpr(k models.SortKey) string {
+	if k.Custom != nil {
+		return fieldSortExpr(*k.Custom)
+	}
+	switch k.Field {
+	case models.SortPriority:
+		return rankExpr("i.priority", models.ValidPriorities)
+	case models.SortStatus:
+		return rankExpr("i.status", models.ValidStatuses)
+	case models.SortAssignee:
+		return "COALESCE(u.name, '')"
+	case models.SortTitle:
+		return "i.title"
+	case models.SortCreatedAt:
+		return "i.created_at"
+	case models.SortUpdatedAt:
+		return "i.updated_at"
+	default:
+		return "i.order_index"
+	}
+}
+
+// rankExpr maps an enum column to its position in values so that it sorts
+// semantically (e.g. Low < Medium < High < Critical) rather than alphabetically
+func rankExpr(column string, values []string) string {
+	var b strings.Builder
+	b.WriteString("CASE ")
+	b.WriteString(column)
+	for i, v := range values {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", v, i)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(values))
+	return b.String()
+}
+
+// missingNumber is the sort value of an issue without a value for a number
+// field, placing it before every other issue
+const missingNumber = -1e308
+
+// fieldSortExpr returns the SQL expression ordering issues by a custom
+// field. Issues without a value come first: text and dates sort as the
+// empty string, numbers as missingNumber and options as rank -1.
+func fieldSortExpr(f models.CustomField) string {
+	value := func(column string) string {
+		return fmt.Sprintf("(SELECT v.%s FROM issue_field_values v WHERE v.issue_id = i.id AND v.field_id = %s)", column, quote(f.ID))
+	}
+	switch f.Type {
+	case models.FieldNumber:
+		return fmt.Sprintf("COALESCE(%s, %s)", value("number"), models.FormatNumber(missingNumber))
+	case models.FieldSelect:
+		var b strings.Builder
+		b.WriteString("CASE ")
+		b.WriteString(value("value"))
+		for i, o := range f.Options {
+			fmt.Fprintf(&b, " WHEN %s THEN %d", quote(o), i)
+		}
+		b.WriteString(" ELSE -1 END")
+		return b.String()
+	default:
+		return fmt.Sprintf("COALESCE(%s, '')", value("value"))
+	}
+}
+
+// quote returns s as a SQL string literal
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// orderByClause returns the ORDER BY clause for keys with the ID tie-breaker
+func orderByClause(keys []models.SortKey) string {
+	parts := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		dir := "ASC"
+		if k.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, sortExpr(k)+" "+dir)
+	}
+	parts = append(parts, "i.id ASC")
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// sortValue returns the value sortExpr compares for an issue: int64 ranks
+// for enums, float64 for order_index, strings and times otherwise
+func sortValue(issue models.Issue, k models.SortKey) interface{} {
+	if k.Custom != nil {
+		return fieldSortValue(issue, *k.Custom)
+	}
+	switch k.Field {
+	case models.SortPriority:
+		return rank(issue.Priority, models.ValidPriorities)
+	case models.SortStatus:
+		return rank(issue.Status, models.ValidStatuses)
+	case models.SortAssignee:
+		if issue.Assignee != nil {
+			return issue.Assignee.Name
+		}
+		return ""
+	case models.SortTitle:
+		return issue.Title
+	case models.SortCreatedAt:
+		return issue.CreatedAt
+	case models.SortUpdatedAt:
+		return issue.UpdatedAt
+	default:
+		return issue.OrderIndex
+	}
+}
+
+// fieldSortValue returns the value fieldSortExpr compares for an issue
+func fieldSortValue(issue models.Issue, f models.CustomField) interface{} {
+	value, ok := issue.CustomFields[f.Key]
+	switch f.Type {
+	case models.FieldNumber:
+		if n, ok := value.(float64); ok {
+			return n
+		}
+		return float64(missingNumber)
+	case models.FieldSelect:
+		if !ok {
+			return int64(-1)
+		}
+		s, _ := value.(string)
+		for i, o := range f.Options {
+			if o == s {
+				return int64(i)
+			}
+		}
+		return int64(-1)
+	default:
+		s, _ := value.(string)
+		return s
+	}
+}
+
+func rank(value string, values []string) int64 {
+	for i, v := range values {
+		if v == value {
+			return int64(i)
+		}
+	}
+	return int64(len(values))
+}
+
+// issueCursor returns the keyset position of an issue in the list ordering
+func issueCursor(issue models.Issue, keys []models.SortKey) models.Cursor {
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		values[i] = sortValue(issue, k)
+	}
+	return models.Cursor{Values: values, Sort: models.FormatSort(keys), ID: issue.ID}
+}
+
+// cursorValues checks that c was issued for keys and converts its JSON-decoded
+// values back to the types returned by sortValue
+func cursorValues(c *models.Cursor, keys []models.SortKey) ([]interface{}, error) {
+	sort := c.Sort
+	if sort == "" {
+		sort = models.FormatSort(models.DefaultSort)
+	}
+	if sort != models.FormatSort(keys) || len(c.Values) != len(keys) {
+		return nil, models.ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		switch cursorType(k) {
+		case "rank":
+			f, ok := c.Values[i].(float64)
+			if !ok || f != math.Trunc(f) {
+				return nil, models.ErrInvalidCursor
+			}
+			values[i] = int64(f)
+		case "number":
+			f, ok := c.Values[i].(float64)
+			if !ok {
+				return nil, models.ErrInvalidCursor
+			}
+			values[i] = f
+		case "time":
+			s, ok := c.Values[i].(string)
+			if !ok {
+				return nil, models.ErrInvalidCursor
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, models.ErrInvalidCursor
+			}
+			values[i] = t
+		default:
+			s, ok := c.Values[i].(string)
+			if !ok {
+				return nil, models.ErrInvalidCursor
+			}
+			values[i] = s
+		}
+	}
+	return values, nil
+}
+
+// cursorType returns the kind of value sortValue returns for a key: "rank",
+// "number", "time" or "string"
+func cursorType(k models.SortKey) string {
+	if k.Custom != nil {
+		switch k.Custom.Type {
+		case models.FieldNumber:
+			return "number"
+		case models.FieldSelect:
+			return "rank"
+		}
+		return "string"
+	}
+	switch k.Field {
+	case models.SortPriority, models.SortStatus:
+		return "rank"
+	case models.SortOrderIndex:
+		return "number"
+	case models.SortCreatedAt, models.SortUpdatedAt:
+		return "time"
+	}
+	return "string"
+}
+
+// keysetClause builds the " AND (...)" condition selecting the issues that
+// come after the position (values, id) in the ordering given by keys
+func keysetClause(keys []models.SortKey, values []interface{}, id string) (string, []interface{}) {
+	var terms []string
+	var args []interface{}
+
+	for i := 0; i <= len(keys); i++ {
+		var conds []string
+		var condArgs []interface{}
+		for j := 0; j < i; j++ {
+			conds = append(conds, sortExpr(keys[j])+" = ?")
+			condArgs = append(condArgs, values[j])
+		}
+		if i < len(keys) {
+			op := ">"
+			if keys[i].Desc {
+				op = "<"
+			}
+			conds = append(conds, sortExpr(keys[i])+" "+op+" ?")
+			condArgs = append(condArgs, values[i])
+		} else {
+			conds = append(conds, "i.id > ?")
+			condArgs = append(condArgs, id)
+		}
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+		args = append(args, condArgs...)
+	}
+
+	return " AND (" + strings.Join(terms, " OR ") + ")", args
+}
+
+// comparePositions compares two list positions under keys, returning a
+// negative number when a comes first
+func comparePositions(keys []models.SortKey, a []interface{}, aID string, b []interface{}, bID string) int {
+	for i, k := range keys {
+		c := compareSortValues(a[i], b[i])
+		if k.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(aID, bID)
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case int64:
+		bv := b.(int64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case float64:
+		bv := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	default:
+		return strings.Compare(a.(string), b.(string))
+	}
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// groupExpr returns the SQL expression of a stats dimension. Label grouping
+// relies on the gil/gl joins added by CountIssues.
+func groupExpr(dim string) (string, error) {
+	switch dim {
+	case models.GroupByStatus:
+		return "i.status", nil
+	case models.GroupByPriority:
+		return "i.priority", nil
+	case models.GroupByAssignee:
+		return "i.assignee_id", nil
+	case models.GroupByLabel:
+		return "gl.name", nil
+	default:
+		return "", fmt.Errorf("invalid group by %q", dim)
+	}
+}
+
+// CountIssues counts the issues matching f grouped by the given dimensions.
+// Without dimensions it returns a single row with the total.
+func (r *Repository) CountIssues(ctx context.Context, f models.IssueFilter, groupBy []string) ([]models.GroupCount, error) {
+	exprs := make([]string, len(groupBy))
+	joinLabels := false
+	for i, dim := range groupBy {
+		expr, err := groupExpr(dim)
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = expr
+		joinLabels = joinLabels || dim == models.GroupByLabel
+	}
+
+	columns := append(append([]string{}, exprs...), "COUNT(DISTINCT i.id)")
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM issues i"
+	if joinLabels {
+		query += " LEFT JOIN issue_labels gil ON gil.issue_id = i.id LEFT JOIN labels gl ON gl.id = gil.label_id"
+	}
+	where, args := issueFilterClause(f)
+	query += " WHERE 1=1" + where
+	if len(exprs) > 0 {
+		query += " GROUP BY " + strings.Join(exprs, ", ")
+	}
+
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []models.GroupCount
+	for rows.Next() {
+		keys := make([]sql.NullString, len(groupBy))
+		dest := make([]interface{}, 0, len(groupBy)+1)
+		for i := range keys {
+			dest = append(dest, &keys[i])
+		}
+		var c models.GroupCount
+		dest = append(dest, &c.Count)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan issue count: %w", err)
+		}
+		c.Keys = make([]*string, len(keys))
+		for i, k := range keys {
+			if k.Valid {
+				v := k.String
+				c.Keys[i] = &v
+			}
+		}
+		counts = append(counts, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating issue counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetStatusHistory returns the status changes of the issues matching f,
+// ordered by issue and time
+func (r *Repository) GetStatusHistory(ctx context.Context, f models.IssueFilter) ([]models.StatusChange, error) {
+	where, args := issueFilterClause(f)
+	query := `
+		SELECT h.issue_id, h.from_status, h.to_status, h.changed_at
+		FROM issue_status_history h
+		JOIN issues i ON i.id = h.issue_id
+		WHERE 1=1` + where + `
+		ORDER BY h.issue_id, h.changed_at, h.id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.StatusChange
+	for rows.Next() {
+		var c models.StatusChange
+		var from sql.NullString
+		if err := rows.Scan(&c.IssueID, &from, &c.To, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		if from.Valid {
+			c.From = &from.String
+		}
+		history = append(history, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating status history: %w", err)
+	}
+
+	return history, nil
+}
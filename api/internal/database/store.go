@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// Supported storage drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
+// Store is the persistence contract the HTTP handlers depend on.
+// Repository implements it on top of database/sql for SQLite and PostgreSQL,
+// MemoryStore keeps everything in process memory.
+type Store interface {
+	Ping(ctx context.Context) error
+
+	ListIssues(ctx context.Context, q models.IssueQuery) (*models.IssueList, error)
+	GetIssue(ctx context.Context, id string) (*models.Issue, error)
+	CreateIssue(ctx context.Context, issue models.Issue) error
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}) error
+	UpdateIssueWithLabels(ctx context.Context, id string, updates map[string]interface{}, labelIDs []string) error
+	UpdateIssueLabels(ctx context.Context, issueID string, labelIDs []string) error
+	DeleteIssue(ctx context.Context, id string) error
+	RestoreIssue(ctx context.Context, id string) error
+	PurgeIssue(ctx context.Context, id string) error
+	PurgeTrash(ctx context.Context, cutoff time.Time) (int64, error)
+	ArchiveIssue(ctx context.Context, id string) error
+	UnarchiveIssue(ctx context.Context, id string) error
+	ArchiveStale(ctx context.Context, statuses []string, before time.Time) (int64, error)
+
+	// SetWIPLimits makes writes fail with a *WIPError when they would take a
+	// column over a strict limit
+	SetWIPLimits(limits models.WIPLimits)
+
+	ListComments(ctx context.Context, issueID string) ([]models.Comment, error)
+	CreateComment(ctx context.Context, c models.Comment) error
+
+	CountIssues(ctx context.Context, f models.IssueFilter, groupBy []string) ([]models.GroupCount, error)
+	GetStatusHistory(ctx context.Context, f models.IssueFilter) ([]models.StatusChange, error)
+
+	GetUsers(ctx context.Context) ([]models.User, error)
+	GetUser(ctx context.Context, id string) (*models.User, error)
+	GetUsersByID(ctx context.Context, ids []string) ([]models.User, error)
+	CreateUser(ctx context.Context, user models.User) error
+	GetUserRole(ctx context.Context, userID, projectID string) (models.Role, error)
+	SetUserRole(ctx context.Context, a models.RoleAssignment) error
+
+	CreateAccount(ctx context.Context, a models.Account) error
+	GetAccountByEmail(ctx context.Context, email string) (*models.Account, error)
+	GetIdentity(ctx context.Context, issuer, subject string) (*models.Identity, error)
+	CreateIdentity(ctx context.Context, id models.Identity) error
+	CreateSession(ctx context.Context, s models.Session) error
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+	DeleteSession(ctx context.Context, id string) error
+
+	GetLabels(ctx context.Context) ([]models.Label, error)
+	CreateLabel(ctx context.Context, label models.Label) error
+
+	ListTemplates(ctx context.Context) ([]models.IssueTemplate, error)
+	GetTemplate(ctx context.Context, id string) (*models.IssueTemplate, error)
+	CreateTemplate(ctx context.Context, t models.IssueTemplate) error
+	UpdateTemplate(ctx context.Context, t models.IssueTemplate) error
+	DeleteTemplate(ctx context.Context, id string) error
+
+	ListFields(ctx context.Context) ([]models.CustomField, error)
+	GetField(ctx context.Context, id string) (*models.CustomField, error)
+	CreateField(ctx context.Context, f models.CustomField) error
+	UpdateField(ctx context.Context, f models.CustomField) error
+	DeleteField(ctx context.Context, id string) error
+
+	ListIssueTypes(ctx context.Context) ([]models.IssueType, error)
+	GetIssueType(ctx context.Context, key string) (*models.IssueType, error)
+	UpdateIssueType(ctx context.Context, t models.IssueType) error
+
+	GetBoardVersion(ctx context.Context) (models.BoardVersion, error)
+	GetChangeSeq(ctx context.Context) (int64, error)
+	GetEntityChangeSeq(ctx context.Context, entity, id string) (int64, error)
+	GetChanges(ctx context.Context, since int64, limit int) ([]models.Change, error)
+}
+
+var _ Store = (*Repository)(nil)
+
+// NewStore creates the Store for the given driver. db is ignored for the
+// memory driver.
+func NewStore(driver string, db *sql.DB) (Store, error) {
+	if driver == DriverMemory {
+		return NewMemoryStore(), nil
+	}
+	d, err := dialectFor(driver)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{DB: db, dialect: d}, nil
+}
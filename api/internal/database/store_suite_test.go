@@ -0,0 +1,1525 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// storeFactory returns a fresh, empty Store with the schema applied
+type storeFactory func(t *testing.T) Store
+
+// runStoreSuite runs the behavior every Store implementation must share
+func runStoreSuite(t *testing.T, newStore storeFactory) {
+	ctx := context.Background()
+
+	// seed creates two users, two labels and four issues spread across statuses
+	seed := func(t *testing.T, s Store) {
+		t.Helper()
+		for _, u := range []models.User{
+			{ID: "user1", Name: "Alice", AvatarURL: "https://example.com/alice.jpg"},
+			{ID: "user2", Name: "Bob"},
+		} {
+			if err := s.CreateUser(ctx, u); err != nil {
+				t.Fatalf("Failed to create user: %v", err)
+			}
+		}
+		for _, l := range []models.Label{
+			{ID: "label1", Name: "Bug", Color: "#FF0000"},
+			{ID: "label2", Name: "Feature", Color: "#00FF00"},
+		} {
+			if err := s.CreateLabel(ctx, l); err != nil {
+				t.Fatalf("Failed to create label: %v", err)
+			}
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		user1 := "user1"
+		issues := []models.Issue{
+			{ID: "issue-1", Title: "Issue 1", Description: "First", Status: "Todo", Priority: "High", AssigneeID: &user1, OrderIndex: 3},
+			{ID: "issue-2", Title: "Issue 2", Description: "Second", Status: "In Progress", Priority: "Low", OrderIndex: 1},
+			{ID: "issue-3", Title: "Issue 3", Description: "Third", Status: "Done", Priority: "High", OrderIndex: 2},
+			{ID: "issue-4", Title: "Issue 4", Description: "Fourth", Status: "Todo", Priority: "Medium", OrderIndex: 4},
+		}
+		for _, issue := range issues {
+			issue.CreatedAt = now
+			issue.UpdatedAt = now
+			if err := s.CreateIssue(ctx, issue); err != nil {
+				t.Fatalf("Failed to create issue: %v", err)
+			}
+		}
+		if err := s.UpdateIssueLabels(ctx, "issue-1", []string{"label1", "label2"}); err != nil {
+			t.Fatalf("Failed to set labels: %v", err)
+		}
+		if err := s.UpdateIssueLabels(ctx, "issue-3", []string{"label2"}); err != nil {
+			t.Fatalf("Failed to set labels: %v", err)
+		}
+	}
+
+	ids := func(issues []models.Issue) []string {
+		out := make([]string, len(issues))
+		for i, issue := range issues {
+			out[i] = issue.ID
+		}
+		return out
+	}
+
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("Ping", func(t *testing.T) {
+		s := newStore(t)
+		if err := s.Ping(ctx); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+	})
+
+	t.Run("Create And Get Issue", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		issue, err := s.GetIssue(ctx, "issue-1")
+		if err != nil {
+			t.Fatalf("Failed to get issue: %v", err)
+		}
+		if issue == nil {
+			t.Fatal("Expected issue, got nil")
+		}
+		if issue.Title != "Issue 1" || issue.Description != "First" || issue.Status != "Todo" || issue.Priority != "High" {
+			t.Errorf("Unexpected issue fields: %+v", issue)
+		}
+		if issue.OrderIndex != 3 {
+			t.Errorf("Expected order_index 3, got %f", issue.OrderIndex)
+		}
+		if issue.AssigneeID == nil || *issue.AssigneeID != "user1" {
+			t.Errorf("Expected assignee_id user1, got %v", issue.AssigneeID)
+		}
+		if issue.Assignee == nil || issue.Assignee.Name != "Alice" || issue.Assignee.AvatarURL != "https://example.com/alice.jpg" {
+			t.Errorf("Expected assignee Alice to be populated, got %+v", issue.Assignee)
+		}
+		if got := labelNames(issue.Labels); !equal(got, []string{"Bug", "Feature"}) {
+			t.Errorf("Expected labels [Bug Feature], got %v", got)
+		}
+		if issue.CreatedAt.IsZero() || issue.UpdatedAt.IsZero() {
+			t.Error("Expected timestamps to be set")
+		}
+	})
+
+	t.Run("Get Missing Issue", func(t *testing.T) {
+		s := newStore(t)
+
+		issue, err := s.GetIssue(ctx, "missing")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if issue != nil {
+			t.Errorf("Expected nil for missing issue, got %+v", issue)
+		}
+	})
+
+	t.Run("List Issues", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		tests := []struct {
+			name       string
+			status     []string
+			assigneeID string
+			priority   []string
+			labels     []string
+			ids        []string
+			page       int
+			pageSize   int
+			want       []string
+		}{
+			{name: "All ordered by order_index", page: 1, want: []string{"issue-2", "issue-3", "issue-1", "issue-4"}},
+			{name: "Status", status: []string{"Todo"}, page: 1, want: []string{"issue-1", "issue-4"}},
+			{name: "Multiple statuses", status: []string{"Todo", "Done"}, page: 1, want: []string{"issue-3", "issue-1", "issue-4"}},
+			{name: "Assignee", assigneeID: "user1", page: 1, want: []string{"issue-1"}},
+			{name: "Priority", priority: []string{"High"}, page: 1, want: []string{"issue-3", "issue-1"}},
+			{name: "Label name", labels: []string{"Feature"}, page: 1, want: []string{"issue-3", "issue-1"}},
+			{name: "Any of labels", labels: []string{"Bug", "Feature"}, page: 1, want: []string{"issue-3", "issue-1"}},
+			{name: "IDs", ids: []string{"issue-4", "issue-2", "missing"}, page: 1, want: []string{"issue-2", "issue-4"}},
+			{name: "Combined", status: []string{"Todo"}, priority: []string{"High"}, labels: []string{"Bug"}, page: 1, want: []string{"issue-1"}},
+			{name: "No results", status: []string{"Canceled"}, page: 1, want: []string{}},
+			{name: "First page", page: 1, pageSize: 3, want: []string{"issue-2", "issue-3", "issue-1"}},
+			{name: "Second page", page: 2, pageSize: 3, want: []string{"issue-4"}},
+			{name: "Past last page", page: 3, pageSize: 3, want: []string{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				list, err := s.ListIssues(ctx, models.IssueQuery{
+					IssueFilter: models.IssueFilter{Status: tt.status, AssigneeID: tt.assigneeID, Priority: tt.priority, Labels: tt.labels, IDs: tt.ids},
+					Page:        tt.page,
+					PageSize:    tt.pageSize,
+				})
+				if err != nil {
+					t.Fatalf("Failed to list issues: %v", err)
+				}
+				if got := ids(list.Items); !equal(got, tt.want) {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+			})
+		}
+
+		t.Run("Populates assignee and labels", func(t *testing.T) {
+			list, err := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Status: []string{"Todo"}}, Page: 1})
+			if err != nil {
+				t.Fatalf("Failed to list issues: %v", err)
+			}
+			issues := list.Items
+			if len(issues) != 2 {
+				t.Fatalf("Expected 2 issues, got %d", len(issues))
+			}
+			if issues[0].Assignee == nil || issues[0].Assignee.ID != "user1" {
+				t.Errorf("Expected assignee user1, got %+v", issues[0].Assignee)
+			}
+			if got := labelNames(issues[0].Labels); !equal(got, []string{"Bug", "Feature"}) {
+				t.Errorf("Expected labels [Bug Feature], got %v", got)
+			}
+			if issues[1].Assignee != nil {
+				t.Errorf("Expected no assignee, got %+v", issues[1].Assignee)
+			}
+			if issues[1].Labels == nil || len(issues[1].Labels) != 0 {
+				t.Errorf("Expected empty labels, got %v", issues[1].Labels)
+			}
+		})
+
+		t.Run("Total count with offset pages", func(t *testing.T) {
+			list, err := s.ListIssues(ctx, models.IssueQuery{Page: 2, PageSize: 3})
+			if err != nil {
+				t.Fatalf("Failed to list issues: %v", err)
+			}
+			if list.TotalCount != 4 {
+				t.Errorf("Expected total_count 4, got %d", list.TotalCount)
+			}
+			if list.NextCursor != nil {
+				t.Errorf("Expected no next_cursor in offset mode, got %s", *list.NextCursor)
+			}
+		})
+	})
+
+	t.Run("List Issues With Cursor", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		// Walk all pages two at a time
+		var got []string
+		q := models.IssueQuery{Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > 3 {
+				t.Fatal("Cursor pagination did not terminate")
+			}
+			list, err := s.ListIssues(ctx, q)
+			if err != nil {
+				t.Fatalf("Failed to list issues: %v", err)
+			}
+			if list.TotalCount != 4 {
+				t.Errorf("Expected total_count 4, got %d", list.TotalCount)
+			}
+			got = append(got, ids(list.Items)...)
+			if list.NextCursor == nil {
+				break
+			}
+			c, err := models.DecodeCursor(*list.NextCursor)
+			if err != nil {
+				t.Fatalf("Failed to decode cursor: %v", err)
+			}
+			q.Cursor = c
+		}
+		if want := []string{"issue-2", "issue-3", "issue-1", "issue-4"}; !equal(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+
+		// Filters apply to total_count and pages
+		list, err := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Status: []string{"Todo"}}, Limit: 1})
+		if err != nil {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		if got := ids(list.Items); !equal(got, []string{"issue-1"}) || list.TotalCount != 2 || list.NextCursor == nil {
+			t.Errorf("Unexpected filtered page: items=%v total=%d next=%v", got, list.TotalCount, list.NextCursor)
+		}
+
+		// The last page has no next cursor
+		list, err = s.ListIssues(ctx, models.IssueQuery{Limit: 4})
+		if err != nil {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		if len(list.Items) != 4 || list.NextCursor != nil {
+			t.Errorf("Expected a single complete page, got %v next=%v", ids(list.Items), list.NextCursor)
+		}
+	})
+
+	t.Run("Cursor Is Stable Under Concurrent Changes", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		first, err := s.ListIssues(ctx, models.IssueQuery{Limit: 2})
+		if err != nil || first.NextCursor == nil {
+			t.Fatalf("Failed to list first page: %v", err)
+		}
+
+		// Moving an already-seen issue to the end and adding a new one at the
+		// front must neither duplicate nor skip the remaining issues
+		if err := s.UpdateIssue(ctx, "issue-2", map[string]interface{}{"order_index": 10.0}); err != nil {
+			t.Fatalf("Failed to move issue: %v", err)
+		}
+		now := time.Now().UTC()
+		if err := s.CreateIssue(ctx, models.Issue{ID: "issue-0", Title: "New", Status: "Todo", Priority: "Low", OrderIndex: 0, CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+
+		c, err := models.DecodeCursor(*first.NextCursor)
+		if err != nil {
+			t.Fatalf("Failed to decode cursor: %v", err)
+		}
+		second, err := s.ListIssues(ctx, models.IssueQuery{Cursor: c, Limit: 2})
+		if err != nil {
+			t.Fatalf("Failed to list second page: %v", err)
+		}
+		if got := ids(second.Items); !equal(got, []string{"issue-1", "issue-4"}) {
+			t.Errorf("Expected [issue-1 issue-4], got %v", got)
+		}
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		tests := []struct {
+			sort string
+			want []string
+		}{
+			{sort: "", want: []string{"issue-2", "issue-3", "issue-1", "issue-4"}},
+			{sort: "-priority", want: []string{"issue-1", "issue-3", "issue-4", "issue-2"}},
+			{sort: "priority", want: []string{"issue-2", "issue-4", "issue-1", "issue-3"}},
+			{sort: "-priority,order_index", want: []string{"issue-3", "issue-1", "issue-4", "issue-2"}},
+			{sort: "status", want: []string{"issue-1", "issue-4", "issue-2", "issue-3"}},
+			{sort: "assignee", want: []string{"issue-2", "issue-3", "issue-4", "issue-1"}},
+			{sort: "-assignee,-order_index", want: []string{"issue-1", "issue-4", "issue-3", "issue-2"}},
+			{sort: "-title", want: []string{"issue-4", "issue-3", "issue-2", "issue-1"}},
+			{sort: "created_at", want: []string{"issue-1", "issue-2", "issue-3", "issue-4"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.sort, func(t *testing.T) {
+				keys, err := models.ParseSort(tt.sort)
+				if err != nil {
+					t.Fatalf("Failed to parse sort: %v", err)
+				}
+
+				list, err := s.ListIssues(ctx, models.IssueQuery{Sort: keys, Page: 1})
+				if err != nil {
+					t.Fatalf("Failed to list issues: %v", err)
+				}
+				if got := ids(list.Items); !equal(got, tt.want) {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+
+				// Walking the same sort one issue at a time yields the same order
+				var walked []string
+				q := models.IssueQuery{Sort: keys, Limit: 1}
+				for pages := 0; ; pages++ {
+					if pages > 4 {
+						t.Fatal("Cursor pagination did not terminate")
+					}
+					page, err := s.ListIssues(ctx, q)
+					if err != nil {
+						t.Fatalf("Failed to list page: %v", err)
+					}
+					walked = append(walked, ids(page.Items)...)
+					if page.NextCursor == nil {
+						break
+					}
+					if q.Cursor, err = models.DecodeCursor(*page.NextCursor); err != nil {
+						t.Fatalf("Failed to decode cursor: %v", err)
+					}
+				}
+				if !equal(walked, tt.want) {
+					t.Errorf("Expected cursor walk %v, got %v", tt.want, walked)
+				}
+			})
+		}
+
+		t.Run("Cursor from another sort", func(t *testing.T) {
+			page, err := s.ListIssues(ctx, models.IssueQuery{Limit: 1})
+			if err != nil || page.NextCursor == nil {
+				t.Fatalf("Failed to list issues: %v", err)
+			}
+			c, _ := models.DecodeCursor(*page.NextCursor)
+			_, err = s.ListIssues(ctx, models.IssueQuery{Sort: []models.SortKey{{Field: models.SortPriority}}, Cursor: c, Limit: 1})
+			if err != models.ErrInvalidCursor {
+				t.Errorf("Expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	})
+
+	t.Run("Fields And Expand", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		list, err := s.ListIssues(ctx, models.IssueQuery{
+			IssueFilter: models.IssueFilter{AssigneeID: "user1"},
+			Fields:      []string{"title"},
+			Expand:      &models.IssueExpand{},
+			Page:        1,
+		})
+		if err != nil || len(list.Items) != 1 {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		issue := list.Items[0]
+		if issue.Title != "Issue 1" || issue.Description != "" {
+			t.Errorf("Expected title only, got title '%s' description '%s'", issue.Title, issue.Description)
+		}
+		if issue.Assignee != nil || issue.Labels != nil {
+			t.Errorf("Expected no expansions, got assignee %+v labels %v", issue.Assignee, issue.Labels)
+		}
+		if issue.AssigneeID == nil || *issue.AssigneeID != "user1" {
+			t.Errorf("Expected assignee_id to still be loaded, got %v", issue.AssigneeID)
+		}
+
+		list, err = s.ListIssues(ctx, models.IssueQuery{Expand: &models.IssueExpand{Labels: true}, Page: 1})
+		if err != nil {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		for _, issue := range list.Items {
+			if issue.Assignee != nil {
+				t.Errorf("Expected no assignee on %s, got %+v", issue.ID, issue.Assignee)
+			}
+			if issue.Labels == nil {
+				t.Errorf("Expected labels on %s", issue.ID)
+			}
+		}
+
+		// Sorting by assignee works without expanding it
+		list, err = s.ListIssues(ctx, models.IssueQuery{Sort: []models.SortKey{{Field: models.SortAssignee, Desc: true}}, Expand: &models.IssueExpand{}, Limit: 1})
+		if err != nil {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		if got := ids(list.Items); !equal(got, []string{"issue-1"}) || list.Items[0].Assignee != nil {
+			t.Errorf("Expected issue-1 without assignee, got %v", list.Items)
+		}
+	})
+
+	t.Run("Count Issues", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		// key renders a group key list, with "-" for nil keys
+		key := func(keys []*string) string {
+			out := ""
+			for i, k := range keys {
+				if i > 0 {
+					out += "/"
+				}
+				if k == nil {
+					out += "-"
+				} else {
+					out += *k
+				}
+			}
+			return out
+		}
+		counts := func(t *testing.T, f models.IssueFilter, groupBy ...string) map[string]int {
+			t.Helper()
+			rows, err := s.CountIssues(ctx, f, groupBy)
+			if err != nil {
+				t.Fatalf("Failed to count issues: %v", err)
+			}
+			out := make(map[string]int)
+			for _, r := range rows {
+				out[key(r.Keys)] = r.Count
+			}
+			return out
+		}
+		expect := func(t *testing.T, got, want map[string]int) {
+			t.Helper()
+			if len(got) != len(want) {
+				t.Errorf("Expected %v, got %v", want, got)
+				return
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("Expected %v, got %v", want, got)
+					return
+				}
+			}
+		}
+
+		expect(t, counts(t, models.IssueFilter{}), map[string]int{"": 4})
+		expect(t, counts(t, models.IssueFilter{Status: []string{"Canceled"}}), map[string]int{"": 0})
+		expect(t, counts(t, models.IssueFilter{}, models.GroupByStatus), map[string]int{"Todo": 2, "In Progress": 1, "Done": 1})
+		expect(t, counts(t, models.IssueFilter{}, models.GroupByPriority), map[string]int{"High": 2, "Medium": 1, "Low": 1})
+		expect(t, counts(t, models.IssueFilter{}, models.GroupByAssignee), map[string]int{"user1": 1, "-": 3})
+		expect(t, counts(t, models.IssueFilter{}, models.GroupByLabel), map[string]int{"Bug": 1, "Feature": 2, "-": 2})
+		expect(t, counts(t, models.IssueFilter{Priority: []string{"High"}}, models.GroupByStatus, models.GroupByLabel),
+			map[string]int{"Todo/Bug": 1, "Todo/Feature": 1, "Done/Feature": 1})
+
+		if _, err := s.CountIssues(ctx, models.IssueFilter{}, []string{"title"}); err == nil {
+			t.Error("Expected error grouping by an unknown dimension")
+		}
+	})
+
+	t.Run("Status History", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		movedAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"status": "In Progress", "updated_at": movedAt}); err != nil {
+			t.Fatalf("Failed to update issue: %v", err)
+		}
+		// Updates that keep the status are not transitions
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"status": "In Progress", "title": "Same"}); err != nil {
+			t.Fatalf("Failed to update issue: %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"status": "Done", "updated_at": movedAt.Add(time.Hour)}); err != nil {
+			t.Fatalf("Failed to update issue: %v", err)
+		}
+
+		history, err := s.GetStatusHistory(ctx, models.IssueFilter{AssigneeID: "user1"})
+		if err != nil {
+			t.Fatalf("Failed to get status history: %v", err)
+		}
+		if len(history) != 3 {
+			t.Fatalf("Expected 3 status changes, got %+v", history)
+		}
+		if history[0].From != nil || history[0].To != "Todo" {
+			t.Errorf("Expected creation entry to Todo, got %+v", history[0])
+		}
+		if history[1].From == nil || *history[1].From != "Todo" || history[1].To != "In Progress" || !history[1].ChangedAt.Equal(movedAt) {
+			t.Errorf("Expected Todo -> In Progress at %v, got %+v", movedAt, history[1])
+		}
+		if history[2].From == nil || *history[2].From != "In Progress" || history[2].To != "Done" {
+			t.Errorf("Expected In Progress -> Done, got %+v", history[2])
+		}
+
+		all, err := s.GetStatusHistory(ctx, models.IssueFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get status history: %v", err)
+		}
+		if len(all) != 6 {
+			t.Errorf("Expected 6 status changes, got %d", len(all))
+		}
+
+		if err := s.DeleteIssue(ctx, "issue-1"); err != nil {
+			t.Fatalf("Failed to delete issue: %v", err)
+		}
+		history, _ = s.GetStatusHistory(ctx, models.IssueFilter{})
+		for _, c := range history {
+			if c.IssueID == "issue-1" {
+				t.Errorf("Expected history of deleted issue to be gone, got %+v", c)
+			}
+		}
+	})
+
+	t.Run("Rejects Malformed Cursor", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		_, err := s.ListIssues(ctx, models.IssueQuery{Cursor: &models.Cursor{Values: []interface{}{"x"}, ID: "issue-1"}, Limit: 2})
+		if err != models.ErrInvalidCursor {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("Update Issue", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		updatedAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		err := s.UpdateIssue(ctx, "issue-2", map[string]interface{}{
+			"title":       "Renamed",
+			"status":      "Done",
+			"priority":    "Critical",
+			"assignee_id": "user2",
+			"order_index": 9.5,
+			"updated_at":  updatedAt,
+		})
+		if err != nil {
+			t.Fatalf("Failed to update issue: %v", err)
+		}
+
+		issue, err := s.GetIssue(ctx, "issue-2")
+		if err != nil || issue == nil {
+			t.Fatalf("Failed to get issue: %v", err)
+		}
+		if issue.Title != "Renamed" || issue.Status != "Done" || issue.Priority != "Critical" || issue.OrderIndex != 9.5 {
+			t.Errorf("Unexpected issue after update: %+v", issue)
+		}
+		if issue.Assignee == nil || issue.Assignee.Name != "Bob" {
+			t.Errorf("Expected assignee Bob, got %+v", issue.Assignee)
+		}
+		if !issue.UpdatedAt.Equal(updatedAt) {
+			t.Errorf("Expected updated_at %v, got %v", updatedAt, issue.UpdatedAt)
+		}
+
+		if err := s.UpdateIssue(ctx, "missing", map[string]interface{}{"title": "x"}); err == nil {
+			t.Error("Expected error when updating a missing issue")
+		}
+	})
+
+	t.Run("Replace Labels", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		if err := s.UpdateIssueLabels(ctx, "issue-1", []string{"label2"}); err != nil {
+			t.Fatalf("Failed to replace labels: %v", err)
+		}
+		issue, _ := s.GetIssue(ctx, "issue-1")
+		if got := labelNames(issue.Labels); !equal(got, []string{"Feature"}) {
+			t.Errorf("Expected labels [Feature], got %v", got)
+		}
+
+		if err := s.UpdateIssueLabels(ctx, "issue-1", []string{}); err != nil {
+			t.Fatalf("Failed to clear labels: %v", err)
+		}
+		issue, _ = s.GetIssue(ctx, "issue-1")
+		if len(issue.Labels) != 0 {
+			t.Errorf("Expected no labels, got %v", issue.Labels)
+		}
+	})
+
+	t.Run("Create With Labels", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		err := s.CreateIssue(ctx, models.Issue{
+			ID: "issue-5", Title: "Issue 5", Status: "Todo", Priority: "Low", CreatedAt: now, UpdatedAt: now,
+			Labels: []models.Label{{ID: "label1"}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		issue, _ := s.GetIssue(ctx, "issue-5")
+		if got := labelNames(issue.Labels); !equal(got, []string{"Bug"}) {
+			t.Errorf("Expected labels [Bug], got %v", got)
+		}
+	})
+
+	t.Run("Unknown References", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		ghost := "ghost"
+		err := s.CreateIssue(ctx, models.Issue{
+			ID: "issue-5", Title: "Issue 5", Status: "Todo", Priority: "Low", AssigneeID: &ghost, CreatedAt: now, UpdatedAt: now,
+			Labels: []models.Label{{ID: "label1"}, {ID: "nope"}},
+		})
+		refErr, ok := err.(*ReferenceError)
+		if !ok {
+			t.Fatalf("Expected *ReferenceError, got %v", err)
+		}
+		if refErr.AssigneeID != "ghost" || !equal(refErr.LabelIDs, []string{"nope"}) {
+			t.Errorf("Expected ghost and nope to be reported, got %+v", refErr)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-5"); issue != nil {
+			t.Error("Expected no issue to be created")
+		}
+
+		err = s.UpdateIssueWithLabels(ctx, "issue-2", map[string]interface{}{"title": "Changed"}, []string{"nope"})
+		if _, ok := err.(*ReferenceError); !ok {
+			t.Fatalf("Expected *ReferenceError, got %v", err)
+		}
+		issue, _ := s.GetIssue(ctx, "issue-2")
+		if issue.Title != "Issue 2" {
+			t.Errorf("Expected the update to be rolled back, got title %q", issue.Title)
+		}
+
+		if err := s.UpdateIssue(ctx, "issue-2", map[string]interface{}{"assignee_id": "ghost"}); err == nil {
+			t.Error("Expected an unknown assignee to be rejected")
+		}
+	})
+
+	t.Run("Update With Labels", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		err := s.UpdateIssueWithLabels(ctx, "issue-2", map[string]interface{}{"status": "Done", "assignee_id": "user2"}, []string{"label1"})
+		if err != nil {
+			t.Fatalf("Failed to update issue: %v", err)
+		}
+		issue, _ := s.GetIssue(ctx, "issue-2")
+		if issue.Status != "Done" || issue.AssigneeID == nil || *issue.AssigneeID != "user2" {
+			t.Errorf("Expected Done and user2, got %s %v", issue.Status, issue.AssigneeID)
+		}
+		if got := labelNames(issue.Labels); !equal(got, []string{"Bug"}) {
+			t.Errorf("Expected labels [Bug], got %v", got)
+		}
+
+		// Labels alone still require the issue to exist
+		if err := s.UpdateIssueWithLabels(ctx, "missing", nil, []string{"label1"}); err == nil {
+			t.Error("Expected error when labelling a missing issue")
+		}
+	})
+
+	t.Run("Delete Issue", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		if err := s.DeleteIssue(ctx, "issue-1"); err != nil {
+			t.Fatalf("Failed to delete issue: %v", err)
+		}
+		issue, err := s.GetIssue(ctx, "issue-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if issue != nil {
+			t.Error("Expected issue to be deleted")
+		}
+
+		// Deleted issues no longer match label filters
+		list, _ := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Labels: []string{"Bug"}}, Page: 1})
+		if len(list.Items) != 0 {
+			t.Errorf("Expected no issues labelled Bug, got %v", ids(list.Items))
+		}
+
+		if err := s.DeleteIssue(ctx, "issue-1"); err == nil {
+			t.Error("Expected error when deleting a missing issue")
+		}
+	})
+
+	t.Run("Rejects Invalid Status And Priority", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		if err := s.CreateIssue(ctx, models.Issue{ID: "bad", Title: "Bad", Status: "Nope", Priority: "Low"}); err == nil {
+			t.Error("Expected error creating an issue with an invalid status")
+		}
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"priority": "Urgent"}); err == nil {
+			t.Error("Expected error updating an issue to an invalid priority")
+		}
+		issue, _ := s.GetIssue(ctx, "issue-1")
+		if issue.Priority != "High" {
+			t.Errorf("Expected priority to stay High, got '%s'", issue.Priority)
+		}
+	})
+
+	t.Run("Typed Errors", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		ghost := "ghost"
+		for _, tt := range []struct {
+			name string
+			err  error
+			want error
+		}{
+			{"update missing issue", s.UpdateIssue(ctx, "missing", map[string]interface{}{"title": "X"}), ErrNotFound},
+			{"delete missing issue", s.DeleteIssue(ctx, "missing"), ErrNotFound},
+			{"label missing issue", s.UpdateIssueLabels(ctx, "missing", []string{"label1"}), ErrNotFound},
+			{"duplicate issue", s.CreateIssue(ctx, models.Issue{ID: "issue-1", Title: "Dup", Status: "Todo", Priority: "Low", CreatedAt: now, UpdatedAt: now}), ErrConflict},
+			{"duplicate user", s.CreateUser(ctx, models.User{ID: "user1", Name: "Alice"}), ErrConflict},
+			{"duplicate label", s.CreateLabel(ctx, models.Label{ID: "label1", Name: "Bug", Color: "#FF0000"}), ErrConflict},
+			{"invalid status", s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"status": "Nope"}), ErrConstraint},
+			{"unknown assignee", s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"assignee_id": &ghost}), ErrConstraint},
+			{"role of unknown user", s.SetUserRole(ctx, models.RoleAssignment{UserID: "ghost", Role: models.RoleMember}), ErrConstraint},
+		} {
+			if !errors.Is(tt.err, tt.want) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.err)
+			}
+		}
+
+		if err := s.CreateAccount(ctx, models.Account{UserID: "user1", Email: "alice@example.com", PasswordHash: "hash"}); err != nil {
+			t.Fatalf("Failed to create account: %v", err)
+		}
+		err := s.CreateAccount(ctx, models.Account{UserID: "user2", Email: "alice@example.com", PasswordHash: "hash"})
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("duplicate email: expected %v, got %v", ErrConflict, err)
+		}
+	})
+
+	t.Run("Board Version", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		start, err := s.GetBoardVersion(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get board version: %v", err)
+		}
+		if start.Version == 0 || start.UpdatedAt.IsZero() {
+			t.Fatalf("Expected seeding to advance the version, got %+v", start)
+		}
+
+		version := start.Version
+		for _, write := range []struct {
+			name string
+			run  func() error
+		}{
+			{"update", func() error { return s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"title": "Renamed"}) }},
+			{"labels", func() error { return s.UpdateIssueLabels(ctx, "issue-2", []string{"label1"}) }},
+			{"delete", func() error { return s.DeleteIssue(ctx, "issue-4") }},
+			{"label", func() error { return s.CreateLabel(ctx, models.Label{ID: "label3", Name: "Docs", Color: "#0000FF"}) }},
+		} {
+			if err := write.run(); err != nil {
+				t.Fatalf("%s failed: %v", write.name, err)
+			}
+			v, _ := s.GetBoardVersion(ctx)
+			if v.Version != version+1 {
+				t.Errorf("%s: expected version %d, got %d", write.name, version+1, v.Version)
+			}
+			version = v.Version
+		}
+
+		// Failed writes leave it alone
+		s.UpdateIssue(ctx, "missing", map[string]interface{}{"title": "X"})
+		s.CreateUser(ctx, models.User{ID: "user1", Name: "Alice"})
+		if v, _ := s.GetBoardVersion(ctx); v.Version != version {
+			t.Errorf("Expected failed writes to keep version %d, got %d", version, v.Version)
+		}
+	})
+
+	t.Run("Change Log", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		token, err := s.GetChangeSeq(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get change sequence: %v", err)
+		}
+		if token == 0 {
+			t.Fatal("Expected seeding to record changes")
+		}
+
+		s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"title": "Renamed"})
+		s.UpdateIssueLabels(ctx, "issue-2", []string{"label1"})
+		s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"title": "Renamed again"})
+		s.DeleteIssue(ctx, "issue-4")
+		s.CreateUser(ctx, models.User{ID: "user1", Name: "Alice"}) // duplicate, not recorded
+
+		changes, err := s.GetChanges(ctx, token, 10)
+		if err != nil {
+			t.Fatalf("Failed to get changes: %v", err)
+		}
+		var got []string
+		for _, c := range changes {
+			got = append(got, fmt.Sprintf("%s/%s/%v", c.Entity, c.EntityID, c.Deleted))
+		}
+		want := []string{"issue/issue-2/false", "issue/issue-1/false", "issue/issue-4/true"}
+		if !equal(got, want) {
+			t.Errorf("Expected latest changes %v, got %v", want, got)
+		}
+		if len(changes) == 3 && (changes[0].Seq <= token || changes[2].Seq != token+4 || changes[2].ChangedAt.IsZero()) {
+			t.Errorf("Unexpected sequences %+v", changes)
+		}
+
+		if limited, _ := s.GetChanges(ctx, token, 2); len(limited) != 2 {
+			t.Errorf("Expected the limit to apply, got %d changes", len(limited))
+		}
+		if seq, _ := s.GetEntityChangeSeq(ctx, models.EntityIssue, "issue-1"); seq != token+3 {
+			t.Errorf("Expected issue-1 last changed at %d, got %d", token+3, seq)
+		}
+		if seq, _ := s.GetEntityChangeSeq(ctx, models.EntityIssue, "missing"); seq != 0 {
+			t.Errorf("Expected 0 for an unchanged entity, got %d", seq)
+		}
+		if seq, _ := s.GetChangeSeq(ctx); seq != token+4 {
+			t.Errorf("Expected sequence %d, got %d", token+4, seq)
+		}
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		if err := s.DeleteIssue(ctx, "issue-1"); err != nil {
+			t.Fatalf("Failed to delete issue: %v", err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-1"); issue != nil {
+			t.Error("Expected a trashed issue to be hidden")
+		}
+		live, _ := s.ListIssues(ctx, models.IssueQuery{})
+		for _, i := range live.Items {
+			if i.ID == "issue-1" {
+				t.Error("Expected the list to exclude the trashed issue")
+			}
+		}
+		if counts, _ := s.CountIssues(ctx, models.IssueFilter{}, nil); len(counts) != 1 || counts[0].Count != 3 {
+			t.Errorf("Expected counts to exclude the trashed issue, got %+v", counts)
+		}
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"title": "X"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound updating a trashed issue, got %v", err)
+		}
+		if err := s.UpdateIssueLabels(ctx, "issue-1", []string{"label2"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound relabelling a trashed issue, got %v", err)
+		}
+		if err := s.DeleteIssue(ctx, "issue-1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+		}
+
+		trash, err := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Trashed: true}})
+		if err != nil {
+			t.Fatalf("Failed to list trash: %v", err)
+		}
+		if len(trash.Items) != 1 || trash.Items[0].ID != "issue-1" || trash.Items[0].DeletedAt == nil || len(trash.Items[0].Labels) == 0 {
+			t.Fatalf("Expected issue-1 in the trash with its labels, got %+v", trash.Items)
+		}
+
+		if err := s.RestoreIssue(ctx, "issue-1"); err != nil {
+			t.Fatalf("Failed to restore issue: %v", err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-1"); issue == nil || issue.DeletedAt != nil || len(issue.Labels) == 0 {
+			t.Errorf("Expected issue-1 back with its labels, got %+v", issue)
+		}
+		if err := s.RestoreIssue(ctx, "issue-2"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound restoring a live issue, got %v", err)
+		}
+
+		// Purging respects the cutoff
+		s.DeleteIssue(ctx, "issue-2")
+		if n, err := s.PurgeTrash(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+			t.Errorf("Expected nothing purged before the cutoff, got %d (%v)", n, err)
+		}
+		if n, err := s.PurgeTrash(ctx, time.Now().Add(time.Hour)); err != nil || n != 1 {
+			t.Errorf("Expected 1 issue purged, got %d (%v)", n, err)
+		}
+		if trash, _ := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Trashed: true}}); len(trash.Items) != 0 {
+			t.Errorf("Expected an empty trash, got %d issues", len(trash.Items))
+		}
+		if err := s.RestoreIssue(ctx, "issue-2"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected a purged issue to be gone, got %v", err)
+		}
+
+		// Live issues can be purged immediately
+		if err := s.PurgeIssue(ctx, "issue-3"); err != nil {
+			t.Fatalf("Failed to purge issue: %v", err)
+		}
+		if history, _ := s.GetStatusHistory(ctx, models.IssueFilter{}); containsIssue(history, "issue-3") {
+			t.Error("Expected the purged issue's history to go with it")
+		}
+		if err := s.PurgeIssue(ctx, "issue-3"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound purging twice, got %v", err)
+		}
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		// Only issues closed before the cutoff are archived
+		if n, err := s.ArchiveStale(ctx, models.ClosedStatuses, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+			t.Errorf("Expected nothing archived before the cutoff, got %d (%v)", n, err)
+		}
+		if n, err := s.ArchiveStale(ctx, models.ClosedStatuses, time.Now().Add(time.Hour)); err != nil || n != 1 {
+			t.Fatalf("Expected 1 issue archived, got %d (%v)", n, err)
+		}
+		if n, _ := s.ArchiveStale(ctx, models.ClosedStatuses, time.Now().Add(time.Hour)); n != 0 {
+			t.Errorf("Expected archived issues to be skipped, got %d", n)
+		}
+
+		live, _ := s.ListIssues(ctx, models.IssueQuery{})
+		if contains(ids(live.Items), "issue-3") {
+			t.Error("Expected the list to exclude the archived issue")
+		}
+		all, _ := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Status: []string{"Done"}, IncludeArchived: true}})
+		if len(all.Items) != 1 || all.Items[0].ID != "issue-3" || all.Items[0].ArchivedAt == nil {
+			t.Errorf("Expected the archived issue with include_archived, got %+v", all.Items)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-3"); issue == nil || issue.ArchivedAt == nil || len(issue.Labels) != 1 {
+			t.Errorf("Expected the archived issue to stay readable, got %+v", issue)
+		}
+
+		if err := s.UnarchiveIssue(ctx, "issue-3"); err != nil {
+			t.Fatalf("Failed to unarchive issue: %v", err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-3"); issue == nil || issue.ArchivedAt != nil {
+			t.Errorf("Expected issue-3 back on the board, got %+v", issue)
+		}
+		// An unarchived issue closed within the period stays on the board
+		if n, _ := s.ArchiveStale(ctx, models.ClosedStatuses, time.Now().Add(-time.Minute)); n != 0 {
+			t.Errorf("Expected an unarchived issue not to be archived again, got %d", n)
+		}
+
+		// Any issue can be archived by hand, and repeating it changes nothing
+		seq, _ := s.GetChangeSeq(ctx)
+		for i := 0; i < 2; i++ {
+			if err := s.ArchiveIssue(ctx, "issue-1"); err != nil {
+				t.Fatalf("Failed to archive issue: %v", err)
+			}
+		}
+		if after, _ := s.GetChangeSeq(ctx); after != seq+1 {
+			t.Errorf("Expected one change for archiving, got %d", after-seq)
+		}
+		if err := s.UnarchiveIssue(ctx, "issue-2"); err != nil {
+			t.Errorf("Expected unarchiving a live issue to succeed, got %v", err)
+		}
+		s.DeleteIssue(ctx, "issue-4")
+		for _, id := range []string{"missing", "issue-4"} {
+			if err := s.ArchiveIssue(ctx, id); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Expected ErrNotFound archiving %s, got %v", id, err)
+			}
+		}
+
+		// The clock starts when the issue is closed, so later edits do not restart it
+		closed := time.Now().Add(-2 * time.Hour)
+		if err := s.UpdateIssue(ctx, "issue-2", map[string]interface{}{"status": "Done", "updated_at": closed}); err != nil {
+			t.Fatalf("Failed to close issue: %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-2", map[string]interface{}{"title": "Edited", "updated_at": time.Now()}); err != nil {
+			t.Fatalf("Failed to edit issue: %v", err)
+		}
+		if n, err := s.ArchiveStale(ctx, models.ClosedStatuses, time.Now().Add(-time.Hour)); err != nil || n != 1 {
+			t.Fatalf("Expected the issue closed 2 hours ago archived, got %d (%v)", n, err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-2"); issue == nil || issue.ArchivedAt == nil {
+			t.Errorf("Expected issue-2 archived, got %+v", issue)
+		}
+	})
+
+	t.Run("Templates", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		bug := models.IssueTemplate{ID: "tmpl-bug", Name: "Bug report", TitlePrefix: "[Bug] ", Description: "## Steps", Priority: "High", Type: "bug", LabelIDs: []string{"label2", "label1"}, CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateTemplate(ctx, bug); err != nil {
+			t.Fatalf("Failed to create template: %v", err)
+		}
+		if err := s.CreateTemplate(ctx, models.IssueTemplate{ID: "tmpl-task", Name: "Chore", CreatedAt: now, UpdatedAt: now}); err != nil {
+			t.Fatalf("Failed to create template: %v", err)
+		}
+
+		got, err := s.GetTemplate(ctx, "tmpl-bug")
+		if err != nil || got == nil {
+			t.Fatalf("Failed to get template: %v", err)
+		}
+		if got.Name != "Bug report" || got.TitlePrefix != "[Bug] " || got.Priority != "High" || got.Type != "bug" || !equal(got.LabelIDs, []string{"label1", "label2"}) {
+			t.Errorf("Expected the stored template, got %+v", got)
+		}
+		if missing, err := s.GetTemplate(ctx, "missing"); err != nil || missing != nil {
+			t.Errorf("Expected nil for a missing template, got %+v (%v)", missing, err)
+		}
+
+		templates, err := s.ListTemplates(ctx)
+		if err != nil || len(templates) != 2 || templates[0].Name != "Bug report" || templates[1].Name != "Chore" {
+			t.Fatalf("Expected 2 templates ordered by name, got %+v (%v)", templates, err)
+		}
+		if len(templates[1].LabelIDs) != 0 {
+			t.Errorf("Expected no labels on Chore, got %v", templates[1].LabelIDs)
+		}
+
+		dup := models.IssueTemplate{ID: "tmpl-dup", Name: "Bug report", CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateTemplate(ctx, dup); !errors.Is(err, ErrConflict) {
+			t.Errorf("Expected ErrConflict for a duplicate name, got %v", err)
+		}
+		var refErr *ReferenceError
+		unknown := models.IssueTemplate{ID: "tmpl-x", Name: "X", LabelIDs: []string{"nope"}, CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateTemplate(ctx, unknown); !errors.As(err, &refErr) || !equal(refErr.LabelIDs, []string{"nope"}) {
+			t.Errorf("Expected a reference error for an unknown label, got %v", err)
+		}
+		story := models.IssueTemplate{ID: "tmpl-story", Name: "Story", Type: "story", CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateTemplate(ctx, story); !errors.Is(err, ErrConstraint) {
+			t.Errorf("Expected ErrConstraint for an invalid type, got %v", err)
+		}
+
+		bug.Name = "Bug"
+		bug.LabelIDs = []string{"label1"}
+		bug.UpdatedAt = now.Add(time.Minute)
+		if err := s.UpdateTemplate(ctx, bug); err != nil {
+			t.Fatalf("Failed to update template: %v", err)
+		}
+		if got, _ := s.GetTemplate(ctx, "tmpl-bug"); got == nil || got.Name != "Bug" || !equal(got.LabelIDs, []string{"label1"}) || !got.CreatedAt.Equal(now) {
+			t.Errorf("Expected the updated template, got %+v", got)
+		}
+		bug.Name = "Chore"
+		if err := s.UpdateTemplate(ctx, bug); !errors.Is(err, ErrConflict) {
+			t.Errorf("Expected ErrConflict renaming onto another template, got %v", err)
+		}
+		if err := s.UpdateTemplate(ctx, models.IssueTemplate{ID: "missing", Name: "Y"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound updating a missing template, got %v", err)
+		}
+
+		if err := s.DeleteTemplate(ctx, "tmpl-bug"); err != nil {
+			t.Fatalf("Failed to delete template: %v", err)
+		}
+		if err := s.DeleteTemplate(ctx, "tmpl-bug"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+		}
+	})
+
+	t.Run("Custom Fields", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		severity := models.CustomField{ID: "cf-severity", Key: "severity", Name: "Severity", Type: models.FieldSelect, Options: []string{"Low", "High", "Critical"}, CreatedAt: now, UpdatedAt: now}
+		estimate := models.CustomField{ID: "cf-estimate", Key: "estimate", Name: "Estimate", Type: models.FieldNumber, CreatedAt: now, UpdatedAt: now}
+		tags := models.CustomField{ID: "cf-tags", Key: "tags", Name: "Tags", Type: models.FieldMultiSelect, Options: []string{"a", "b", "c"}, CreatedAt: now, UpdatedAt: now}
+		for _, f := range []models.CustomField{severity, estimate, tags} {
+			if err := s.CreateField(ctx, f); err != nil {
+				t.Fatalf("Failed to create custom field: %v", err)
+			}
+		}
+		dup := models.CustomField{ID: "cf-dup", Key: "severity", Name: "Again", Type: models.FieldText, CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateField(ctx, dup); !errors.Is(err, ErrConflict) {
+			t.Errorf("Expected ErrConflict for a duplicate key, got %v", err)
+		}
+
+		fields, err := s.ListFields(ctx)
+		if err != nil || len(fields) != 3 || fields[0].Key != "estimate" || fields[2].Key != "tags" {
+			t.Fatalf("Expected 3 fields ordered by key, got %+v (%v)", fields, err)
+		}
+		if got, err := s.GetField(ctx, "cf-severity"); err != nil || got == nil || !equal(got.Options, severity.Options) {
+			t.Errorf("Expected the stored field, got %+v (%v)", got, err)
+		}
+		if missing, err := s.GetField(ctx, "missing"); err != nil || missing != nil {
+			t.Errorf("Expected nil for a missing field, got %+v (%v)", missing, err)
+		}
+
+		set := func(id string, values models.FieldValues) {
+			t.Helper()
+			if err := s.UpdateIssue(ctx, id, map[string]interface{}{"custom_fields": values}); err != nil {
+				t.Fatalf("Failed to set custom fields of %s: %v", id, err)
+			}
+		}
+		set("issue-1", models.FieldValues{"severity": "High", "estimate": 5.0, "tags": []string{"a", "b"}})
+		set("issue-2", models.FieldValues{"severity": "Critical", "estimate": 2.5})
+		set("issue-3", models.FieldValues{"severity": "Low"})
+
+		issue, err := s.GetIssue(ctx, "issue-1")
+		if err != nil || issue == nil {
+			t.Fatalf("Failed to get issue: %v", err)
+		}
+		if issue.CustomFields["severity"] != "High" || issue.CustomFields["estimate"] != 5.0 {
+			t.Errorf("Expected the stored values, got %+v", issue.CustomFields)
+		}
+		if got, _ := issue.CustomFields["tags"].([]string); !equal(got, []string{"a", "b"}) {
+			t.Errorf("Expected tags [a b], got %v", issue.CustomFields["tags"])
+		}
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"custom_fields": models.FieldValues{"nope": "x"}}); !errors.Is(err, ErrConstraint) {
+			t.Errorf("Expected ErrConstraint for an unknown field, got %v", err)
+		}
+
+		list, err := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{
+			CustomFields: []models.CustomFieldFilter{{Field: severity, Values: []string{"High", "Critical"}}},
+		}})
+		if err != nil || !equal(ids(list.Items), []string{"issue-2", "issue-1"}) {
+			t.Errorf("Expected issues with High or Critical severity, got %v (%v)", ids(list.Items), err)
+		}
+		list, _ = s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{
+			CustomFields: []models.CustomFieldFilter{{Field: tags, Values: []string{"b"}}, {Field: estimate, Values: []string{"5"}}},
+		}})
+		if !equal(ids(list.Items), []string{"issue-1"}) {
+			t.Errorf("Expected issue-1 tagged b with estimate 5, got %v", ids(list.Items))
+		}
+
+		list, _ = s.ListIssues(ctx, models.IssueQuery{Sort: []models.SortKey{{Field: "cf.severity", Desc: true, Custom: &severity}}})
+		if !equal(ids(list.Items), []string{"issue-2", "issue-1", "issue-3", "issue-4"}) {
+			t.Errorf("Expected issues by option order with the unset last, got %v", ids(list.Items))
+		}
+
+		// Unset numbers come first; page through them by cursor
+		keys := []models.SortKey{{Field: "cf.estimate", Custom: &estimate}}
+		page, err := s.ListIssues(ctx, models.IssueQuery{Sort: keys, Limit: 2})
+		if err != nil || !equal(ids(page.Items), []string{"issue-3", "issue-4"}) || page.NextCursor == nil {
+			t.Fatalf("Expected the issues without an estimate first, got %v (%v)", ids(page.Items), err)
+		}
+		cursor, err := models.DecodeCursor(*page.NextCursor)
+		if err != nil {
+			t.Fatalf("Failed to decode cursor: %v", err)
+		}
+		page, err = s.ListIssues(ctx, models.IssueQuery{Sort: keys, Cursor: cursor, Limit: 2})
+		if err != nil || !equal(ids(page.Items), []string{"issue-2", "issue-1"}) || page.NextCursor != nil {
+			t.Errorf("Expected the estimated issues next, got %v (%v)", ids(page.Items), err)
+		}
+
+		set("issue-1", models.FieldValues{"severity": nil, "tags": []string{"c"}})
+		issue, _ = s.GetIssue(ctx, "issue-1")
+		if _, ok := issue.CustomFields["severity"]; ok || issue.CustomFields["estimate"] != 5.0 {
+			t.Errorf("Expected severity cleared and the estimate kept, got %+v", issue.CustomFields)
+		}
+		if got, _ := issue.CustomFields["tags"].([]string); !equal(got, []string{"c"}) {
+			t.Errorf("Expected tags replaced with [c], got %v", issue.CustomFields["tags"])
+		}
+
+		severity.Name = "Impact"
+		severity.Options = append(severity.Options, "Blocker")
+		if err := s.UpdateField(ctx, severity); err != nil {
+			t.Fatalf("Failed to update custom field: %v", err)
+		}
+		if got, _ := s.GetField(ctx, "cf-severity"); got == nil || got.Name != "Impact" || len(got.Options) != 4 || got.Key != "severity" {
+			t.Errorf("Expected the updated field, got %+v", got)
+		}
+		if err := s.UpdateField(ctx, models.CustomField{ID: "missing", Name: "X"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound updating a missing field, got %v", err)
+		}
+
+		before, _ := s.GetChangeSeq(ctx)
+		if err := s.DeleteField(ctx, "cf-tags"); err != nil {
+			t.Fatalf("Failed to delete custom field: %v", err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-1"); issue == nil || issue.CustomFields["tags"] != nil {
+			t.Errorf("Expected the field's values deleted with it, got %+v", issue)
+		}
+		if seq, _ := s.GetEntityChangeSeq(ctx, models.EntityIssue, "issue-1"); seq <= before {
+			t.Errorf("Expected issue-1 recorded as changed, got seq %d after %d", seq, before)
+		}
+		if err := s.DeleteField(ctx, "cf-tags"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+		}
+	})
+
+	t.Run("Issue Types", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		types, err := s.ListIssueTypes(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list issue types: %v", err)
+		}
+		var keys []string
+		for _, it := range types {
+			keys = append(keys, it.Key)
+		}
+		if !equal(keys, models.ValidTypes) {
+			t.Fatalf("Expected types %v in display order, got %v", models.ValidTypes, keys)
+		}
+		if bug := types[0]; bug.Color != "#ef4444" || !equal(bug.RequiredFields, []string{"description"}) || bug.Statuses == nil {
+			t.Errorf("Expected the seeded bug type, got %+v", bug)
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		epic := models.IssueType{Key: "epic", Name: "Initiative", Icon: "flag", Color: "#000000", RequiredFields: []string{"assignee_id"}, Statuses: []string{"Backlog", "In Progress", "Done"}, UpdatedAt: now}
+		if err := s.UpdateIssueType(ctx, epic); err != nil {
+			t.Fatalf("Failed to update issue type: %v", err)
+		}
+		got, err := s.GetIssueType(ctx, "epic")
+		if err != nil || got == nil || got.Name != "Initiative" || !equal(got.Statuses, epic.Statuses) || !equal(got.RequiredFields, epic.RequiredFields) {
+			t.Errorf("Expected the updated type, got %+v (%v)", got, err)
+		}
+		if missing, err := s.GetIssueType(ctx, "story"); err != nil || missing != nil {
+			t.Errorf("Expected nil for a missing type, got %+v (%v)", missing, err)
+		}
+		if err := s.UpdateIssueType(ctx, models.IssueType{Key: "story", Name: "Story", UpdatedAt: now}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for a missing type, got %v", err)
+		}
+
+		issue, err := s.GetIssue(ctx, "issue-1")
+		if err != nil || issue == nil || issue.Type != models.TypeTask {
+			t.Fatalf("Expected issues created without a type to be tasks, got %+v (%v)", issue, err)
+		}
+		bug := models.Issue{ID: "issue-5", Title: "Issue 5", Status: "Todo", Priority: "High", Type: models.TypeBug, CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateIssue(ctx, bug); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-3", map[string]interface{}{"type": models.TypeEpic}); err != nil {
+			t.Fatalf("Failed to change type: %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-4", map[string]interface{}{"type": "story"}); !errors.Is(err, ErrConstraint) {
+			t.Errorf("Expected ErrConstraint for an invalid type, got %v", err)
+		}
+
+		list, err := s.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{Types: []string{models.TypeBug, models.TypeEpic}}})
+		if err != nil {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		if got := ids(list.Items); !equal(got, []string{"issue-5", "issue-3"}) {
+			t.Errorf("Expected bugs and epics [issue-5 issue-3], got %v", got)
+		}
+		for _, issue := range list.Items {
+			if issue.Type == "" {
+				t.Errorf("Expected listed issues to carry their type, got %+v", issue)
+			}
+		}
+	})
+
+	t.Run("Comments And Sub-issues", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		parent := "issue-1"
+		child := models.Issue{ID: "issue-5", Title: "Child", Status: "Todo", Priority: "Low", ParentID: &parent, CreatedAt: now, UpdatedAt: now}
+		if err := s.CreateIssue(ctx, child); err != nil {
+			t.Fatalf("Failed to create sub-issue: %v", err)
+		}
+		missing := "missing"
+		orphan := models.Issue{ID: "issue-6", Title: "Orphan", Status: "Todo", Priority: "Low", ParentID: &missing, CreatedAt: now, UpdatedAt: now}
+		var refErr *ReferenceError
+		if err := s.CreateIssue(ctx, orphan); !errors.As(err, &refErr) || refErr.ParentID != "missing" {
+			t.Errorf("Expected a ReferenceError for an unknown parent, got %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"parent_id": "issue-5"}); !errors.Is(err, ErrConstraint) {
+			t.Errorf("Expected ErrConstraint for a cycle, got %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-1", map[string]interface{}{"parent_id": "issue-1"}); !errors.Is(err, ErrConstraint) {
+			t.Errorf("Expected ErrConstraint for an issue parenting itself, got %v", err)
+		}
+
+		for i, body := range []string{"First", "Second"} {
+			c := models.Comment{ID: fmt.Sprintf("comment-%d", i), IssueID: "issue-1", Body: body, CreatedAt: now.Add(time.Duration(i) * time.Second)}
+			if err := s.CreateComment(ctx, c); err != nil {
+				t.Fatalf("Failed to create comment: %v", err)
+			}
+		}
+		if err := s.CreateComment(ctx, models.Comment{ID: "comment-x", IssueID: "missing", Body: "Hi", CreatedAt: now}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound commenting on a missing issue, got %v", err)
+		}
+		comments, err := s.ListComments(ctx, "issue-1")
+		if err != nil {
+			t.Fatalf("Failed to list comments: %v", err)
+		}
+		if len(comments) != 2 || comments[0].Body != "First" || comments[1].Body != "Second" {
+			t.Errorf("Expected both comments oldest first, got %+v", comments)
+		}
+
+		list, err := s.ListIssues(ctx, models.IssueQuery{Expand: &models.IssueExpand{CommentsCount: true, Children: true}})
+		if err != nil {
+			t.Fatalf("Failed to list issues: %v", err)
+		}
+		for _, issue := range list.Items {
+			wantCount, wantChildren := 0, 0
+			if issue.ID == "issue-1" {
+				wantCount, wantChildren = 2, 1
+			}
+			if issue.CommentsCount == nil || *issue.CommentsCount != wantCount {
+				t.Errorf("Expected %d comments on %s, got %v", wantCount, issue.ID, issue.CommentsCount)
+			}
+			if issue.Children == nil || len(issue.Children) != wantChildren {
+				t.Errorf("Expected %d children of %s, got %+v", wantChildren, issue.ID, issue.Children)
+			}
+		}
+
+		// Purging the parent detaches its sub-issues and drops its comments
+		if err := s.PurgeIssue(ctx, "issue-1"); err != nil {
+			t.Fatalf("Failed to purge issue: %v", err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-5"); issue == nil || issue.ParentID != nil {
+			t.Errorf("Expected issue-5 without a parent, got %+v", issue)
+		}
+		if comments, _ := s.ListComments(ctx, "issue-1"); len(comments) != 0 {
+			t.Errorf("Expected the comments to go with the issue, got %+v", comments)
+		}
+	})
+
+	t.Run("Strict WIP Limits", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+		s.SetWIPLimits(models.WIPLimits{Status: map[string]int{"In Progress": 3}, PerAssignee: map[string]int{"Todo": 1}, Strict: true})
+
+		now := time.Now().UTC().Truncate(time.Second)
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				issue := models.Issue{ID: fmt.Sprintf("wip-%d", i), Title: "WIP", Status: "In Progress", Priority: "Low", CreatedAt: now, UpdatedAt: now}
+				errs[i] = s.CreateIssue(ctx, issue)
+			}(i)
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			var wipErr *WIPError
+			switch {
+			case err == nil:
+				created++
+			case !errors.As(err, &wipErr) || !errors.Is(err, ErrConflict):
+				t.Errorf("Expected a WIPError, got %v", err)
+			}
+		}
+		if created != 2 {
+			t.Errorf("Expected 2 issues to fill the column, got %d", created)
+		}
+
+		if err := s.UpdateIssue(ctx, "issue-4", map[string]interface{}{"status": "In Progress"}); !errors.Is(err, ErrConflict) {
+			t.Errorf("Expected ErrConflict moving into a full column, got %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-2", map[string]interface{}{"order_index": 0.5}); err != nil {
+			t.Errorf("Expected reordering within a full column to pass, got %v", err)
+		}
+		if err := s.UpdateIssue(ctx, "issue-4", map[string]interface{}{"assignee_id": "user1"}); !errors.Is(err, ErrConflict) {
+			t.Errorf("Expected ErrConflict over the per-assignee limit, got %v", err)
+		}
+		if issue, _ := s.GetIssue(ctx, "issue-4"); issue.Status != "Todo" || issue.AssigneeID != nil {
+			t.Errorf("Expected issue-4 unchanged, got %+v", issue)
+		}
+	})
+
+	t.Run("Users And Labels", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		users, err := s.GetUsers(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get users: %v", err)
+		}
+		var names []string
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+		sort.Strings(names)
+		if !equal(names, []string{"Alice", "Bob"}) {
+			t.Errorf("Expected users [Alice Bob], got %v", names)
+		}
+
+		users, err = s.GetUsersByID(ctx, []string{"user2", "missing"})
+		if err != nil {
+			t.Fatalf("Failed to get users by ID: %v", err)
+		}
+		if len(users) != 1 || users[0].Name != "Bob" {
+			t.Errorf("Expected only Bob, got %+v", users)
+		}
+
+		labels, err := s.GetLabels(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get labels: %v", err)
+		}
+		got := labelNames(labels)
+		sort.Strings(got)
+		if !equal(got, []string{"Bug", "Feature"}) {
+			t.Errorf("Expected labels [Bug Feature], got %v", got)
+		}
+	})
+
+	t.Run("Accounts, Identities And Sessions", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		user, err := s.GetUser(ctx, "user1")
+		if err != nil || user == nil || user.Name != "Alice" {
+			t.Fatalf("Expected Alice, got %+v (%v)", user, err)
+		}
+		if user, _ := s.GetUser(ctx, "missing"); user != nil {
+			t.Errorf("Expected nil for a missing user, got %+v", user)
+		}
+
+		if err := s.CreateAccount(ctx, models.Account{UserID: "user1", Email: "Alice@Example.com", PasswordHash: "hash"}); err != nil {
+			t.Fatalf("Failed to create account: %v", err)
+		}
+		if err := s.CreateAccount(ctx, models.Account{UserID: "user2", Email: "alice@example.com", PasswordHash: "hash"}); err == nil {
+			t.Error("Expected duplicate email to fail")
+		}
+		account, err := s.GetAccountByEmail(ctx, "ALICE@example.com")
+		if err != nil || account == nil || account.UserID != "user1" || account.Email != "alice@example.com" {
+			t.Errorf("Expected case-insensitive lookup of Alice's account, got %+v (%v)", account, err)
+		}
+
+		if err := s.CreateIdentity(ctx, models.Identity{Issuer: "https://idp", Subject: "sub-1", UserID: "user2"}); err != nil {
+			t.Fatalf("Failed to create identity: %v", err)
+		}
+		identity, err := s.GetIdentity(ctx, "https://idp", "sub-1")
+		if err != nil || identity == nil || identity.UserID != "user2" {
+			t.Errorf("Expected identity of user2, got %+v (%v)", identity, err)
+		}
+		if identity, _ := s.GetIdentity(ctx, "https://other", "sub-1"); identity != nil {
+			t.Errorf("Expected identities to be scoped by issuer, got %+v", identity)
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		session := models.Session{ID: "hash-1", UserID: "user1", CSRFToken: "csrf", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+		if err := s.CreateSession(ctx, session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		got, err := s.GetSession(ctx, "hash-1")
+		if err != nil || got == nil || got.UserID != "user1" || got.CSRFToken != "csrf" || !got.ExpiresAt.Equal(session.ExpiresAt) {
+			t.Errorf("Expected stored session, got %+v (%v)", got, err)
+		}
+		if err := s.DeleteSession(ctx, "hash-1"); err != nil {
+			t.Fatalf("Failed to delete session: %v", err)
+		}
+		if got, _ := s.GetSession(ctx, "hash-1"); got != nil {
+			t.Errorf("Expected session to be deleted, got %+v", got)
+		}
+	})
+
+	t.Run("User Roles", func(t *testing.T) {
+		s := newStore(t)
+		seed(t, s)
+
+		role, err := s.GetUserRole(ctx, "user1", "")
+		if err != nil || role != "" {
+			t.Fatalf("Expected no role, got %q (%v)", role, err)
+		}
+
+		for _, a := range []models.RoleAssignment{
+			{UserID: "user1", Role: models.RoleMember},
+			{UserID: "user1", ProjectID: "web", Role: models.RoleViewer},
+			{UserID: "user1", Role: models.RoleMaintainer}, // replaces the global role
+		} {
+			if err := s.SetUserRole(ctx, a); err != nil {
+				t.Fatalf("Failed to set role: %v", err)
+			}
+		}
+
+		for _, tt := range []struct {
+			user, project string
+			want          models.Role
+		}{
+			{"user1", "", models.RoleMaintainer},
+			{"user1", "web", models.RoleViewer},
+			{"user1", "api", models.RoleMaintainer},
+			{"user2", "web", ""},
+		} {
+			got, err := s.GetUserRole(ctx, tt.user, tt.project)
+			if err != nil {
+				t.Fatalf("Failed to get role: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetUserRole(%s, %q) = %q, want %q", tt.user, tt.project, got, tt.want)
+			}
+		}
+	})
+}
+
+func labelNames(labels []models.Label) []string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSQLiteStoreConformance(t *testing.T) {
+	runStoreSuite(t, func(t *testing.T) Store {
+		db, err := sql.Open("sqlite3", sqliteForeignKeys(filepath.Join(t.TempDir(), "test.db")))
+		if err != nil {
+			t.Fatalf("Failed to open sqlite: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		if err := runMigrations(db, filepath.Join("..", "..", "migrations", DriverSQLite)); err != nil {
+			t.Fatalf("Failed to run migrations: %v", err)
+		}
+		return NewRepository(db)
+	})
+}
+
+func TestMemoryStoreConformance(t *testing.T) {
+	runStoreSuite(t, func(t *testing.T) Store {
+		return NewMemoryStore()
+	})
+}
+
+// containsIssue reports whether history has a change of the issue
+func containsIssue(history []models.StatusChange, issueID string) bool {
+	for _, c := range history {
+		if c.IssueID == issueID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// ListTemplates returns all issue templates ordered by name
+func (r *Repository) ListTemplates(ctx context.Context) ([]models.IssueTemplate, error) {
+	query := "SELECT id, name, title_prefix, description, status, priority, type, created_at, updated_at FROM issue_templates ORDER BY name"
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]models.IssueTemplate, 0)
+	for rows.Next() {
+		var t models.IssueTemplate
+		if err := rows.Scan(&t.ID, &t.Name, &t.TitlePrefix, &t.Description, &t.Status, &t.Priority, &t.Type, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating templates: %w", err)
+	}
+
+	labels, err := r.templateLabels(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		templates[i].LabelIDs = labels[templates[i].ID]
+	}
+	return templates, nil
+}
+
+// GetTemplate returns an issue template by ID, or nil if it does not exist
+func (r *Repository) GetTemplate(ctx context.Context, id string) (*models.IssueTemplate, error) {
+	var t models.IssueTemplate
+	query := "SELECT id, name, title_prefix, description, status, priority, type, created_at, updated_at FROM issue_templates WHERE id = ?"
+	err := r.DB.QueryRowContext(ctx, r.rebind(query), id).Scan(&t.ID, &t.Name, &t.TitlePrefix, &t.Description, &t.Status, &t.Priority, &t.Type, &t.CreatedAt, &t.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	labels, err := r.templateLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t.LabelIDs = labels[id]
+	return &t, nil
+}
+
+// templateLabels returns the label IDs of one template, or of all of them
+// when id is empty, keyed by template ID
+func (r *Repository) templateLabels(ctx context.Context, id string) (map[string][]string, error) {
+	query := "SELECT template_id, label_id FROM issue_template_labels"
+	var args []interface{}
+	if id != "" {
+		query += " WHERE template_id = ?"
+		args = append(args, id)
+	}
+	query += " ORDER BY template_id, label_id"
+
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query template labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string][]string)
+	for rows.Next() {
+		var templateID, labelID string
+		if err := rows.Scan(&templateID, &labelID); err != nil {
+			return nil, fmt.Errorf("failed to scan template label: %w", err)
+		}
+		labels[templateID] = append(labels[templateID], labelID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating template labels: %w", err)
+	}
+	return labels, nil
+}
+
+// CreateTemplate stores a template with its labels. A name already in use
+// returns ErrConflict.
+func (r *Repository) CreateTemplate(ctx context.Context, t models.IssueTemplate) error {
+	query := `
+		INSERT INTO issue_templates (id, name, title_prefix, description, status, priority, type, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	return r.writeTemplate(ctx, t, query, t.ID, t.Name, t.TitlePrefix, t.Description, t.Status, t.Priority, t.Type, t.CreatedAt, t.UpdatedAt)
+}
+
+// UpdateTemplate replaces a template and its labels, keeping its creation
+// time. It returns ErrNotFound if the template does not exist.
+func (r *Repository) UpdateTemplate(ctx context.Context, t models.IssueTemplate) error {
+	query := `
+		UPDATE issue_templates SET name = ?, title_prefix = ?, description = ?, status = ?, priority = ?, type = ?, updated_at = ?
+		WHERE id = ?
+	`
+	return r.writeTemplate(ctx, t, query, t.Name, t.TitlePrefix, t.Description, t.Status, t.Priority, t.Type, t.UpdatedAt, t.ID)
+}
+
+// writeTemplate runs a single-row template write and replaces the
+// template's labels in one transaction
+func (r *Repository) writeTemplate(ctx context.Context, t models.IssueTemplate, query string, args ...interface{}) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.checkReferences(ctx, tx, nil, t.LabelIDs); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return r.writeError("failed to write template", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("template %s: %w", t.ID, ErrNotFound)
+	}
+
+	if _, err := tx.ExecContext(ctx, r.rebind("DELETE FROM issue_template_labels WHERE template_id = ?"), t.ID); err != nil {
+		return fmt.Errorf("failed to delete template labels: %w", err)
+	}
+	for _, labelID := range t.LabelIDs {
+		_, err := tx.ExecContext(ctx, r.rebind("INSERT INTO issue_template_labels (template_id, label_id) VALUES (?, ?)"), t.ID, labelID)
+		if err != nil {
+			if refErr := r.referenceError(ctx, err, nil, t.LabelIDs); refErr != nil {
+				return refErr
+			}
+			return r.writeError("failed to insert template label", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteTemplate deletes a template. Issues created from it are unaffected.
+func (r *Repository) DeleteTemplate(ctx context.Context, id string) error {
+	result, err := r.DB.ExecContext(ctx, r.rebind("DELETE FROM issue_templates WHERE id = ?"), id)
+	if err != nil {
+		return r.writeError("failed to delete template", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("template %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
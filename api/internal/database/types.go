@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// issueType returns the type of a new issue, which like the column default
+// is a task if unset
+func issueType(issue models.Issue) string {
+	if issue.Type == "" {
+		return models.DefaultType
+	}
+	return issue.Type
+}
+
+// ListIssueTypes returns the issue types in display order
+func (r *Repository) ListIssueTypes(ctx context.Context) ([]models.IssueType, error) {
+	query := "SELECT key, name, icon, color, required_fields, statuses, updated_at FROM issue_types ORDER BY " + rankExpr("key", models.ValidTypes)
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue types: %w", err)
+	}
+	defer rows.Close()
+
+	types := make([]models.IssueType, 0, len(models.ValidTypes))
+	for rows.Next() {
+		t, err := scanIssueType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating issue types: %w", err)
+	}
+	return types, nil
+}
+
+// GetIssueType returns an issue type by key, or nil if it does not exist
+func (r *Repository) GetIssueType(ctx context.Context, key string) (*models.IssueType, error) {
+	row := r.DB.QueryRowContext(ctx, r.rebind("SELECT key, name, icon, color, required_fields, statuses, updated_at FROM issue_types WHERE key = ?"), key)
+	t, err := scanIssueType(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// scanIssueType scans an issue_types row, decoding its rules
+func scanIssueType(row interface{ Scan(dest ...any) error }) (models.IssueType, error) {
+	var t models.IssueType
+	var required, statuses string
+	if err := row.Scan(&t.Key, &t.Name, &t.Icon, &t.Color, &required, &statuses, &t.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return t, err
+		}
+		return t, fmt.Errorf("failed to scan issue type: %w", err)
+	}
+	if err := json.Unmarshal([]byte(required), &t.RequiredFields); err != nil {
+		return t, fmt.Errorf("failed to decode required fields of issue type %s: %w", t.Key, err)
+	}
+	if err := json.Unmarshal([]byte(statuses), &t.Statuses); err != nil {
+		return t, fmt.Errorf("failed to decode statuses of issue type %s: %w", t.Key, err)
+	}
+	return t, nil
+}
+
+// UpdateIssueType replaces the display and rules of an issue type. It
+// returns ErrNotFound if the type does not exist.
+func (r *Repository) UpdateIssueType(ctx context.Context, t models.IssueType) error {
+	required, err := encodeOptions(t.RequiredFields)
+	if err != nil {
+		return err
+	}
+	statuses, err := encodeOptions(t.Statuses)
+	if err != nil {
+		return err
+	}
+	query := "UPDATE issue_types SET name = ?, icon = ?, color = ?, required_fields = ?, statuses = ?, updated_at = ? WHERE key = ?"
+	result, err := r.DB.ExecContext(ctx, r.rebind(query), t.Name, t.Icon, t.Color, required, statuses, t.UpdatedAt, t.Key)
+	if err != nil {
+		return r.writeError("failed to update issue type", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("issue type %s: %w", t.Key, ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// GetBoardVersion returns the board-wide change counter. It is zero until the
+// first write.
+func (r *Repository) GetBoardVersion(ctx context.Context) (models.BoardVersion, error) {
+	var v models.BoardVersion
+	err := r.DB.QueryRowContext(ctx, "SELECT version, updated_at FROM board_version WHERE id = 1").Scan(&v.Version, &v.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.BoardVersion{}, nil
+	}
+	if err != nil {
+		return v, fmt.Errorf("failed to get board version: %w", err)
+	}
+	return v, nil
+}
+
+// bumpVersion advances the board version within the transaction of a write
+func (r *Repository) bumpVersion(ctx context.Context, tx *sql.Tx) error {
+	query := "UPDATE board_version SET version = version + 1, updated_at = ? WHERE id = 1"
+	if _, err := tx.ExecContext(ctx, r.rebind(query), time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to bump board version: %w", err)
+	}
+	return nil
+}
+
+// recordChange appends a write to an entity to the change log and advances
+// the board version, within the transaction of the write. The version row is
+// updated first: its lock serialises writers until they commit, so change
+// sequences become visible in order and a reader holding a sync token never
+// skips a change committed later with a lower sequence.
+func (r *Repository) recordChange(ctx context.Context, tx *sql.Tx, entity, id string, deleted bool) error {
+	if err := r.bumpVersion(ctx, tx); err != nil {
+		return err
+	}
+	query := "INSERT INTO change_log (entity, entity_id, deleted, changed_at) VALUES (?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, r.rebind(query), entity, id, deleted, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to record change: %w", err)
+	}
+	return nil
+}
+
+// GetChangeSeq returns the sequence of the latest change, or zero before the
+// first write
+func (r *Repository) GetChangeSeq(ctx context.Context) (int64, error) {
+	var seq sql.NullInt64
+	if err := r.DB.QueryRowContext(ctx, "SELECT MAX(seq) FROM change_log").Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to get change sequence: %w", err)
+	}
+	return seq.Int64, nil
+}
+
+// GetEntityChangeSeq returns the sequence of the latest change to an entity,
+// or zero if it was never changed
+func (r *Repository) GetEntityChangeSeq(ctx context.Context, entity, id string) (int64, error) {
+	var seq sql.NullInt64
+	query := "SELECT MAX(seq) FROM change_log WHERE entity = ? AND entity_id = ?"
+	if err := r.DB.QueryRowContext(ctx, r.rebind(query), entity, id).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to get change sequence: %w", err)
+	}
+	return seq.Int64, nil
+}
+
+// GetChanges returns, in sequence order, up to limit entities changed after
+// since, each with its latest change only
+func (r *Repository) GetChanges(ctx context.Context, since int64, limit int) ([]models.Change, error) {
+	query := `
+		SELECT c.seq, c.entity, c.entity_id, c.deleted, c.changed_at
+		FROM change_log c
+		WHERE c.seq > ? AND c.seq = (
+			SELECT MAX(l.seq) FROM change_log l WHERE l.entity = c.entity AND l.entity_id = c.entity_id
+		)
+		ORDER BY c.seq
+		LIMIT ?
+	`
+	rows, err := r.DB.QueryContext(ctx, r.rebind(query), since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.Change
+	for rows.Next() {
+		var c models.Change
+		if err := rows.Scan(&c.Seq, &c.Entity, &c.EntityID, &c.Deleted, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating changes: %w", err)
+	}
+	return changes, nil
+}
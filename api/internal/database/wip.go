@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+// SetWIPLimits sets the WIP limits the repository enforces. Only strict
+// limits are enforced; lenient ones are left to the caller to warn about.
+func (r *Repository) SetWIPLimits(limits models.WIPLimits) {
+	r.wip = limits
+}
+
+// checkWIP returns a *WIPError if strict WIP limits reject moving an issue
+// into status with assigneeID. current is the issue before the change, nil
+// on create. It first locks the board version row, which every issue write
+// updates, so concurrent writers cannot both take the last slot of a column.
+func (r *Repository) checkWIP(ctx context.Context, tx *sql.Tx, current *models.Issue, status string, assigneeID *string) error {
+	if !r.wip.Strict || !r.wip.Enabled() {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE board_version SET version = version WHERE id = 1"); err != nil {
+		return fmt.Errorf("failed to lock board: %w", err)
+	}
+
+	violations, err := r.wip.Violations(current, status, assigneeID, func(status string, assigneeID *string) (int, error) {
+		f := models.IssueFilter{Status: []string{status}}
+		if assigneeID != nil {
+			f.AssigneeID = *assigneeID
+		}
+		where, args := issueFilterClause(f)
+		var n int
+		if err := tx.QueryRowContext(ctx, r.rebind("SELECT COUNT(*) FROM issues i WHERE 1=1"+where), args...).Scan(&n); err != nil {
+			return 0, fmt.Errorf("failed to count issues: %w", err)
+		}
+		return n, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &WIPError{Violations: violations}
+	}
+	return nil
+}
+
+// checkUpdateWIP runs checkWIP for an update that changes the status or
+// assignee of a live issue. Unknown issues pass so the update reports them.
+func (r *Repository) checkUpdateWIP(ctx context.Context, tx *sql.Tx, id string, updates map[string]interface{}) error {
+	status, statusChanged := updates["status"].(string)
+	assigneeID := updatedAssignee(updates)
+	if !r.wip.Strict || !r.wip.Enabled() || (!statusChanged && assigneeID == nil) {
+		return nil
+	}
+
+	var current models.Issue
+	var currentAssignee sql.NullString
+	query := "SELECT status, assignee_id FROM issues WHERE id = ? AND deleted_at IS NULL"
+	err := tx.QueryRowContext(ctx, r.rebind(query), id).Scan(&current.Status, &currentAssignee)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if currentAssignee.Valid {
+		current.AssigneeID = &currentAssignee.String
+	}
+
+	if !statusChanged {
+		status = current.Status
+	}
+	if assigneeID == nil {
+		assigneeID = current.AssigneeID
+	}
+	return r.checkWIP(ctx, tx, &current, status, assigneeID)
+}
+
+// SetWIPLimits sets the WIP limits the store enforces, like Repository
+func (m *MemoryStore) SetWIPLimits(limits models.WIPLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.wip = limits
+}
+
+// checkWIP returns a *WIPError if strict WIP limits reject moving an issue
+// into status with assigneeID. Callers must hold the lock.
+func (m *MemoryStore) checkWIP(current *models.Issue, status string, assigneeID *string) error {
+	if !m.wip.Strict || !m.wip.Enabled() {
+		return nil
+	}
+
+	violations, err := m.wip.Violations(current, status, assigneeID, func(status string, assigneeID *string) (int, error) {
+		f := models.IssueFilter{Status: []string{status}}
+		if assigneeID != nil {
+			f.AssigneeID = *assigneeID
+		}
+		return len(m.filterIssues(f)), nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &WIPError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	_ "github.com/abhir9/issue-board/api/internal/models" // response types named in the swag annotations
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// ArchiveIssue godoc
+// @Summary Archive an issue
+// @Description Take an issue off the board. It stays readable by ID and is listed with include_archived=true.
+// @Description Archiving an archived issue changes nothing.
+// @Tags issues
+// @Produce json
+// @Param id path string true "Issue ID"
+// @Success 200 {object} models.Issue
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issues/{id}/archive [post]
+// @Security ApiKeyAuth
+func (h *Handler) ArchiveIssue(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.Repo.ArchiveIssue(r.Context(), id); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to archive issue", "issue_id", id)
+		return
+	}
+	h.writeIssue(w, r, id, http.StatusOK)
+}
+
+// UnarchiveIssue godoc
+// @Summary Unarchive an issue
+// @Description Put an archived issue back on the board in its status column. Unarchiving a live issue changes nothing.
+// @Tags issues
+// @Produce json
+// @Param id path string true "Issue ID"
+// @Success 200 {object} models.Issue
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issues/{id}/unarchive [post]
+// @Security ApiKeyAuth
+func (h *Handler) UnarchiveIssue(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.Repo.UnarchiveIssue(r.Context(), id); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to unarchive issue", "issue_id", id)
+		return
+	}
+	h.writeIssue(w, r, id, http.StatusOK)
+}
+
+// writeIssue writes an issue just changed by the request with status
+func (h *Handler) writeIssue(w http.ResponseWriter, r *http.Request, id string, status int) {
+	issue, err := h.Repo.GetIssue(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to fetch issue", "issue_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issue", nil)
+		return
+	}
+	if issue == nil {
+		// Deleted between the change and the read
+		utils.WriteProblem(w, r, utils.CodeIssueNotFound, "", nil)
+		return
+	}
+	utils.WriteJSON(w, status, issue)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestArchive(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	open := createIssue(t, r, issueJSON("Open", "Todo"))
+	done := createIssue(t, r, issueJSON("Done", "Done"))
+
+	t.Run("Archive", func(t *testing.T) {
+		w := serve(r, "POST", "/issues/"+done.ID+"/archive", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var issue models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issue)
+		if issue.ID != done.ID || issue.ArchivedAt == nil {
+			t.Errorf("Expected the archived issue, got %+v", issue)
+		}
+		if w := serve(r, "POST", "/issues/"+done.ID+"/archive", ""); w.Code != http.StatusOK {
+			t.Errorf("Expected archiving twice to succeed, got %d", w.Code)
+		}
+
+		if issues := listIssues(t, r, ""); len(issues) != 1 || issues[0].ID != open.ID {
+			t.Errorf("Expected only %s on the board, got %+v", open.ID, issues)
+		}
+		if issues := listIssues(t, r, "?include_archived=true&status=Done"); len(issues) != 1 || issues[0].ID != done.ID {
+			t.Errorf("Expected the archived issue with include_archived, got %+v", issues)
+		}
+		if w := serve(r, "GET", "/issues/"+done.ID, ""); w.Code != http.StatusOK {
+			t.Errorf("Expected an archived issue to stay readable, got %d", w.Code)
+		}
+		if w := serve(r, "GET", "/issues?include_archived=maybe", ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a malformed include_archived, got %d", w.Code)
+		}
+	})
+
+	t.Run("Stats Count Archived Issues", func(t *testing.T) {
+		var stats models.IssueStats
+		json.Unmarshal(serve(r, "GET", "/stats", "").Body.Bytes(), &stats)
+		if stats.Total != 2 {
+			t.Errorf("Expected 2 issues counted, got %d", stats.Total)
+		}
+	})
+
+	t.Run("Unarchive", func(t *testing.T) {
+		w := serve(r, "POST", "/issues/"+done.ID+"/unarchive", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var issue models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issue)
+		if issue.ArchivedAt != nil {
+			t.Errorf("Expected the issue to be unarchived, got %+v", issue)
+		}
+		if issues := listIssues(t, r, ""); len(issues) != 2 {
+			t.Errorf("Expected both issues on the board, got %+v", issues)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		serve(r, "DELETE", "/issues/"+open.ID, "")
+		for _, url := range []string{"/issues/missing/archive", "/issues/" + open.ID + "/archive", "/issues/missing/unarchive"} {
+			if w := serve(r, "POST", url, ""); w.Code != http.StatusNotFound {
+				t.Errorf("Expected 404 for %s, got %d", url, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/middleware"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// oidcStateCookie binds a pending OIDC login to the browser that started it
+const oidcStateCookie = "oidc_state"
+
+// dummyHash is checked against when an email has no account, so unknown
+// emails take as long to reject as wrong passwords
+var dummyHash, _ = auth.HashPassword("dummy password")
+
+var errUnknownUser = errors.New("unknown user")
+
+// AuthHandler serves the login endpoints that create browser sessions
+type AuthHandler struct {
+	Repo         database.Store
+	SessionTTL   time.Duration
+	SecureCookie bool
+	SameSite     http.SameSite
+
+	// OIDC login, nil when disabled
+	OIDC         *auth.Provider
+	OIDCIssuer   string
+	PostLoginURL string
+
+	states auth.LoginStates
+}
+
+// Login godoc
+// @Summary Log in with email and password
+// @Description Starts a session for a local account. The session cookie is HTTP-only; the returned
+// @Description csrf_token must be sent in the X-CSRF-Token header of every non-GET request.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Email and password"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 200 {object} models.SessionInfo
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 401 {object} utils.Problem "Unauthorized"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req models.LoginRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode login request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+
+	account, err := h.Repo.GetAccountByEmail(ctx, req.Email)
+	if err != nil {
+		slog.Error("Failed to fetch account", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log in", nil)
+		return
+	}
+
+	hash := dummyHash
+	if account != nil {
+		hash = account.PasswordHash
+	}
+	ok, err := auth.CheckPassword(hash, req.Password)
+	if err != nil {
+		slog.Error("Failed to check password", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log in", nil)
+		return
+	}
+	if account == nil || !ok {
+		utils.WriteProblem(w, r, utils.CodeInvalidLogin, "", nil)
+		return
+	}
+
+	info, err := h.startSession(w, r, account.UserID)
+	if err != nil {
+		slog.Error("Failed to create session", "user_id", account.UserID, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log in", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, info)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Ends the current session. Requires the X-CSRF-Token header.
+// @Tags auth
+// @Param X-CSRF-Token header string true "CSRF token of the session"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 204 {object} nil
+// @Failure 403 {object} utils.Problem "Forbidden"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session, err := h.currentSession(r)
+	if err != nil {
+		slog.Error("Failed to fetch session", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log out", nil)
+		return
+	}
+
+	if session != nil {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(middleware.CSRFHeaderName)), []byte(session.CSRFToken)) != 1 {
+			utils.WriteProblem(w, r, utils.CodeCSRFTokenInvalid, "", nil)
+			return
+		}
+		if err := h.Repo.DeleteSession(ctx, session.ID); err != nil {
+			slog.Error("Failed to delete session", "error", err)
+			utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log out", nil)
+			return
+		}
+	}
+
+	h.setCookie(w, middleware.SessionCookieName, "", "/", -1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSession godoc
+// @Summary Get the current session
+// @Description Returns the logged-in user and the session's CSRF token, e.g. after a page reload
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.SessionInfo
+// @Failure 401 {object} utils.Problem "Unauthorized"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /auth/session [get]
+func (h *AuthHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session, err := h.currentSession(r)
+	if err != nil {
+		slog.Error("Failed to fetch session", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch session", nil)
+		return
+	}
+	if session == nil {
+		utils.WriteProblem(w, r, utils.CodeUnauthenticated, "Not logged in", nil)
+		return
+	}
+
+	user, err := h.Repo.GetUser(ctx, session.UserID)
+	if err != nil || user == nil {
+		slog.Error("Failed to fetch session user", "user_id", session.UserID, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch session", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, models.SessionInfo{User: *user, CSRFToken: session.CSRFToken, ExpiresAt: session.ExpiresAt})
+}
+
+// OIDCLogin godoc
+// @Summary Log in with the OIDC provider
+// @Description Redirects the browser to the configured issuer (authorization code flow with PKCE)
+// @Tags auth
+// @Success 302 {string} string "Redirect to the issuer"
+// @Failure 404 {object} utils.Problem "OIDC login is not configured"
+// @Router /auth/oidc/login [get]
+func (h *AuthHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if h.OIDC == nil {
+		utils.WriteProblem(w, r, utils.CodeOIDCNotConfigured, "", nil)
+		return
+	}
+
+	state, pending, err := h.states.Start()
+	if err != nil {
+		slog.Error("Failed to start OIDC login", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to start login", nil)
+		return
+	}
+	url, err := h.OIDC.AuthCodeURL(r.Context(), state, pending.Nonce, pending.CodeVerifier)
+	if err != nil {
+		slog.Error("Failed to build OIDC authorization URL", "error", err)
+		utils.WriteProblem(w, r, utils.CodeIdentityProviderDown, "", nil)
+		return
+	}
+
+	h.setCookie(w, oidcStateCookie, state, "/api/auth/oidc", int((10 * time.Minute).Seconds()))
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// OIDCCallback godoc
+// @Summary Complete an OIDC login
+// @Description Redirect target of the issuer. Exchanges the code, verifies the ID token, links or
+// @Description creates the user, starts a session and redirects to the frontend.
+// @Tags auth
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 302 {string} string "Redirect to the frontend"
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 401 {object} utils.Problem "Unauthorized"
+// @Router /auth/oidc/callback [get]
+func (h *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.OIDC == nil {
+		utils.WriteProblem(w, r, utils.CodeOIDCNotConfigured, "", nil)
+		return
+	}
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		utils.WriteProblem(w, r, utils.CodeLoginFailed, "The identity provider returned "+e, map[string]interface{}{"provider_error": e})
+		return
+	}
+
+	state := q.Get("state")
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		utils.WriteProblem(w, r, utils.CodeInvalidLoginState, "", nil)
+		return
+	}
+	pending, ok := h.states.Take(state)
+	if !ok {
+		utils.WriteProblem(w, r, utils.CodeInvalidLoginState, "", nil)
+		return
+	}
+	h.setCookie(w, oidcStateCookie, "", "/api/auth/oidc", -1)
+
+	claims, err := h.OIDC.Exchange(ctx, q.Get("code"), pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		slog.Warn("OIDC code exchange failed", "error", err)
+		utils.WriteProblem(w, r, utils.CodeLoginFailed, "", nil)
+		return
+	}
+
+	userID, err := h.oidcUser(r, claims)
+	if err != nil {
+		slog.Error("Failed to resolve OIDC user", "subject", claims.String("sub"), "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log in", nil)
+		return
+	}
+	if _, err := h.startSession(w, r, userID); err != nil {
+		slog.Error("Failed to create session", "user_id", userID, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to log in", nil)
+		return
+	}
+	http.Redirect(w, r, h.PostLoginURL, http.StatusFound)
+}
+
+// oidcUser returns the user linked to the token's subject, creating the user
+// on first login
+func (h *AuthHandler) oidcUser(r *http.Request, claims auth.Claims) (string, error) {
+	ctx := r.Context()
+	identity, err := h.Repo.GetIdentity(ctx, h.OIDCIssuer, claims.String("sub"))
+	if err != nil {
+		return "", err
+	}
+	if identity != nil {
+		return identity.UserID, nil
+	}
+
+	name := claims.String("name")
+	if name == "" {
+		name = claims.String("email")
+	}
+	if name == "" {
+		name = claims.String("sub")
+	}
+	user := models.User{ID: uuid.New().String(), Name: name, AvatarURL: claims.String("picture")}
+	if err := h.Repo.CreateUser(ctx, user); err != nil {
+		return "", err
+	}
+	if err := h.Repo.CreateIdentity(ctx, models.Identity{Issuer: h.OIDCIssuer, Subject: claims.String("sub"), UserID: user.ID}); err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+// startSession creates a session for userID and sets its cookie
+func (h *AuthHandler) startSession(w http.ResponseWriter, r *http.Request, userID string) (*models.SessionInfo, error) {
+	ctx := r.Context()
+	user, err := h.Repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errUnknownUser
+	}
+
+	token, err := auth.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	csrf, err := auth.NewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	session := models.Session{
+		ID:        auth.HashToken(token),
+		UserID:    userID,
+		CSRFToken: csrf,
+		CreatedAt: now,
+		ExpiresAt: now.Add(h.SessionTTL),
+	}
+	if err := h.Repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	h.setCookie(w, middleware.SessionCookieName, token, "/", int(h.SessionTTL.Seconds()))
+	return &models.SessionInfo{User: *user, CSRFToken: csrf, ExpiresAt: session.ExpiresAt}, nil
+}
+
+// currentSession returns the unexpired session of the request's cookie, or nil
+func (h *AuthHandler) currentSession(r *http.Request) (*models.Session, error) {
+	cookie, err := r.Cookie(middleware.SessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil, nil
+	}
+	session, err := h.Repo.GetSession(r.Context(), auth.HashToken(cookie.Value))
+	if err != nil || session == nil || !time.Now().Before(session.ExpiresAt) {
+		return nil, err
+	}
+	return session, nil
+}
+
+// setCookie sets an HTTP-only cookie; a negative maxAge deletes it
+func (h *AuthHandler) setCookie(w http.ResponseWriter, name, value, path string, maxAge int) {
+	sameSite := h.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+	if name == oidcStateCookie && sameSite == http.SameSiteStrictMode {
+		// The callback is a cross-site redirect from the issuer
+		sameSite = http.SameSiteLaxMode
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.SecureCookie,
+		SameSite: sameSite,
+	})
+}
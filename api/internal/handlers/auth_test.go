@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/auth/mockidp"
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/middleware"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+func setupAuthRouter(ah *AuthHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Post("/auth/login", ah.Login)
+	r.Post("/auth/logout", ah.Logout)
+	r.Get("/auth/session", ah.GetSession)
+	r.Get("/auth/oidc/login", ah.OIDCLogin)
+	r.Get("/auth/oidc/callback", ah.OIDCCallback)
+	return r
+}
+
+// responseCookie returns the cookie called name set by a response
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func createAccount(t *testing.T, repo *database.Repository, userID, email, password string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := repo.CreateUser(ctx, models.User{ID: userID, Name: "Test User"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	if err := repo.CreateAccount(ctx, models.Account{UserID: userID, Email: email, PasswordHash: hash}); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+}
+
+func TestLocalLogin(t *testing.T) {
+	repo := setupTestDB(t)
+	createAccount(t, repo, "user1", "alice@example.com", "correct horse")
+	r := setupAuthRouter(&AuthHandler{Repo: repo, SessionTTL: time.Hour, SecureCookie: true})
+
+	do := func(method, url, body string, cookie *http.Cookie, csrf string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		if csrf != "" {
+			req.Header.Set(middleware.CSRFHeaderName, csrf)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		w := do("POST", "/auth/login", `{"email": "alice@example.com", "password": "wrong"}`, nil, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+		if responseCookie(w, middleware.SessionCookieName) != nil {
+			t.Error("Expected no session cookie")
+		}
+	})
+
+	t.Run("Unknown Email", func(t *testing.T) {
+		w := do("POST", "/auth/login", `{"email": "bob@example.com", "password": "correct horse"}`, nil, "")
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+
+	w := do("POST", "/auth/login", `{"email": "Alice@Example.com", "password": "correct horse"}`, nil, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var info models.SessionInfo
+	json.Unmarshal(w.Body.Bytes(), &info)
+	if info.User.ID != "user1" || info.CSRFToken == "" {
+		t.Fatalf("Unexpected session info: %+v", info)
+	}
+
+	cookie := responseCookie(w, middleware.SessionCookieName)
+	if cookie == nil {
+		t.Fatal("Expected a session cookie")
+	}
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/" {
+		t.Errorf("Unexpected cookie attributes: %+v", cookie)
+	}
+
+	t.Run("Session", func(t *testing.T) {
+		w := do("GET", "/auth/session", "", cookie, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var got models.SessionInfo
+		json.Unmarshal(w.Body.Bytes(), &got)
+		if got.User.ID != "user1" || got.CSRFToken != info.CSRFToken {
+			t.Errorf("Unexpected session info: %+v", got)
+		}
+	})
+
+	t.Run("Logout Requires CSRF Token", func(t *testing.T) {
+		if w := do("POST", "/auth/logout", "", cookie, ""); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		w := do("POST", "/auth/logout", "", cookie, info.CSRFToken)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", w.Code)
+		}
+		if c := responseCookie(w, middleware.SessionCookieName); c == nil || c.MaxAge >= 0 {
+			t.Errorf("Expected the session cookie to be cleared, got %+v", c)
+		}
+		if w := do("GET", "/auth/session", "", cookie, ""); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 after logout, got %d", w.Code)
+		}
+	})
+}
+
+func TestOIDCLogin(t *testing.T) {
+	repo := setupTestDB(t)
+	idp := mockidp.New("issue-board")
+	defer idp.Close()
+
+	callback := "http://localhost:8080/api/auth/oidc/callback"
+	ah := &AuthHandler{
+		Repo:       repo,
+		SessionTTL: time.Hour,
+		OIDC: auth.NewProvider(auth.OIDCConfig{
+			Issuer:      idp.Issuer(),
+			ClientID:    "issue-board",
+			RedirectURL: callback,
+		}, idp.Client()),
+		OIDCIssuer:   idp.Issuer(),
+		PostLoginURL: "/board",
+	}
+	r := setupAuthRouter(ah)
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	// login runs the browser side of the flow and returns the callback response
+	login := func(t *testing.T) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/auth/oidc/login", nil))
+		if w.Code != http.StatusFound {
+			t.Fatalf("Expected status 302, got %d", w.Code)
+		}
+		stateCookie := responseCookie(w, oidcStateCookie)
+		if stateCookie == nil {
+			t.Fatal("Expected a state cookie")
+		}
+
+		resp, err := noRedirect.Get(w.Header().Get("Location"))
+		if err != nil {
+			t.Fatalf("Authorize request failed: %v", err)
+		}
+		resp.Body.Close()
+		redirect, err := url.Parse(resp.Header.Get("Location"))
+		if err != nil || resp.StatusCode != http.StatusFound {
+			t.Fatalf("Expected a redirect from the issuer, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+		}
+
+		req := httptest.NewRequest("GET", "/auth/oidc/callback?"+redirect.RawQuery, nil)
+		req.AddCookie(stateCookie)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	var userID string
+	t.Run("First Login Creates User", func(t *testing.T) {
+		w := login(t)
+		if w.Code != http.StatusFound || w.Header().Get("Location") != "/board" {
+			t.Fatalf("Expected redirect to /board, got %d %q. Body: %s", w.Code, w.Header().Get("Location"), w.Body.String())
+		}
+		if responseCookie(w, middleware.SessionCookieName) == nil {
+			t.Fatal("Expected a session cookie")
+		}
+
+		identity, err := repo.GetIdentity(context.Background(), idp.Issuer(), "mock-user")
+		if err != nil || identity == nil {
+			t.Fatalf("Expected a linked identity, got %v, %v", identity, err)
+		}
+		userID = identity.UserID
+		user, _ := repo.GetUser(context.Background(), userID)
+		if user == nil || user.Name != "Mock User" {
+			t.Errorf("Unexpected user: %+v", user)
+		}
+	})
+
+	t.Run("Second Login Reuses User", func(t *testing.T) {
+		if w := login(t); w.Code != http.StatusFound {
+			t.Fatalf("Expected status 302, got %d", w.Code)
+		}
+		users, _ := repo.GetUsers(context.Background())
+		if len(users) != 1 {
+			t.Errorf("Expected 1 user, got %d", len(users))
+		}
+		identity, _ := repo.GetIdentity(context.Background(), idp.Issuer(), "mock-user")
+		if identity == nil || identity.UserID != userID {
+			t.Errorf("Expected identity to stay linked to %s, got %+v", userID, identity)
+		}
+	})
+
+	t.Run("State Mismatch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/auth/oidc/login", nil))
+
+		req := httptest.NewRequest("GET", "/auth/oidc/callback?code=x&state=forged", nil)
+		req.AddCookie(responseCookie(w, oidcStateCookie))
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Not Configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupAuthRouter(&AuthHandler{Repo: repo}).ServeHTTP(w, httptest.NewRequest("GET", "/auth/oidc/login", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
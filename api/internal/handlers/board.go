@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	_ "github.com/abhir9/issue-board/api/internal/models" // response types named in the swag annotations
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// GetBoardVersion godoc
+// @Summary Get the board version
+// @Description Get the board-wide change counter, which every write to issues, labels or users advances.
+// @Description Polling clients compare it (or send If-None-Match) to learn that nothing changed without listing issues.
+// @Tags board
+// @Produce json
+// @Param If-None-Match header string false "ETag of the version the client holds"
+// @Success 200 {object} models.BoardVersion
+// @Success 304 "Not Modified"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /board/version [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetBoardVersion(w http.ResponseWriter, r *http.Request) {
+	v, err := h.Repo.GetBoardVersion(r.Context())
+	if err != nil {
+		slog.Error("Failed to fetch board version", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch board version", nil)
+		return
+	}
+	version := strconv.FormatInt(v.Version, 10)
+	w.Header().Set("X-Board-Version", version)
+	if utils.WriteValidators(w, r, utils.ETag(version, r.URL.Path), v.UpdatedAt) {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, v)
+}
+
+// notModified sets ETag and Last-Modified for the representation of r from
+// the board version, which changes with every write its content depends on.
+// It returns true once it has answered the request, with 304 when the
+// client's copy is current, before the caller runs any list queries.
+func (h *Handler) notModified(w http.ResponseWriter, r *http.Request) bool {
+	v, err := h.Repo.GetBoardVersion(r.Context())
+	if err != nil {
+		slog.Error("Failed to fetch board version", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch board version", nil)
+		return true
+	}
+	version := strconv.FormatInt(v.Version, 10)
+	w.Header().Set("X-Board-Version", version)
+	return utils.WriteValidators(w, r, utils.ETag(version, r.URL.Path, r.URL.RawQuery), v.UpdatedAt)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestConditionalGet(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	serve(r, "POST", "/issues", issueJSON("First", "Todo"))
+
+	first := serve(r, "GET", "/issues", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Last-Modified") == "" {
+		t.Fatalf("Expected 200 with ETag and Last-Modified, got %d %v", first.Code, first.Header())
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		w := serveWithHeaders(r, "GET", "/issues", "", map[string]string{"If-None-Match": etag})
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("Expected an empty 304, got %d %s", w.Code, w.Body.String())
+		}
+		w = serveWithHeaders(r, "GET", "/issues", "", map[string]string{"If-Modified-Since": first.Header().Get("Last-Modified")})
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for If-Modified-Since, got %d", w.Code)
+		}
+	})
+
+	t.Run("Representations Differ By Query", func(t *testing.T) {
+		w := serveWithHeaders(r, "GET", "/issues?status=Done", "", map[string]string{"If-None-Match": etag})
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+			t.Errorf("Expected another query to have its own ETag, got %d", w.Code)
+		}
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		serve(r, "POST", "/issues", issueJSON("Second", "Todo"))
+		w := serveWithHeaders(r, "GET", "/issues", "", map[string]string{"If-None-Match": etag})
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+			t.Fatalf("Expected 200 with a new ETag after a write, got %d", w.Code)
+		}
+		var issues []models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issues)
+		if len(issues) != 2 {
+			t.Errorf("Expected 2 issues, got %d", len(issues))
+		}
+	})
+
+	t.Run("Board Version", func(t *testing.T) {
+		w := serve(r, "GET", "/board/version", "")
+		var v models.BoardVersion
+		json.Unmarshal(w.Body.Bytes(), &v)
+		if w.Code != http.StatusOK || v.Version == 0 || w.Header().Get("X-Board-Version") == "" {
+			t.Fatalf("Expected the board version, got %d %s", w.Code, w.Body.String())
+		}
+		if w := serveWithHeaders(r, "GET", "/board/version", "", map[string]string{"If-None-Match": w.Header().Get("ETag")}); w.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for an unchanged version, got %d", w.Code)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		w := serve(r, "GET", "/stats", "")
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == "" {
+			t.Fatalf("Expected stats with an ETag, got %d", w.Code)
+		}
+		if w := serveWithHeaders(r, "GET", "/stats", "", map[string]string{"If-None-Match": w.Header().Get("ETag")}); w.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for unchanged stats, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/middleware"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetComments godoc
+// @Summary List the comments of an issue
+// @Description List the comments of an issue, oldest first
+// @Tags issues
+// @Produce json
+// @Param id path string true "Issue ID"
+// @Success 200 {array} models.Comment
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issues/{id}/comments [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	issue, err := h.Repo.GetIssue(ctx, id)
+	if err != nil {
+		slog.Error("Failed to fetch issue", "issue_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issue", nil)
+		return
+	}
+	if issue == nil {
+		utils.WriteProblem(w, r, utils.CodeIssueNotFound, "", nil)
+		return
+	}
+
+	comments, err := h.Repo.ListComments(ctx, id)
+	if err != nil {
+		slog.Error("Failed to fetch comments", "issue_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch comments", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, comments)
+}
+
+// CreateComment godoc
+// @Summary Comment on an issue
+// @Description Add a comment to an issue. The author is the authenticated user, if any.
+// @Tags issues
+// @Accept json
+// @Produce json
+// @Param id path string true "Issue ID"
+// @Param comment body models.CreateCommentRequest true "Comment to add"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issues/{id}/comments [post]
+// @Security ApiKeyAuth
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var req models.CreateCommentRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode create comment request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Required("body", req.Body)
+	v.MaxLength("body", req.Body, 10000)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	comment := models.Comment{
+		ID:        uuid.New().String(),
+		IssueID:   id,
+		Body:      req.Body,
+		CreatedAt: time.Now(),
+	}
+	if p := middleware.PrincipalFrom(r.Context()); p != nil && p.UserID != "" {
+		comment.AuthorID = &p.UserID
+	}
+
+	if err := h.Repo.CreateComment(r.Context(), comment); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to create comment", "issue_id", id)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, comment)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestCommentsAndSubIssues(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	parent := createIssue(t, r, issueJSON("Parent", "Todo"))
+	child := createIssue(t, r, fmt.Sprintf(`{"title": "Child", "status": "Todo", "priority": "Low", "parent_id": %q}`, parent.ID))
+
+	t.Run("Create Sub-issue", func(t *testing.T) {
+		if child.ParentID == nil || *child.ParentID != parent.ID {
+			t.Errorf("Expected parent_id %s, got %v", parent.ID, child.ParentID)
+		}
+		w := serve(r, "POST", "/issues", `{"title": "Orphan", "status": "Todo", "priority": "Low", "parent_id": "00000000-0000-0000-0000-000000000000"}`)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for an unknown parent, got %d: %s", w.Code, w.Body.String())
+		}
+		w = serve(r, "POST", "/issues", `{"title": "Orphan", "status": "Todo", "priority": "Low", "parent_id": "nope"}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a malformed parent_id, got %d", w.Code)
+		}
+	})
+
+	t.Run("Reject Cycles", func(t *testing.T) {
+		w := serve(r, "PATCH", "/issues/"+parent.ID, fmt.Sprintf(`{"parent_id": %q}`, child.ID))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for a cycle, got %d: %s", w.Code, w.Body.String())
+		}
+		w = serve(r, "PATCH", "/issues/"+parent.ID, fmt.Sprintf(`{"parent_id": %q}`, parent.ID))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for an issue parenting itself, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Comments", func(t *testing.T) {
+		w := serve(r, "POST", "/issues/"+parent.ID+"/comments", `{"body": "First"}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		serve(r, "POST", "/issues/"+parent.ID+"/comments", `{"body": "Second"}`)
+
+		if w := serve(r, "POST", "/issues/"+parent.ID+"/comments", `{"body": ""}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for an empty body, got %d", w.Code)
+		}
+		if w := serve(r, "POST", "/issues/missing/comments", `{"body": "Hi"}`); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for a missing issue, got %d", w.Code)
+		}
+
+		var comments []models.Comment
+		json.Unmarshal(serve(r, "GET", "/issues/"+parent.ID+"/comments", "").Body.Bytes(), &comments)
+		if len(comments) != 2 || comments[0].Body != "First" || comments[1].Body != "Second" {
+			t.Errorf("Expected both comments oldest first, got %+v", comments)
+		}
+		if w := serve(r, "GET", "/issues/missing/comments", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for a missing issue, got %d", w.Code)
+		}
+	})
+
+	t.Run("Expand Comments Count And Children", func(t *testing.T) {
+		w := serve(r, "GET", "/issues?fields=id&expand=comments_count,children", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var issues []struct {
+			ID            string            `json:"id"`
+			CommentsCount *int              `json:"comments_count"`
+			Children      []models.IssueRef `json:"children"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &issues)
+
+		found := 0
+		for _, issue := range issues {
+			switch issue.ID {
+			case parent.ID:
+				found++
+				if issue.CommentsCount == nil || *issue.CommentsCount != 2 {
+					t.Errorf("Expected 2 comments on the parent, got %v", issue.CommentsCount)
+				}
+				if len(issue.Children) != 1 || issue.Children[0].ID != child.ID || issue.Children[0].Title != "Child" {
+					t.Errorf("Expected the child issue, got %+v", issue.Children)
+				}
+			case child.ID:
+				found++
+				if issue.CommentsCount == nil || *issue.CommentsCount != 0 {
+					t.Errorf("Expected 0 comments on the child, got %v", issue.CommentsCount)
+				}
+				if issue.Children == nil || len(issue.Children) != 0 {
+					t.Errorf("Expected no children, got %+v", issue.Children)
+				}
+			}
+		}
+		if found != 2 {
+			t.Errorf("Expected both issues listed, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Detach Sub-issue", func(t *testing.T) {
+		w := serve(r, "PATCH", "/issues/"+child.ID, `{"parent_id": ""}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var issue models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issue)
+		if issue.ParentID != nil {
+			t.Errorf("Expected no parent, got %v", *issue.ParentID)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// writeStoreError responds to a failed store call by its sentinel: a missing
+// record gets notFound (404), a conflict 409, listing the violations of a
+// strict WIP limit, and a broken constraint 422.
+// Anything else is logged with attrs and reported as an internal error
+// with msg.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error, notFound utils.Code, msg string, attrs ...any) {
+	var wipErr *database.WIPError
+	switch {
+	case isReferenceError(err):
+		writeReferenceError(w, r, err)
+	case errors.As(err, &wipErr):
+		utils.WriteProblem(w, r, utils.CodeWIPLimitExceeded, "", map[string]interface{}{"violations": wipErr.Violations})
+	case errors.Is(err, database.ErrNotFound):
+		utils.WriteProblem(w, r, notFound, "", nil)
+	case errors.Is(err, database.ErrConflict):
+		slog.Warn(msg, append(attrs, "error", err)...)
+		utils.WriteProblem(w, r, utils.CodeConflict, "", nil)
+	case errors.Is(err, database.ErrConstraint):
+		slog.Warn(msg, append(attrs, "error", err)...)
+		utils.WriteProblem(w, r, utils.CodeConstraintViolation, "", nil)
+	default:
+		slog.Error(msg, append(attrs, "error", err)...)
+		utils.WriteProblem(w, r, utils.CodeInternal, msg, nil)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestWriteStoreError(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantCode   utils.Code
+	}{
+		{fmt.Errorf("issue 1: %w", database.ErrNotFound), http.StatusNotFound, utils.CodeIssueNotFound},
+		{fmt.Errorf("failed to create user: %w: duplicate id", database.ErrConflict), http.StatusConflict, utils.CodeConflict},
+		{fmt.Errorf("failed to update issue: %w", database.ErrConstraint), http.StatusUnprocessableEntity, utils.CodeConstraintViolation},
+		{&database.ReferenceError{AssigneeID: "ghost"}, http.StatusUnprocessableEntity, utils.CodeUnknownReference},
+		{errors.New("disk full"), http.StatusInternalServerError, utils.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeStoreError(w, httptest.NewRequest("PATCH", "/issues/1", nil), tt.err, utils.CodeIssueNotFound, "Failed to update issue")
+
+		var p utils.Problem
+		json.Unmarshal(w.Body.Bytes(), &p)
+		if w.Code != tt.wantStatus || p.Code != tt.wantCode {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.wantStatus, tt.wantCode, w.Code, p.Code)
+		}
+	}
+}
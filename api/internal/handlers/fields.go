@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	maxFieldOptions   = 100
+	maxFieldTextValue = 1000
+)
+
+// GetFields godoc
+// @Summary List custom fields
+// @Description List the custom field definitions, ordered by key
+// @Tags fields
+// @Produce json
+// @Success 200 {array} models.CustomField
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /fields [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetFields(w http.ResponseWriter, r *http.Request) {
+	fields, err := h.Repo.ListFields(r.Context())
+	if err != nil {
+		slog.Error("Failed to fetch custom fields", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch custom fields", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, fields)
+}
+
+// GetField godoc
+// @Summary Get a custom field
+// @Tags fields
+// @Produce json
+// @Param id path string true "Field ID"
+// @Success 200 {object} models.CustomField
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /fields/{id} [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetField(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	field, ok := h.findField(w, r, id)
+	if !ok {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, field)
+}
+
+// CreateField godoc
+// @Summary Define a custom field
+// @Description Define an issue attribute of type text, number, date, select, multi_select or user.
+// @Description Select fields need options; the key and type cannot change later.
+// @Tags fields
+// @Accept json
+// @Produce json
+// @Param field body models.CreateFieldRequest true "Field definition"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 201 {object} models.CustomField
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 409 {object} utils.Problem "Key already in use"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /fields [post]
+// @Security ApiKeyAuth
+func (h *Handler) CreateField(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateFieldRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode create field request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+	if err := validateCreateFieldRequest(&req); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	now := time.Now()
+	field := models.CustomField{
+		ID:        uuid.New().String(),
+		Key:       req.Key,
+		Name:      req.Name,
+		Type:      req.Type,
+		Options:   req.Options,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := h.Repo.CreateField(r.Context(), field); err != nil {
+		if errors.Is(err, database.ErrConflict) {
+			utils.WriteProblem(w, r, utils.CodeFieldKeyTaken, "", nil)
+			return
+		}
+		writeStoreError(w, r, err, utils.CodeFieldNotFound, "Failed to create custom field", "field_id", field.ID)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, field)
+}
+
+// UpdateField godoc
+// @Summary Update a custom field
+// @Description Rename a custom field or change its options. Options can be added and reordered
+// @Description but not removed, so that existing values stay valid.
+// @Tags fields
+// @Accept json
+// @Produce json
+// @Param id path string true "Field ID"
+// @Param field body models.UpdateFieldRequest true "Field changes"
+// @Success 200 {object} models.CustomField
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /fields/{id} [patch]
+// @Security ApiKeyAuth
+func (h *Handler) UpdateField(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req models.UpdateFieldRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode update field request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+
+	field, ok := h.findField(w, r, id)
+	if !ok {
+		return
+	}
+	if err := validateUpdateFieldRequest(&req, *field); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	if req.Name != nil {
+		field.Name = *req.Name
+	}
+	if req.Options != nil {
+		field.Options = req.Options
+	}
+	field.UpdatedAt = time.Now()
+	if err := h.Repo.UpdateField(ctx, *field); err != nil {
+		writeStoreError(w, r, err, utils.CodeFieldNotFound, "Failed to update custom field", "field_id", id)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, field)
+}
+
+// DeleteField godoc
+// @Summary Delete a custom field
+// @Description Delete a custom field together with its values on every issue
+// @Tags fields
+// @Param id path string true "Field ID"
+// @Success 204 {object} nil
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /fields/{id} [delete]
+// @Security ApiKeyAuth
+func (h *Handler) DeleteField(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.Repo.DeleteField(r.Context(), id); err != nil {
+		writeStoreError(w, r, err, utils.CodeFieldNotFound, "Failed to delete custom field", "field_id", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findField fetches a custom field. It writes the error response and
+// returns false if it does not exist.
+func (h *Handler) findField(w http.ResponseWriter, r *http.Request, id string) (*models.CustomField, bool) {
+	field, err := h.Repo.GetField(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to fetch custom field", "field_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch custom field", nil)
+		return nil, false
+	}
+	if field == nil {
+		utils.WriteProblem(w, r, utils.CodeFieldNotFound, "", nil)
+		return nil, false
+	}
+	return field, true
+}
+
+// fieldsByKey returns the custom field definitions keyed by key
+func (h *Handler) fieldsByKey(ctx context.Context) (map[string]models.CustomField, error) {
+	fields, err := h.Repo.ListFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]models.CustomField, len(fields))
+	for _, f := range fields {
+		byKey[f.Key] = f
+	}
+	return byKey, nil
+}
+
+// parseFieldValues validates the custom field values of an issue request
+// against their definitions and checks that referenced users exist. A null,
+// empty string or empty list clears a field. It writes the error response
+// and returns false if a value is invalid.
+func (h *Handler) parseFieldValues(w http.ResponseWriter, r *http.Request, raw map[string]json.RawMessage) (models.FieldValues, bool) {
+	if len(raw) == 0 {
+		return nil, true
+	}
+	ctx := r.Context()
+	fields, err := h.fieldsByKey(ctx)
+	if err != nil {
+		slog.Error("Failed to fetch custom fields", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch custom fields", nil)
+		return nil, false
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	v := validator.New()
+	values := make(models.FieldValues, len(raw))
+	for _, key := range keys {
+		name := "custom_fields." + key
+		f, ok := fields[key]
+		if !ok {
+			v.AddError(name, "unknown custom field")
+			continue
+		}
+		values[key] = parseFieldValue(v, name, f, raw[key])
+	}
+	if err := v.Err(); err != nil {
+		writeValidationError(w, r, err)
+		return nil, false
+	}
+
+	for _, key := range keys {
+		if id, ok := values[key].(string); ok && fields[key].Type == models.FieldUser {
+			if err := v.Exist(ctx, "custom_fields."+key, []string{id}, h.missingUsers); err != nil {
+				slog.Error("Failed to check references", "error", err)
+				utils.WriteProblem(w, r, utils.CodeInternal, "Failed to check references", nil)
+				return nil, false
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		writeReferenceError(w, r, err)
+		return nil, false
+	}
+	return values, true
+}
+
+// parseFieldValue decodes a JSON value of a custom field, adding an error
+// under name to v if it does not fit the field's type. It returns nil for
+// a cleared or invalid value.
+func parseFieldValue(v *validator.Validator, name string, f models.CustomField, raw json.RawMessage) interface{} {
+	if string(raw) == "null" {
+		return nil
+	}
+
+	if f.Type == models.FieldNumber {
+		var n float64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			v.AddError(name, "must be a number")
+			return nil
+		}
+		return n
+	}
+
+	if f.Type == models.FieldMultiSelect {
+		var options []string
+		if err := json.Unmarshal(raw, &options); err != nil {
+			v.AddError(name, "must be a list of options")
+			return nil
+		}
+		seen := make(map[string]bool, len(options))
+		var values []string
+		for i, o := range options {
+			v.OneOf(fmt.Sprintf("%s[%d]", name, i), o, f.Options)
+			if !seen[o] {
+				seen[o] = true
+				values = append(values, o)
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		sort.Strings(values)
+		return values
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		v.AddError(name, "must be a string")
+		return nil
+	}
+	if s == "" {
+		return nil
+	}
+	if err := checkFieldString(v, name, f, s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// checkFieldString validates the string value of a text, date, select or
+// user field, adding an error under name to v and returning it if invalid
+func checkFieldString(v *validator.Validator, name string, f models.CustomField, s string) error {
+	switch f.Type {
+	case models.FieldText:
+		v.MaxLength(name, s, maxFieldTextValue)
+	case models.FieldDate:
+		if _, err := time.Parse(models.DateLayout, s); err != nil {
+			v.Add(name, validator.CodeInvalidDate, "must be a date such as 2024-01-31")
+		}
+	case models.FieldSelect:
+		v.OneOf(name, s, f.Options)
+	case models.FieldUser:
+		v.UUID(name, s)
+	}
+	if v.HasError(name) {
+		return errors.New(name + " is invalid")
+	}
+	return nil
+}
+
+// parseFieldQuery resolves the custom field filters (cf.<key>=value,
+// repeatable to match any of several values) and custom sort keys of an
+// issue list query. It writes the error response and returns false for an
+// unknown field or an invalid value.
+func (h *Handler) parseFieldQuery(w http.ResponseWriter, r *http.Request, q *models.IssueQuery) bool {
+	query := r.URL.Query()
+	var params []string
+	for param := range query {
+		if strings.HasPrefix(param, models.CustomFieldPrefix) {
+			params = append(params, param)
+		}
+	}
+	sorted := false
+	for _, k := range q.Sort {
+		_, custom := models.ParseFieldKey(k.Field)
+		sorted = sorted || custom
+	}
+	if len(params) == 0 && !sorted {
+		return true
+	}
+
+	fields, err := h.fieldsByKey(r.Context())
+	if err != nil {
+		slog.Error("Failed to fetch custom fields", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch custom fields", nil)
+		return false
+	}
+
+	v := validator.New()
+	sort.Strings(params)
+	for _, param := range params {
+		f, ok := fields[strings.TrimPrefix(param, models.CustomFieldPrefix)]
+		if !ok {
+			v.AddError(param, "unknown custom field")
+			continue
+		}
+		filter := models.CustomFieldFilter{Field: f}
+		for _, value := range query[param] {
+			if f.Type == models.FieldNumber {
+				n, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					v.AddError(param, "must be a number")
+					continue
+				}
+				value = models.FormatNumber(n)
+			} else if f.Type == models.FieldMultiSelect {
+				v.OneOf(param, value, f.Options)
+			} else if checkFieldString(v, param, f, value) != nil {
+				continue
+			}
+			filter.Values = append(filter.Values, value)
+		}
+		q.CustomFields = append(q.CustomFields, filter)
+	}
+
+	for i, k := range q.Sort {
+		key, custom := models.ParseFieldKey(k.Field)
+		if !custom {
+			continue
+		}
+		f, ok := fields[key]
+		switch {
+		case !ok:
+			v.AddError("sort", fmt.Sprintf("unknown custom field %q", key))
+		case !f.Sortable():
+			v.AddError("sort", fmt.Sprintf("cannot sort by %s field %q", f.Type, key))
+		default:
+			q.Sort[i].Custom = &f
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		writeQueryError(w, r, "", err)
+		return false
+	}
+	return true
+}
+
+// validateCreateFieldRequest validates a custom field definition. Names
+// and options are trimmed and duplicate options dropped.
+func validateCreateFieldRequest(req *models.CreateFieldRequest) error {
+	v := validator.New()
+
+	v.Required("key", req.Key)
+	if req.Key != "" && !models.ValidFieldKey(req.Key) {
+		v.AddError("key", "must be up to 50 lowercase letters, digits and underscores, starting with a letter")
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 100)
+	v.Required("type", req.Type)
+	if req.Type != "" {
+		v.OneOf("type", req.Type, models.FieldTypes)
+	}
+
+	field := models.CustomField{Type: req.Type}
+	req.Options = validateFieldOptions(v, field, req.Options)
+	return v.Err()
+}
+
+// validateUpdateFieldRequest validates changes to a custom field. Options
+// must keep all of the field's current ones.
+func validateUpdateFieldRequest(req *models.UpdateFieldRequest, field models.CustomField) error {
+	v := validator.New()
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+		v.Required("name", name)
+		v.MaxLength("name", name, 100)
+	}
+	if req.Options != nil {
+		req.Options = validateFieldOptions(v, field, req.Options)
+		for _, o := range field.Options {
+			if !v.HasError("options") && !contains(req.Options, o) {
+				v.AddError("options", fmt.Sprintf("cannot remove option %q", o))
+			}
+		}
+	}
+	return v.Err()
+}
+
+// validateFieldOptions checks the options of a field of the given type and
+// returns them trimmed and deduplicated
+func validateFieldOptions(v *validator.Validator, field models.CustomField, options []string) []string {
+	if !field.HasOptions() {
+		if len(options) > 0 {
+			v.AddError("options", "only select and multi_select fields have options")
+		}
+		return nil
+	}
+	if len(options) == 0 {
+		v.Add("options", validator.CodeRequired, "is required")
+		return nil
+	}
+	if len(options) > maxFieldOptions {
+		v.AddError("options", fmt.Sprintf("must not exceed %d options", maxFieldOptions))
+		return nil
+	}
+
+	seen := make(map[string]bool, len(options))
+	var trimmed []string
+	for i, o := range options {
+		o = strings.TrimSpace(o)
+		name := fmt.Sprintf("options[%d]", i)
+		v.Required(name, o)
+		v.MaxLength(name, o, 100)
+		if o != "" && !seen[o] {
+			seen[o] = true
+			trimmed = append(trimmed, o)
+		}
+	}
+	return trimmed
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestCustomFields(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	alice := "5b0c1d2e-3f4a-4b5c-8d6e-7f8091a2b3c4"
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES (?, 'Alice')", alice)
+
+	titles := func(issues []models.Issue) []string {
+		out := make([]string, len(issues))
+		for i, issue := range issues {
+			out[i] = issue.Title
+		}
+		return out
+	}
+
+	var severity models.CustomField
+	t.Run("Define", func(t *testing.T) {
+		w := serve(r, "POST", "/fields", `{"key": "severity", "name": " Severity ", "type": "select", "options": ["Low", "High", " Critical ", "High"]}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		json.Unmarshal(w.Body.Bytes(), &severity)
+		if severity.Name != "Severity" || fmt.Sprint(severity.Options) != "[Low High Critical]" {
+			t.Errorf("Expected trimmed, deduplicated options, got %+v", severity)
+		}
+		for _, body := range []string{
+			`{"key": "customer", "name": "Customer", "type": "text"}`,
+			`{"key": "estimate", "name": "Estimate", "type": "number"}`,
+			`{"key": "due", "name": "Due", "type": "date"}`,
+			`{"key": "platforms", "name": "Platforms", "type": "multi_select", "options": ["web", "ios", "android"]}`,
+			`{"key": "reviewer", "name": "Reviewer", "type": "user"}`,
+		} {
+			if w := serve(r, "POST", "/fields", body); w.Code != http.StatusCreated {
+				t.Fatalf("Expected status 201 for %s, got %d: %s", body, w.Code, w.Body.String())
+			}
+		}
+
+		if w := serve(r, "POST", "/fields", `{"key": "severity", "name": "Again", "type": "text"}`); w.Code != http.StatusConflict || problemCode(w) != utils.CodeFieldKeyTaken {
+			t.Errorf("Expected 409 FIELD_KEY_TAKEN, got %d %s", w.Code, w.Body.String())
+		}
+		for _, body := range []string{
+			`{"key": "Bad Key", "name": "X", "type": "text"}`,
+			`{"key": "x", "name": "X", "type": "color"}`,
+			`{"key": "x", "name": "X", "type": "select"}`,
+			`{"key": "x", "name": "X", "type": "text", "options": ["a"]}`,
+		} {
+			if w := serve(r, "POST", "/fields", body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+			}
+		}
+
+		var fields []models.CustomField
+		json.Unmarshal(serve(r, "GET", "/fields", "").Body.Bytes(), &fields)
+		if len(fields) != 6 || fields[0].Key != "customer" {
+			t.Errorf("Expected 6 fields ordered by key, got %+v", fields)
+		}
+	})
+
+	t.Run("Update Definition", func(t *testing.T) {
+		w := serve(r, "PATCH", "/fields/"+severity.ID, `{"name": "Impact", "options": ["Low", "Medium", "High", "Critical"]}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		json.Unmarshal(w.Body.Bytes(), &severity)
+		if severity.Name != "Impact" || len(severity.Options) != 4 || severity.Key != "severity" {
+			t.Errorf("Expected the renamed field with a new option, got %+v", severity)
+		}
+		if w := serve(r, "PATCH", "/fields/"+severity.ID, `{"options": ["Low", "High"]}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 removing options, got %d", w.Code)
+		}
+		if w := serve(r, "GET", "/fields/00000000-0000-4000-8000-000000000000", ""); w.Code != http.StatusNotFound || problemCode(w) != utils.CodeFieldNotFound {
+			t.Errorf("Expected 404 FIELD_NOT_FOUND, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	var first models.Issue
+	t.Run("Values", func(t *testing.T) {
+		w := serve(r, "POST", "/issues", fmt.Sprintf(`{"title": "Checkout fails", "status": "Todo", "priority": "High", "custom_fields": {
+			"severity": "Critical", "customer": "Acme", "estimate": 3, "due": "2026-11-02",
+			"platforms": ["web", "ios", "web"], "reviewer": %q
+		}}`, alice))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		json.Unmarshal(w.Body.Bytes(), &first)
+		cf := first.CustomFields
+		if cf["severity"] != "Critical" || cf["customer"] != "Acme" || cf["estimate"] != 3.0 || cf["due"] != "2026-11-02" || cf["reviewer"] != alice {
+			t.Errorf("Expected the values in the response, got %+v", cf)
+		}
+		if fmt.Sprint(cf["platforms"]) != "[ios web]" {
+			t.Errorf("Expected sorted, deduplicated options, got %v", cf["platforms"])
+		}
+
+		serve(r, "POST", "/issues", `{"title": "Typo", "status": "Todo", "priority": "Low", "custom_fields": {"severity": "Low", "estimate": 1}}`)
+		serve(r, "POST", "/issues", `{"title": "Slow search", "status": "Todo", "priority": "Medium", "custom_fields": {"severity": "High", "customer": "Globex"}}`)
+
+		for _, body := range []string{
+			`{"custom_fields": {"severity": "Huge"}}`,
+			`{"custom_fields": {"estimate": "3"}}`,
+			`{"custom_fields": {"due": "02/11/2026"}}`,
+			`{"custom_fields": {"platforms": ["web", "tv"]}}`,
+			`{"custom_fields": {"reviewer": "alice"}}`,
+			`{"custom_fields": {"unknown": "x"}}`,
+		} {
+			if w := serve(r, "PATCH", "/issues/"+first.ID, body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+			}
+		}
+		if w := serve(r, "PATCH", "/issues/"+first.ID, `{"custom_fields": {"reviewer": "00000000-0000-4000-8000-000000000000"}}`); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for an unknown user, got %d", w.Code)
+		}
+
+		w = serve(r, "PATCH", "/issues/"+first.ID, `{"custom_fields": {"customer": null, "estimate": 5}}`)
+		var updated models.Issue
+		json.Unmarshal(w.Body.Bytes(), &updated)
+		if _, ok := updated.CustomFields["customer"]; ok || updated.CustomFields["estimate"] != 5.0 || updated.CustomFields["severity"] != "Critical" {
+			t.Errorf("Expected customer cleared, estimate changed and the rest kept, got %+v", updated.CustomFields)
+		}
+	})
+
+	t.Run("Filter And Sort", func(t *testing.T) {
+		if got := titles(listIssues(t, r, "?cf.severity=High&cf.severity=Critical&sort=title")); fmt.Sprint(got) != "[Checkout fails Slow search]" {
+			t.Errorf("Expected High or Critical issues, got %v", got)
+		}
+		if got := titles(listIssues(t, r, "?cf.platforms=ios&cf.estimate=5.0")); fmt.Sprint(got) != "[Checkout fails]" {
+			t.Errorf("Expected the ios issue estimated at 5, got %v", got)
+		}
+		if got := titles(listIssues(t, r, "?sort=-cf.severity")); fmt.Sprint(got) != "[Checkout fails Slow search Typo]" {
+			t.Errorf("Expected issues by severity option order, got %v", got)
+		}
+		if got := titles(listIssues(t, r, "?sort=cf.estimate,title")); fmt.Sprint(got) != "[Slow search Typo Checkout fails]" {
+			t.Errorf("Expected issues without an estimate first, got %v", got)
+		}
+		if got := titles(listIssues(t, r, "?sort=cf.customer,title")); fmt.Sprint(got) != "[Checkout fails Typo Slow search]" {
+			t.Errorf("Expected issues by customer, got %v", got)
+		}
+
+		for _, query := range []string{"?cf.nope=x", "?cf.estimate=many", "?cf.severity=Huge", "?sort=cf.nope", "?sort=cf.platforms", "?sort=cf.reviewer"} {
+			if w := serve(r, "GET", "/issues"+query, ""); w.Code != http.StatusBadRequest || problemCode(w) != utils.CodeInvalidQuery {
+				t.Errorf("Expected 400 INVALID_QUERY for %s, got %d", query, w.Code)
+			}
+		}
+	})
+
+	t.Run("Filter Stats", func(t *testing.T) {
+		w := serve(r, "GET", "/stats?cf.severity=High&cf.severity=Critical&group_by=priority", "")
+		var stats models.IssueStats
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		if w.Code != http.StatusOK || stats.Total != 2 || len(stats.Groups["priority"]) != 2 {
+			t.Errorf("Expected the High and Critical issues counted, got %d %+v", w.Code, stats)
+		}
+
+		for _, path := range []string{"/stats", "/stats/created-vs-closed", "/stats/cycle-time", "/stats/throughput", "/reports/cumulative-flow"} {
+			if w := serve(r, "GET", path+"?cf.estimate=5", ""); w.Code != http.StatusOK {
+				t.Errorf("Expected status 200 for %s, got %d: %s", path, w.Code, w.Body.String())
+			}
+			if w := serve(r, "GET", path+"?cf.nope=x", ""); w.Code != http.StatusBadRequest || problemCode(w) != utils.CodeInvalidQuery {
+				t.Errorf("Expected 400 INVALID_QUERY for %s, got %d", path, w.Code)
+			}
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if w := serve(r, "DELETE", "/fields/"+severity.ID, ""); w.Code != http.StatusNoContent {
+			t.Fatalf("Expected status 204, got %d", w.Code)
+		}
+		var issue models.Issue
+		json.Unmarshal(serve(r, "GET", "/issues/"+first.ID, "").Body.Bytes(), &issue)
+		if _, ok := issue.CustomFields["severity"]; ok {
+			t.Errorf("Expected the values deleted with the field, got %+v", issue.CustomFields)
+		}
+		if w := serve(r, "DELETE", "/fields/"+severity.ID, ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 deleting twice, got %d", w.Code)
+		}
+	})
+}
@@ -1,8 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"github.com/abhir9/issue-board/api/internal/database"
 	"github.com/abhir9/issue-board/api/internal/models"
 	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -24,16 +26,27 @@ func WriteJSON(w http.ResponseWriter, data interface{}) error {
 }
 
 type Handler struct {
-	Repo *database.Repository
+	Repo database.Store
+	WIP  models.WIPLimits
+
+	// Mutations serves the issue requests a sync batch is applied as, with
+	// the permission checks of the equivalent routes. Nil serves them
+	// unchecked.
+	Mutations http.Handler
 }
 
-func NewHandler(repo *database.Repository) *Handler {
+func NewHandler(repo database.Store) *Handler {
 	return &Handler{Repo: repo}
 }
 
 // GetIssues godoc
 // @Summary Get all issues
-// @Description Get a list of issues, optionally filtered by status, assignee, priority, or labels
+// @Description Get a list of issues, optionally filtered by status, assignee, priority, or labels.
+// @Description Passing cursor or limit switches to keyset pagination and an {items, next_cursor, total_count} envelope;
+// @Description otherwise a bare array is returned and page/page_size select an offset page.
+// @Description X-Total-Count and RFC 8288 Link headers are set on paginated responses.
+// @Description The envelope also lists the issue count and WIP limits of each status column.
+// @Description Archived issues are left out unless include_archived is true.
 // @Tags issues
 // @Accept json
 // @Produce json
@@ -41,125 +54,180 @@ func NewHandler(repo *database.Repository) *Handler {
 // @Param assignee query string false "Filter by assignee ID"
 // @Param priority query string false "Filter by priority"
 // @Param labels query string false "Filter by label name (e.g., ?labels=bug)"
+// @Param include_archived query bool false "Also return archived issues"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several"
+// @Param fields query string false "Comma-separated issue attributes to return (id is always included)"
+// @Param expand query string false "Comma-separated related resources to embed: assignee, labels, comments_count, children (default assignee, labels)"
+// @Param sort query string false "Comma-separated sort keys, '-' prefix for descending (order_index, priority, status, assignee, title, created_at, updated_at, cf.<key>)"
+// @Param cursor query string false "Opaque cursor from a previous next_cursor"
+// @Param limit query int false "Keyset page size (default 50, max 200)"
+// @Param page query int false "Offset page number (legacy)"
+// @Param page_size query int false "Offset page size (legacy)"
+// @Success 200 {object} models.IssueList
 // @Success 200 {array} models.Issue
-// @Failure 500 {string} string "Internal Server Error"
+// @Header 200 {string} X-Column-Counts "JSON array of the per-column counts and limits, for the plain array response"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /issues [get]
 // @Security ApiKeyAuth
 func (h *Handler) GetIssues(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	status := r.URL.Query()["status"]
-	assignee := r.URL.Query().Get("assignee")
-	priority := r.URL.Query()["priority"]
-	labels := r.URL.Query()["labels"]
-
-	// Parse pagination parameters
-	page := 1
-	pageSize := 0 // 0 means no pagination
-	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
+
+	q, err := parseIssueQuery(r)
+	if err != nil {
+		writeQueryError(w, r, "", err)
+		return
 	}
-	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
-		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
-			pageSize = ps
-		}
+	if !h.parseFieldQuery(w, r, &q) {
+		return
+	}
+	if h.notModified(w, r) {
+		return
 	}
 
-	issues, err := h.Repo.GetIssues(ctx, status, assignee, priority, labels, page, pageSize)
+	list, err := h.Repo.ListIssues(ctx, q)
+	if errors.Is(err, models.ErrInvalidCursor) {
+		writeQueryError(w, r, "cursor", err)
+		return
+	}
 	if err != nil {
 		slog.Error("Failed to fetch issues", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch issues", map[string]interface{}{"error": "Internal server error"})
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issues", nil)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, issues)
+	if q.Keyset() || q.PageSize > 0 {
+		w.Header().Set("X-Total-Count", strconv.Itoa(list.TotalCount))
+		if links := paginationLinks(r, q, list); len(links) > 0 {
+			w.Header().Set("Link", strings.Join(links, ", "))
+		}
+	}
+
+	columns, err := h.columnCounts(ctx, q.IssueFilter)
+	if err != nil {
+		slog.Error("Failed to count issues per column", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issues", nil)
+		return
+	}
+
+	items := projectIssues(list.Items, q)
+	if q.Keyset() {
+		utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"items":       items,
+			"next_cursor": list.NextCursor,
+			"total_count": list.TotalCount,
+			"columns":     columns,
+		})
+		return
+	}
+	// The plain array has no room for the column counts
+	if err := setColumnCountsHeader(w, columns); err != nil {
+		slog.Error("Failed to encode column counts", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issues", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, items)
 }
 
 // CreateIssue godoc
 // @Summary Create a new issue
-// @Description Create a new issue with the provided details
+// @Description Create a new issue with the provided details.
+// @Description With template_id, the template's title prefix is added and its description, status, priority, type and labels fill in the fields left empty.
 // @Tags issues
 // @Accept json
 // @Produce json
 // @Param issue body models.CreateIssueRequest true "Issue content"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
 // @Success 201 {object} models.Issue
-// @Failure 400 {string} string "Bad Request"
-// @Failure 500 {string} string "Internal Server Error"
+// @Header 201 {string} X-WIP-Warning "WIP limits exceeded by the change"
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 422 {object} utils.Problem "Unknown assignee, labels, parent or template, or a sub-issue cycle"
+// @Failure 409 {object} utils.Problem "WIP limit exceeded (strict mode)"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /issues [post]
 // @Security ApiKeyAuth
 func (h *Handler) CreateIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var req models.CreateIssueRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
 		slog.Warn("Failed to decode create issue request", "error", err)
-		utils.WriteError(w, http.StatusBadRequest, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		utils.WriteDecodeError(w, r, err)
 		return
 	}
 
-	// Validate request
-	if err := validateCreateIssueRequest(&req); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, "Validation failed", map[string]interface{}{"errors": err.Error()})
+	// Fill in the fields left out from the template
+	if req.TemplateID != nil && !h.applyTemplate(w, r, &req) {
 		return
 	}
 
-	id := uuid.New().String()
-	now := time.Now()
+	if req.Type == "" {
+		req.Type = models.DefaultType
+	}
 
-	// Get minimum order_index for this status column to place new issue at the top
-	existingIssues, err := h.Repo.GetIssues(ctx, []string{req.Status}, "", nil, nil, 1, 0)
-	if err != nil {
-		slog.Error("Failed to fetch existing issues", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch existing issues", map[string]interface{}{"error": "Internal server error"})
+	// Validate request
+	if err := validateCreateIssueRequest(&req); err != nil {
+		writeValidationError(w, r, err)
 		return
 	}
-
-	// Calculate order_index: find min and subtract 1 to place at top
-	orderIndex := 0.0
-	if len(existingIssues) > 0 {
-		minIndex := existingIssues[0].OrderIndex
-		for _, issue := range existingIssues {
-			if issue.OrderIndex < minIndex {
-				minIndex = issue.OrderIndex
-			}
-		}
-		orderIndex = minIndex - 1
+	fieldValues, ok := h.parseFieldValues(w, r, req.CustomFields)
+	if !ok {
+		return
 	}
 
+	id := uuid.New().String()
+	now := time.Now()
 	issue := models.Issue{
 		ID:          id,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      req.Status,
 		Priority:    req.Priority,
+		Type:        req.Type,
 		AssigneeID:  req.AssigneeID,
+		ParentID:    req.ParentID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-		OrderIndex:  orderIndex,
-	}
 
-	if err := h.Repo.CreateIssue(ctx, issue); err != nil {
-		slog.Error("Failed to create issue", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to create issue", map[string]interface{}{"error": "Internal server error"})
+		CustomFields: fieldValues,
+	}
+	for _, labelID := range req.LabelIDs {
+		issue.Labels = append(issue.Labels, models.Label{ID: labelID})
+	}
+	if !h.checkTypeRules(w, r, issue, nil) {
 		return
 	}
 
-	if len(req.LabelIDs) > 0 {
-		if err := h.Repo.UpdateIssueLabels(ctx, id, req.LabelIDs); err != nil {
-			slog.Error("Failed to update labels", "error", err)
-			utils.WriteError(w, http.StatusInternalServerError, "Failed to update labels", map[string]interface{}{"error": "Internal server error"})
-			return
-		}
+	if !h.checkWIP(w, r, nil, req.Status, req.AssigneeID) {
+		return
 	}
 
-	createdIssue, err := h.Repo.GetIssue(ctx, id)
+	// Get the first issue of this status column to place the new issue at the top
+	existing, err := h.Repo.ListIssues(ctx, models.IssueQuery{
+		IssueFilter: models.IssueFilter{Status: []string{req.Status}},
+		Fields:      []string{"order_index"},
+		Expand:      &models.IssueExpand{},
+		Limit:       1,
+	})
 	if err != nil {
-		slog.Error("Failed to fetch created issue", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch created issue", map[string]interface{}{"error": "Internal server error"})
+		slog.Error("Failed to fetch existing issues", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch existing issues", nil)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusCreated, createdIssue)
+	// Calculate order_index: the list is ordered by order_index, so subtract 1 from the first
+	if len(existing.Items) > 0 {
+		issue.OrderIndex = existing.Items[0].OrderIndex - 1
+	}
+
+	// The issue and its labels are written in one transaction
+	if err := h.Repo.CreateIssue(ctx, issue); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to create issue")
+		return
+	}
+
+	h.writeIssue(w, r, id, http.StatusCreated)
 }
 
 // GetIssue godoc
@@ -170,21 +238,25 @@ func (h *Handler) CreateIssue(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Issue ID"
 // @Success 200 {object} models.Issue
-// @Failure 404 {string} string "Not Found"
-// @Failure 500 {string} string "Internal Server Error"
+// @Success 304 "Not Modified"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /issues/{id} [get]
 // @Security ApiKeyAuth
 func (h *Handler) GetIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
+	if h.notModified(w, r) {
+		return
+	}
 	issue, err := h.Repo.GetIssue(ctx, id)
 	if err != nil {
 		slog.Error("Failed to fetch issue", "issue_id", id, "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch issue", map[string]interface{}{"error": "Internal server error"})
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issue", nil)
 		return
 	}
 	if issue == nil {
-		utils.WriteError(w, http.StatusNotFound, "Issue not found", nil)
+		utils.WriteProblem(w, r, utils.CodeIssueNotFound, "", nil)
 		return
 	}
 
@@ -200,23 +272,32 @@ func (h *Handler) GetIssue(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Issue ID"
 // @Param issue body models.UpdateIssueRequest true "Issue updates"
 // @Success 200 {object} models.Issue
-// @Failure 400 {string} string "Bad Request"
-// @Failure 500 {string} string "Internal Server Error"
+// @Header 200 {string} X-WIP-Warning "WIP limits exceeded by the change"
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 422 {object} utils.Problem "Unknown assignee, labels or parent, or a sub-issue cycle"
+// @Failure 409 {object} utils.Problem "WIP limit exceeded (strict mode)"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /issues/{id} [patch]
 // @Security ApiKeyAuth
 func (h *Handler) UpdateIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 	var req models.UpdateIssueRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
 		slog.Warn("Failed to decode update issue request", "error", err)
-		utils.WriteError(w, http.StatusBadRequest, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		utils.WriteDecodeError(w, r, err)
 		return
 	}
 
 	// Validate request
 	if err := validateUpdateIssueRequest(&req); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, "Validation failed", map[string]interface{}{"errors": err.Error()})
+		writeValidationError(w, r, err)
+		return
+	}
+	fieldValues, ok := h.parseFieldValues(w, r, req.CustomFields)
+	if !ok {
 		return
 	}
 
@@ -233,33 +314,38 @@ func (h *Handler) UpdateIssue(w http.ResponseWriter, r *http.Request) {
 	if req.Priority != nil {
 		updates["priority"] = *req.Priority
 	}
+	if req.Type != nil {
+		updates["type"] = *req.Type
+	}
 	if req.AssigneeID != nil {
 		updates["assignee_id"] = *req.AssigneeID
 	}
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			updates["parent_id"] = nil
+		} else {
+			updates["parent_id"] = *req.ParentID
+		}
+	}
+	if fieldValues != nil {
+		updates["custom_fields"] = fieldValues
+	}
 	updates["updated_at"] = time.Now()
 
-	if err := h.Repo.UpdateIssue(ctx, id, updates); err != nil {
-		slog.Error("Failed to update issue", "issue_id", id, "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to update issue", map[string]interface{}{"error": "Internal server error"})
+	if !h.checkUpdateTypeRules(w, r, id, &req, fieldValues) {
 		return
 	}
 
-	if req.LabelIDs != nil {
-		if err := h.Repo.UpdateIssueLabels(ctx, id, req.LabelIDs); err != nil {
-			slog.Error("Failed to update labels", "issue_id", id, "error", err)
-			utils.WriteError(w, http.StatusInternalServerError, "Failed to update labels", map[string]interface{}{"error": "Internal server error"})
-			return
-		}
+	if (req.Status != nil || req.AssigneeID != nil) && !h.checkIssueWIP(w, r, id, req.Status, req.AssigneeID) {
+		return
 	}
 
-	updatedIssue, err := h.Repo.GetIssue(ctx, id)
-	if err != nil {
-		slog.Error("Failed to fetch updated issue", "issue_id", id, "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch updated issue", map[string]interface{}{"error": "Internal server error"})
+	if err := h.Repo.UpdateIssueWithLabels(ctx, id, updates, req.LabelIDs); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to update issue", "issue_id", id)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, updatedIssue)
+	h.writeIssue(w, r, id, http.StatusOK)
 }
 
 // MoveIssue godoc
@@ -270,18 +356,26 @@ func (h *Handler) UpdateIssue(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Issue ID"
 // @Param move body models.UpdateIssueRequest true "Move details (status and order_index)"
-// @Success 200 {string} string "OK"
-// @Failure 400 {string} string "Bad Request"
-// @Failure 500 {string} string "Internal Server Error"
+// @Success 200 {object} models.MoveIssueResponse
+// @Header 200 {string} X-WIP-Warning "WIP limits exceeded by the move"
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 409 {object} utils.Problem "WIP limit exceeded (strict mode)"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /issues/{id}/move [patch]
 // @Security ApiKeyAuth
 func (h *Handler) MoveIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 	var req models.UpdateIssueRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
 		slog.Warn("Failed to decode move issue request", "error", err)
-		utils.WriteError(w, http.StatusBadRequest, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+	if err := validateMoveIssueRequest(&req); err != nil {
+		writeValidationError(w, r, err)
 		return
 	}
 
@@ -296,30 +390,82 @@ func (h *Handler) MoveIssue(w http.ResponseWriter, r *http.Request) {
 		updates["order_index"] = *req.OrderIndex
 	}
 
+	if req.Status != nil && !h.checkUpdateTypeRules(w, r, id, &models.UpdateIssueRequest{Status: req.Status}, nil) {
+		return
+	}
+
+	if req.Status != nil && !h.checkIssueWIP(w, r, id, req.Status, nil) {
+		return
+	}
+
 	if err := h.Repo.UpdateIssue(ctx, id, updates); err != nil {
-		slog.Error("Failed to update issue", "issue_id", id, "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to update issue", map[string]interface{}{"error": "Internal server error"})
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to move issue", "issue_id", id)
+		return
+	}
+
+	moved, err := h.movedIssue(ctx, id)
+	if err != nil {
+		slog.Error("Failed to fetch moved issue", "issue_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch moved issue", nil)
+		return
+	}
+	if moved == nil {
+		// Deleted between the move and the read
+		utils.WriteProblem(w, r, utils.CodeIssueNotFound, "", nil)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	utils.WriteJSON(w, http.StatusOK, moved)
+}
+
+// movedIssue returns an issue with its neighbours in its column's board
+// order, or nil if it does not exist
+func (h *Handler) movedIssue(ctx context.Context, id string) (*models.MoveIssueResponse, error) {
+	issue, err := h.Repo.GetIssue(ctx, id)
+	if err != nil || issue == nil {
+		return nil, err
+	}
+
+	resp := &models.MoveIssueResponse{Issue: *issue}
+	for _, n := range []struct {
+		sort []models.SortKey
+		dst  **models.Issue
+	}{
+		{[]models.SortKey{{Field: models.SortOrderIndex, Desc: true}}, &resp.Previous},
+		{[]models.SortKey{{Field: models.SortOrderIndex}}, &resp.Next},
+	} {
+		// The first issue past the moved one, looking up or down the column
+		list, err := h.Repo.ListIssues(ctx, models.IssueQuery{
+			IssueFilter: models.IssueFilter{Status: []string{issue.Status}},
+			Sort:        n.sort,
+			Cursor:      &models.Cursor{Values: []interface{}{issue.OrderIndex}, Sort: models.FormatSort(n.sort), ID: issue.ID},
+			Limit:       1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(list.Items) > 0 {
+			*n.dst = &list.Items[0]
+		}
+	}
+	return resp, nil
 }
 
 // DeleteIssue godoc
 // @Summary Delete an issue
-// @Description Delete an issue by ID
+// @Description Move an issue to the trash. It can be restored until it is purged after the trash retention period.
 // @Tags issues
 // @Param id path string true "Issue ID"
 // @Success 204 {object} nil
-// @Failure 500 {string} string "Internal Server Error"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /issues/{id} [delete]
 // @Security ApiKeyAuth
 func (h *Handler) DeleteIssue(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 	if err := h.Repo.DeleteIssue(ctx, id); err != nil {
-		slog.Error("Failed to delete issue", "issue_id", id, "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to delete issue", map[string]interface{}{"error": "Internal server error"})
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to delete issue", "issue_id", id)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -332,15 +478,19 @@ func (h *Handler) DeleteIssue(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.User
-// @Failure 500 {string} string "Internal Server Error"
+// @Success 304 "Not Modified"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /users [get]
 // @Security ApiKeyAuth
 func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	if h.notModified(w, r) {
+		return
+	}
 	users, err := h.Repo.GetUsers(ctx)
 	if err != nil {
 		slog.Error("Failed to fetch users", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch users", map[string]interface{}{"error": "Internal server error"})
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch users", nil)
 		return
 	}
 	utils.WriteJSON(w, http.StatusOK, users)
@@ -353,15 +503,19 @@ func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.Label
-// @Failure 500 {string} string "Internal Server Error"
+// @Success 304 "Not Modified"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
 // @Router /labels [get]
 // @Security ApiKeyAuth
 func (h *Handler) GetLabels(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	if h.notModified(w, r) {
+		return
+	}
 	labels, err := h.Repo.GetLabels(ctx)
 	if err != nil {
 		slog.Error("Failed to fetch labels", "error", err)
-		utils.WriteError(w, http.StatusInternalServerError, "Failed to fetch labels", map[string]interface{}{"error": "Internal server error"})
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch labels", nil)
 		return
 	}
 	utils.WriteJSON(w, http.StatusOK, labels)
@@ -369,90 +523,64 @@ func (h *Handler) GetLabels(w http.ResponseWriter, r *http.Request) {
 
 // validateCreateIssueRequest validates a create issue request
 func validateCreateIssueRequest(req *models.CreateIssueRequest) error {
-	var errors []string
+	v := validator.New()
 
-	if req.Title == "" {
-		errors = append(errors, "title is required")
-	} else if len(req.Title) > 200 {
-		errors = append(errors, "title must not exceed 200 characters")
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, 200)
+	v.MaxLength("description", req.Description, 5000)
+	v.OneOf("status", req.Status, models.ValidStatuses)
+	v.OneOf("priority", req.Priority, models.ValidPriorities)
+	v.OneOf("type", req.Type, models.ValidTypes)
+	if req.ParentID != nil {
+		v.UUID("parent_id", *req.ParentID)
 	}
 
-	if len(req.Description) > 5000 {
-		errors = append(errors, "description must not exceed 5000 characters")
-	}
-
-	validStatus := false
-	for _, s := range models.ValidStatuses {
-		if req.Status == s {
-			validStatus = true
-			break
-		}
-	}
-	if !validStatus {
-		errors = append(errors, fmt.Sprintf("status must be one of: %v", models.ValidStatuses))
-	}
-
-	validPriority := false
-	for _, p := range models.ValidPriorities {
-		if req.Priority == p {
-			validPriority = true
-			break
-		}
-	}
-	if !validPriority {
-		errors = append(errors, fmt.Sprintf("priority must be one of: %v", models.ValidPriorities))
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("%s", strings.Join(errors, "; "))
-	}
-	return nil
+	return validateReferences(v, req.AssigneeID, req.LabelIDs)
 }
 
 // validateUpdateIssueRequest validates an update issue request
 func validateUpdateIssueRequest(req *models.UpdateIssueRequest) error {
-	var errors []string
+	v := validator.New()
 
 	if req.Title != nil {
-		if *req.Title == "" {
-			errors = append(errors, "title cannot be empty")
-		} else if len(*req.Title) > 200 {
-			errors = append(errors, "title must not exceed 200 characters")
-		}
+		v.Required("title", *req.Title)
+		v.MaxLength("title", *req.Title, 200)
 	}
-
-	if req.Description != nil && len(*req.Description) > 5000 {
-		errors = append(errors, "description must not exceed 5000 characters")
+	if req.Description != nil {
+		v.MaxLength("description", *req.Description, 5000)
 	}
-
 	if req.Status != nil {
-		validStatus := false
-		for _, s := range models.ValidStatuses {
-			if *req.Status == s {
-				validStatus = true
-				break
-			}
-		}
-		if !validStatus {
-			errors = append(errors, fmt.Sprintf("status must be one of: %v", models.ValidStatuses))
-		}
+		v.OneOf("status", *req.Status, models.ValidStatuses)
 	}
-
 	if req.Priority != nil {
-		validPriority := false
-		for _, p := range models.ValidPriorities {
-			if *req.Priority == p {
-				validPriority = true
-				break
-			}
-		}
-		if !validPriority {
-			errors = append(errors, fmt.Sprintf("priority must be one of: %v", models.ValidPriorities))
-		}
+		v.OneOf("priority", *req.Priority, models.ValidPriorities)
+	}
+	if req.Type != nil {
+		v.OneOf("type", *req.Type, models.ValidTypes)
 	}
+	if req.ParentID != nil && *req.ParentID != "" {
+		v.UUID("parent_id", *req.ParentID)
+	}
+
+	return validateReferences(v, req.AssigneeID, req.LabelIDs)
+}
+
+// validateMoveIssueRequest validates a move issue request
+func validateMoveIssueRequest(req *models.UpdateIssueRequest) error {
+	v := validator.New()
+	if req.Status != nil {
+		v.OneOf("status", *req.Status, models.ValidStatuses)
+	}
+	return v.Err()
+}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("%s", strings.Join(errors, "; "))
+// validateReferences checks the format of an issue's assignee and label IDs,
+// then returns all errors collected by v. Whether they exist is checked
+// by the store, which fails the write with a *database.ReferenceError.
+func validateReferences(v *validator.Validator, assigneeID *string, labelIDs []string) error {
+	if assigneeID != nil {
+		v.UUID("assignee_id", *assigneeID)
 	}
-	return nil
+	v.UUIDs("label_ids", labelIDs)
+	return v.Err()
 }
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/abhir9/issue-board/api/internal/database"
 	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
 )
 
 func TestCreateIssue(t *testing.T) {
@@ -43,9 +46,9 @@ func TestCreateIssue(t *testing.T) {
 
 	t.Run("Success with all fields", func(t *testing.T) {
 		// Create a user first to satisfy foreign key constraint
-		repo.DB.Exec("INSERT INTO users (id, name) VALUES ('user1', 'Test User')")
+		repo.DB.Exec("INSERT INTO users (id, name) VALUES ('6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a', 'Test User')")
 		
-		assigneeID := "user1"
+		assigneeID := "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a"
 		payload := map[string]interface{}{
 			"title":       "Full Issue",
 			"description": "Complete Description",
@@ -74,6 +77,33 @@ func TestCreateIssue(t *testing.T) {
 		}
 	})
 
+	t.Run("Invalid Input - Unknown Field", func(t *testing.T) {
+		body := `{"title": "Test Issue", "status": "Todo", "priority": "High", "asignee_id": "user1"}`
+		req, _ := http.NewRequest("POST", "/issues", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", w.Code)
+		}
+		var problem utils.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		if problem.Extensions["field"] != "asignee_id" || problem.Extensions["offset"] != float64(strings.Index(body, `"asignee_id"`)+len(`"asignee_id"`)) {
+			t.Errorf("Expected field and offset of the unknown field, got %v", problem.Extensions)
+		}
+	})
+
+	t.Run("Invalid Input - Trailing Data", func(t *testing.T) {
+		body := `{"title": "Test Issue", "status": "Todo", "priority": "High"}{"title": "Again"}`
+		req, _ := http.NewRequest("POST", "/issues", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
 	t.Run("Validation - Empty Title", func(t *testing.T) {
 		payload := map[string]interface{}{
 			"title":       "",
@@ -297,6 +327,172 @@ func TestGetIssues(t *testing.T) {
 			t.Errorf("Expected 0 issues, got %d", len(issues))
 		}
 	})
+
+	t.Run("Pagination - Total Count And Links", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?page=2&page_size=2", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get("X-Total-Count"); got != "3" {
+			t.Errorf("Expected X-Total-Count 3, got '%s'", got)
+		}
+		links := strings.Split(w.Header().Get("Link"), ", ")
+		want := []string{
+			`</issues?page=1&page_size=2>; rel="first"`,
+			`</issues?page=1&page_size=2>; rel="prev"`,
+			`</issues?page=2&page_size=2>; rel="last"`,
+		}
+		if len(links) != len(want) {
+			t.Fatalf("Expected links %v, got %v", want, links)
+		}
+		for i := range want {
+			if links[i] != want[i] {
+				t.Errorf("Expected link '%s', got '%s'", want[i], links[i])
+			}
+		}
+	})
+
+	t.Run("Cursor Pagination", func(t *testing.T) {
+		var seen []string
+		next := "/issues?limit=2"
+		for pages := 0; next != ""; pages++ {
+			if pages > 2 {
+				t.Fatal("Cursor pagination did not terminate")
+			}
+			req, _ := http.NewRequest("GET", next, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+			}
+			var list models.IssueList
+			if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+				t.Fatalf("Expected an envelope: %v", err)
+			}
+			if list.TotalCount != 3 {
+				t.Errorf("Expected total_count 3, got %d", list.TotalCount)
+			}
+			for _, issue := range list.Items {
+				seen = append(seen, issue.ID)
+			}
+
+			next = ""
+			if list.NextCursor != nil {
+				next = "/issues?cursor=" + *list.NextCursor + "&limit=2"
+				want := `</issues?cursor=` + *list.NextCursor + `&limit=2>; rel="next"`
+				found := false
+				for _, l := range strings.Split(w.Header().Get("Link"), ", ") {
+					found = found || l == want
+				}
+				if !found {
+					t.Errorf("Expected Link '%s', got '%s'", want, w.Header().Get("Link"))
+				}
+			}
+		}
+		if len(seen) != 3 || seen[0] != "1" || seen[1] != "2" || seen[2] != "3" {
+			t.Errorf("Expected issues [1 2 3], got %v", seen)
+		}
+	})
+
+	t.Run("Cursor Pagination - Invalid Cursor", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?cursor=not-a-cursor", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?sort=-priority,created_at", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var issues []models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issues)
+		if len(issues) != 3 || issues[0].Priority != "High" || issues[1].Priority != "Medium" || issues[2].Priority != "Low" {
+			t.Errorf("Expected issues sorted High, Medium, Low, got %+v", issues)
+		}
+	})
+
+	t.Run("Sort - Unknown Field", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?sort=due_date", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "allowed: order_index") {
+			t.Errorf("Expected allowed sort fields in error, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Sparse Fieldset", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?fields=title,status&expand=", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var issues []map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &issues)
+		if len(issues) != 3 {
+			t.Fatalf("Expected 3 issues, got %d", len(issues))
+		}
+		for _, issue := range issues {
+			if len(issue) != 3 || issue["id"] == nil || issue["title"] == nil || issue["status"] == nil {
+				t.Errorf("Expected only id, title and status, got %v", issue)
+			}
+		}
+	})
+
+	t.Run("Expand Assignee", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?assignee=user1&fields=id&expand=assignee", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var issues []map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &issues)
+		if len(issues) != 1 {
+			t.Fatalf("Expected 1 issue, got %d", len(issues))
+		}
+		assignee, ok := issues[0]["assignee"].(map[string]interface{})
+		if !ok || assignee["name"] != "Test User" {
+			t.Errorf("Expected expanded assignee, got %v", issues[0])
+		}
+		if _, ok := issues[0]["labels"]; ok {
+			t.Errorf("Expected labels to be omitted, got %v", issues[0])
+		}
+	})
+
+	t.Run("Unsupported Field And Expand", func(t *testing.T) {
+		for _, query := range []string{"fields=secret", "expand=watchers"} {
+			req, _ := http.NewRequest("GET", "/issues?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+		}
+	})
+
+	t.Run("Cursor Pagination - Invalid Limit", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/issues?limit=1000", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
 }
 
 func TestUpdateIssue(t *testing.T) {
@@ -418,9 +614,9 @@ func TestUpdateIssue(t *testing.T) {
 
 	t.Run("Update with Assignee", func(t *testing.T) {
 		// Create user first to satisfy foreign key constraint
-		repo.DB.Exec("INSERT INTO users (id, name) VALUES ('user1', 'Test User')")
+		repo.DB.Exec("INSERT INTO users (id, name) VALUES ('6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a', 'Test User')")
 		
-		assigneeID := "user1"
+		assigneeID := "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a"
 		payload := map[string]interface{}{
 			"assignee_id": assigneeID,
 		}
@@ -495,8 +691,8 @@ func TestUpdateIssue(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		if w.Code != http.StatusInternalServerError {
-			t.Errorf("Expected status 500 for non-existing issue, got %d", w.Code)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for non-existing issue, got %d", w.Code)
 		}
 	})
 }
@@ -537,8 +733,8 @@ func TestDeleteIssue(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		if w.Code != http.StatusInternalServerError {
-			t.Errorf("Expected status 500 for non-existing issue, got %d", w.Code)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for non-existing issue, got %d", w.Code)
 		}
 	})
 
@@ -649,6 +845,47 @@ func TestMoveIssue(t *testing.T) {
 		}
 	})
 
+	t.Run("Returns Neighbours", func(t *testing.T) {
+		repo := setupTestDB(t)
+		r := setupRouter(repo)
+
+		ctx := context.Background()
+		for _, issue := range []models.Issue{
+			{ID: "1", Title: "Move Me", Status: "Todo", Priority: "Low", OrderIndex: 0},
+			{ID: "2", Title: "Above", Status: "Done", Priority: "Low", OrderIndex: 1},
+			{ID: "3", Title: "Below", Status: "Done", Priority: "Low", OrderIndex: 3},
+			{ID: "4", Title: "Further Below", Status: "Done", Priority: "Low", OrderIndex: 4},
+		} {
+			repo.CreateIssue(ctx, issue)
+		}
+
+		req, _ := http.NewRequest("PATCH", "/issues/1/move", bytes.NewBufferString(`{"status": "Done", "order_index": 2}`))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var resp models.MoveIssueResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if resp.Issue.ID != "1" || resp.Issue.Status != "Done" || resp.Issue.OrderIndex != 2 {
+			t.Errorf("Expected the moved issue, got %+v", resp.Issue)
+		}
+		if resp.Previous == nil || resp.Previous.ID != "2" || resp.Next == nil || resp.Next.ID != "3" {
+			t.Errorf("Expected neighbours 2 and 3, got %+v and %+v", resp.Previous, resp.Next)
+		}
+
+		// At the top of a column there is no previous issue
+		req, _ = http.NewRequest("PATCH", "/issues/1/move", bytes.NewBufferString(`{"order_index": 0}`))
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		resp = models.MoveIssueResponse{}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if resp.Previous != nil || resp.Next == nil || resp.Next.ID != "2" {
+			t.Errorf("Expected no previous issue and 2 next, got %+v and %+v", resp.Previous, resp.Next)
+		}
+	})
+
 	t.Run("Malformed JSON", func(t *testing.T) {
 		repo := setupTestDB(t)
 		r := setupRouter(repo)
@@ -691,10 +928,8 @@ func TestMoveIssue(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		// Note: MoveIssue currently doesn't validate status, so this might succeed
-		// This test documents the current behavior
-		if w.Code != http.StatusOK {
-			t.Errorf("Expected status 200 (no validation), got %d", w.Code)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
 		}
 	})
 
@@ -738,8 +973,8 @@ func TestMoveIssue(t *testing.T) {
 		w := httptest.NewRecorder()
 		r.ServeHTTP(w, req)
 
-		if w.Code != http.StatusInternalServerError {
-			t.Errorf("Expected status 500 for non-existing issue, got %d", w.Code)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for non-existing issue, got %d", w.Code)
 		}
 	})
 }
@@ -1098,3 +1333,54 @@ func TestGetLabels(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlersWithMemoryStore(t *testing.T) {
+	store := database.NewMemoryStore()
+	r := setupRouter(store)
+	ctx := context.Background()
+
+	store.CreateUser(ctx, models.User{ID: "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a", Name: "Alice"})
+	store.CreateLabel(ctx, models.Label{ID: "9b2d4e61-7c3a-4f85-b1e0-5a6c7d8e9f01", Name: "Bug", Color: "#FF0000"})
+
+	payload := map[string]interface{}{
+		"title":       "Memory Issue",
+		"status":      "Todo",
+		"priority":    "High",
+		"assignee_id": "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a",
+		"label_ids":   []string{"9b2d4e61-7c3a-4f85-b1e0-5a6c7d8e9f01"},
+	}
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/issues", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var created models.Issue
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Assignee == nil || created.Assignee.Name != "Alice" {
+		t.Errorf("Expected assignee Alice, got %+v", created.Assignee)
+	}
+	if len(created.Labels) != 1 || created.Labels[0].Name != "Bug" {
+		t.Errorf("Expected label Bug, got %+v", created.Labels)
+	}
+
+	req, _ = http.NewRequest("GET", "/issues?labels=Bug&status=Todo", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var issues []models.Issue
+	json.Unmarshal(w.Body.Bytes(), &issues)
+	if len(issues) != 1 || issues[0].ID != created.ID {
+		t.Errorf("Expected the created issue to be listed, got %+v", issues)
+	}
+
+	req, _ = http.NewRequest("DELETE", "/issues/"+created.ID, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/validator"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// parseIssueFilter reads the issue list filters shared by list and report endpoints
+func parseIssueFilter(r *http.Request) models.IssueFilter {
+	query := r.URL.Query()
+	return models.IssueFilter{
+		Status:     query["status"],
+		AssigneeID: query.Get("assignee"),
+		Priority:   query["priority"],
+		Types:      query["type"],
+		Labels:     query["labels"],
+	}
+}
+
+// parseIssueQuery reads filters, fieldsets, sort and pagination parameters for GetIssues.
+// Legacy page/page_size values that don't parse are ignored as before, while
+// the newer cursor/limit parameters are validated strictly.
+func parseIssueQuery(r *http.Request) (models.IssueQuery, error) {
+	query := r.URL.Query()
+	q := models.IssueQuery{
+		IssueFilter: parseIssueFilter(r),
+		Page:        1,
+	}
+
+	sort, err := models.ParseSort(query.Get("sort"))
+	if err != nil {
+		return q, validator.Field("sort", validator.CodeInvalid, err.Error())
+	}
+	q.Sort = sort
+
+	if _, ok := query["fields"]; ok {
+		fields, err := models.ParseFields(query.Get("fields"))
+		if err != nil {
+			return q, validator.Field("fields", validator.CodeInvalid, err.Error())
+		}
+		q.Fields = fields
+	}
+	if _, ok := query["expand"]; ok {
+		expand, err := models.ParseExpand(query.Get("expand"))
+		if err != nil {
+			return q, validator.Field("expand", validator.CodeInvalid, err.Error())
+		}
+		q.Expand = &expand
+	}
+
+	// Parse pagination parameters
+	if pageStr := query.Get("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			q.Page = p
+		}
+	}
+	if pageSizeStr := query.Get("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			q.PageSize = ps
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		c, err := models.DecodeCursor(cursor)
+		if err != nil {
+			return q, validator.Field("cursor", validator.CodeInvalid, err.Error())
+		}
+		q.Cursor = c
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return q, validator.Field("limit", validator.CodeInvalid, fmt.Sprintf("must be an integer between 1 and %d", maxPageLimit))
+		}
+		q.Limit = limit
+	} else if q.Cursor != nil {
+		q.Limit = defaultPageLimit
+	}
+
+	if v := query.Get("include_archived"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, validator.Field("include_archived", validator.CodeInvalid, "must be true or false")
+		}
+		q.IncludeArchived = include
+	}
+
+	return q, nil
+}
+
+// paginationLinks builds RFC 8288 Link header values for a list response
+func paginationLinks(r *http.Request, q models.IssueQuery, list *models.IssueList) []string {
+	var links []string
+
+	if q.Keyset() {
+		if q.Cursor != nil {
+			links = append(links, link(r, "first", map[string]string{"cursor": "", "limit": strconv.Itoa(q.Limit)}))
+		}
+		if list.NextCursor != nil {
+			links = append(links, link(r, "next", map[string]string{"cursor": *list.NextCursor, "limit": strconv.Itoa(q.Limit)}))
+		}
+		return links
+	}
+
+	if q.PageSize <= 0 {
+		return nil
+	}
+
+	lastPage := (list.TotalCount + q.PageSize - 1) / q.PageSize
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	page := func(n int) map[string]string {
+		return map[string]string{"page": strconv.Itoa(n), "page_size": strconv.Itoa(q.PageSize)}
+	}
+
+	links = append(links, link(r, "first", page(1)))
+	if q.Page > 1 {
+		prev := q.Page - 1
+		if prev > lastPage {
+			prev = lastPage
+		}
+		links = append(links, link(r, "prev", page(prev)))
+	}
+	if q.Page < lastPage {
+		links = append(links, link(r, "next", page(q.Page+1)))
+	}
+	links = append(links, link(r, "last", page(lastPage)))
+	return links
+}
+
+// link returns a Link header value pointing at the current request with the
+// given query parameters replaced; an empty value removes the parameter
+func link(r *http.Request, rel string, params map[string]string) string {
+	query := r.URL.Query()
+	for k, v := range params {
+		if v == "" {
+			query.Del(k)
+		} else {
+			query.Set(k, v)
+		}
+	}
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf("<%s>; rel=\"%s\"", u.String(), rel)
+}
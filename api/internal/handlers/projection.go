@@ -0,0 +1,63 @@
+package handlers
+
+import "github.com/abhir9/issue-board/api/internal/models"
+
+// projectIssues applies the query's sparse fieldset and expansions to a list
+// of issues. Queries without fields or expand keep the full issue shape.
+func projectIssues(issues []models.Issue, q models.IssueQuery) interface{} {
+	if q.Fields == nil && q.Expand == nil {
+		return issues
+	}
+
+	out := make([]map[string]interface{}, len(issues))
+	for i, issue := range issues {
+		out[i] = projectIssue(issue, q)
+	}
+	return out
+}
+
+// projectIssue returns the requested attributes of an issue plus its expanded
+// resources; the id is always included
+func projectIssue(issue models.Issue, q models.IssueQuery) map[string]interface{} {
+	customFields := issue.CustomFields
+	if customFields == nil {
+		customFields = models.FieldValues{}
+	}
+	attrs := map[string]interface{}{
+		"id":          issue.ID,
+		"title":       issue.Title,
+		"description": issue.Description,
+		"status":      issue.Status,
+		"priority":    issue.Priority,
+		"type":        issue.Type,
+		"assignee_id": issue.AssigneeID,
+		"parent_id":   issue.ParentID,
+		"created_at":  issue.CreatedAt,
+		"updated_at":  issue.UpdatedAt,
+		"order_index": issue.OrderIndex,
+
+		"custom_fields": customFields,
+	}
+
+	view := map[string]interface{}{"id": issue.ID}
+	for field, value := range attrs {
+		if q.WantsField(field) {
+			view[field] = value
+		}
+	}
+
+	expand := q.Expansions()
+	if expand.Assignee {
+		view["assignee"] = issue.Assignee
+	}
+	if expand.Labels {
+		view["labels"] = issue.Labels
+	}
+	if expand.CommentsCount {
+		view["comments_count"] = issue.CommentsCount
+	}
+	if expand.Children {
+		view["children"] = issue.Children
+	}
+	return view
+}
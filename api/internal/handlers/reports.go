@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/abhir9/issue-board/api/internal/analytics"
+	_ "github.com/abhir9/issue-board/api/internal/models" // response types named in the swag annotations
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// GetCumulativeFlow godoc
+// @Summary Get cumulative flow diagram data
+// @Description Number of issues in each status at the end of each day or week, reconstructed from status history.
+// @Description Issues that predate history tracking are placed using their created_at and updated_at.
+// @Description Accepts the same filters as the issue list.
+// @Tags reports
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago"
+// @Param to query string false "End date, inclusive for YYYY-MM-DD (default now)"
+// @Param interval query string false "Bucket size: day (default) or week"
+// @Param status query string false "Filter by status"
+// @Param assignee query string false "Filter by assignee ID"
+// @Param priority query string false "Filter by priority"
+// @Param labels query string false "Filter by label name"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several"
+// @Success 200 {object} models.CumulativeFlow
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /reports/cumulative-flow [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetCumulativeFlow(w http.ResponseWriter, r *http.Request) {
+	from, to, interval, err := parseSeriesRange(r)
+	if err != nil {
+		writeQueryError(w, r, "", err)
+		return
+	}
+	f, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	issues, history, err := h.issueHistory(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to compute cumulative flow", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to compute report", nil)
+		return
+	}
+
+	utils.WriteJSONCached(w, r, http.StatusOK, analytics.CumulativeFlow(issues, history, from, to, interval))
+}
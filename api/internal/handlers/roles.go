@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetUserRole godoc
+// @Summary Assign a role to a user
+// @Description Set a user's global role. Requires the admin role.
+// @Description project_id is rejected until the board has projects, since no route would consult the role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role body models.SetRoleRequest true "Role assignment"
+// @Success 200 {object} models.RoleAssignment
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 403 {object} utils.Problem "Forbidden"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /users/{id}/role [put]
+// @Security ApiKeyAuth
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	var req models.SetRoleRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode set role request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+	v := validator.New()
+	if _, err := models.ParseRole(string(req.Role)); err != nil {
+		v.Add("role", validator.CodeOneOf, err.Error())
+	}
+	// No route is project-scoped yet, so a project role would never apply
+	if req.ProjectID != "" {
+		v.Add("project_id", validator.CodeInvalid, "project roles are not supported yet")
+	}
+	if err := v.Err(); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	user, err := h.Repo.GetUser(ctx, id)
+	if err != nil {
+		slog.Error("Failed to fetch user", "user_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch user", nil)
+		return
+	}
+	if user == nil {
+		utils.WriteProblem(w, r, utils.CodeUserNotFound, "", nil)
+		return
+	}
+
+	assignment := models.RoleAssignment{UserID: id, ProjectID: req.ProjectID, Role: req.Role}
+	if err := h.Repo.SetUserRole(ctx, assignment); err != nil {
+		writeStoreError(w, r, err, utils.CodeUserNotFound, "Failed to set user role", "user_id", id)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, assignment)
+}
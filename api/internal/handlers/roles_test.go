@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestSetUserRole(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES ('user1', 'Test User')")
+
+	put := func(url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Assign Role", func(t *testing.T) {
+		w := put("/users/user1/role", `{"role": "maintainer"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var assignment models.RoleAssignment
+		json.Unmarshal(w.Body.Bytes(), &assignment)
+		if assignment.UserID != "user1" || assignment.ProjectID != "" || assignment.Role != models.RoleMaintainer {
+			t.Errorf("Unexpected assignment: %+v", assignment)
+		}
+
+		role, _ := repo.GetUserRole(context.Background(), "user1", "")
+		if role != models.RoleMaintainer {
+			t.Errorf("Expected stored role maintainer, got %q", role)
+		}
+	})
+
+	t.Run("Project Roles Are Rejected", func(t *testing.T) {
+		if w := put("/users/user1/role", `{"role": "viewer", "project_id": "web"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+		if role, _ := repo.GetUserRole(context.Background(), "user1", "web"); role != models.RoleMaintainer {
+			t.Errorf("Expected the global role to stay maintainer, got %q", role)
+		}
+	})
+
+	t.Run("Invalid Role", func(t *testing.T) {
+		if w := put("/users/user1/role", `{"role": "owner"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unknown User", func(t *testing.T) {
+		if w := put("/users/nobody/role", `{"role": "viewer"}`); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
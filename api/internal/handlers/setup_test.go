@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
 	"github.com/go-chi/chi/v5"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -28,66 +35,114 @@ func setupTestDB(t *testing.T) *database.Repository {
 		t.Fatalf("Failed to enable foreign keys: %v", err)
 	}
 
-	// Create tables manually or read migration files.
-	// For testing, it's often easier to just execute the schema directly if it's small,
-	// or ensure the migration path is correct.
-	// Here we'll execute the schema directly to avoid path issues during test execution.
-	schema := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		avatar_url TEXT
-	);
-
-	CREATE TABLE labels (
-		id TEXT PRIMARY KEY,
-		name TEXT NOT NULL,
-		color TEXT NOT NULL
-	);
-
-	CREATE TABLE issues (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		description TEXT,
-		status TEXT NOT NULL,
-		priority TEXT NOT NULL,
-		assignee_id TEXT,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		order_index REAL NOT NULL DEFAULT 0,
-		FOREIGN KEY (assignee_id) REFERENCES users(id)
-	);
-
-	CREATE TABLE issue_labels (
-		issue_id TEXT NOT NULL,
-		label_id TEXT NOT NULL,
-		PRIMARY KEY (issue_id, label_id),
-		FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
-		FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
-	);
-	`
-	_, err = db.Exec(schema)
-	if err != nil {
-		t.Fatalf("Failed to create schema: %v", err)
+	if err := database.Migrate(db, filepath.Join("..", "..", "migrations", database.DriverSQLite)); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	return database.NewRepository(db)
 }
 
-func setupRouter(repo *database.Repository) *chi.Mux {
-	h := NewHandler(repo)
+func setupRouter(repo database.Store) *chi.Mux {
+	return setupHandlerRouter(NewHandler(repo))
+}
+
+// setupHandlerRouter registers the routes of a configured handler
+func setupHandlerRouter(h *Handler) *chi.Mux {
 	r := chi.NewRouter()
+	r.Get("/board/version", h.GetBoardVersion)
 	r.Get("/issues", h.GetIssues)
 	r.Post("/issues", h.CreateIssue)
 	r.Get("/issues/{id}", h.GetIssue)
 	r.Patch("/issues/{id}", h.UpdateIssue)
 	r.Patch("/issues/{id}/move", h.MoveIssue)
 	r.Delete("/issues/{id}", h.DeleteIssue)
+	r.Post("/issues/{id}/archive", h.ArchiveIssue)
+	r.Post("/issues/{id}/unarchive", h.UnarchiveIssue)
+	r.Post("/issues/{id}/restore", h.RestoreIssue)
+	r.Delete("/issues/{id}/permanent", h.PurgeIssue)
+	r.Get("/issues/{id}/comments", h.GetComments)
+	r.Post("/issues/{id}/comments", h.CreateComment)
+	r.Get("/trash", h.GetTrash)
 	r.Get("/users", h.GetUsers)
+	r.Post("/users", h.CreateUser)
+	r.Put("/users/{id}/role", h.SetUserRole)
 	r.Get("/labels", h.GetLabels)
+	r.Get("/templates", h.GetTemplates)
+	r.Post("/templates", h.CreateTemplate)
+	r.Get("/templates/{id}", h.GetTemplate)
+	r.Put("/templates/{id}", h.UpdateTemplate)
+	r.Delete("/templates/{id}", h.DeleteTemplate)
+	r.Get("/fields", h.GetFields)
+	r.Post("/fields", h.CreateField)
+	r.Get("/fields/{id}", h.GetField)
+	r.Patch("/fields/{id}", h.UpdateField)
+	r.Delete("/fields/{id}", h.DeleteField)
+	r.Get("/issue-types", h.GetIssueTypes)
+	r.Get("/issue-types/{key}", h.GetIssueType)
+	r.Put("/issue-types/{key}", h.UpdateIssueType)
+	r.Get("/sync", h.GetSync)
+	r.Post("/sync", h.PostSync)
+	r.Get("/stats", h.GetStats)
+	r.Get("/stats/created-vs-closed", h.GetCreatedVsClosed)
+	r.Get("/stats/cycle-time", h.GetFlowTimes)
+	r.Get("/stats/throughput", h.GetThroughput)
+	r.Get("/reports/cumulative-flow", h.GetCumulativeFlow)
 	return r
 }
 
+// serve sends a request through r and returns the recorded response
+func serve(r http.Handler, method, url, body string) *httptest.ResponseRecorder {
+	return serveWithHeaders(r, method, url, body, nil)
+}
+
+// serveWithHeaders is serve with extra request headers
+func serveWithHeaders(r http.Handler, method, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// issueJSON returns the body creating a low priority issue
+func issueJSON(title, status string) string {
+	return fmt.Sprintf(`{"title": %q, "status": %q, "priority": "Low"}`, title, status)
+}
+
+// createIssue creates an issue through r, failing the test if it isn't
+func createIssue(t *testing.T, r http.Handler, body string) models.Issue {
+	t.Helper()
+	w := serve(r, "POST", "/issues", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var issue models.Issue
+	json.Unmarshal(w.Body.Bytes(), &issue)
+	return issue
+}
+
+// listIssues lists issues through r with a query string, failing the test
+// if the request does
+func listIssues(t *testing.T, r http.Handler, query string) []models.Issue {
+	t.Helper()
+	w := serve(r, "GET", "/issues"+query, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for %s, got %d: %s", query, w.Code, w.Body.String())
+	}
+	var issues []models.Issue
+	json.Unmarshal(w.Body.Bytes(), &issues)
+	return issues
+}
+
+// problemCode returns the code of a problem response
+func problemCode(w *httptest.ResponseRecorder) utils.Code {
+	var p utils.Problem
+	json.Unmarshal(w.Body.Bytes(), &p)
+	return p.Code
+}
+
 // Helper function to create string pointers
 func ptr(s string) *string {
 	return &s
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/analytics"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+)
+
+const (
+	defaultStatsRange = 30 * 24 * time.Hour
+	maxStatsBuckets   = 400
+)
+
+// GetStats godoc
+// @Summary Get issue counts
+// @Description Count issues grouped by status, priority, assignee and label, optionally with a 2-D pivot.
+// @Description Accepts the same filters as the issue list.
+// @Tags stats
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param assignee query string false "Filter by assignee ID"
+// @Param priority query string false "Filter by priority"
+// @Param labels query string false "Filter by label name"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several"
+// @Param group_by query string false "Comma-separated dimensions to group by (status, priority, assignee, label); default all"
+// @Param pivot query string false "Two dimensions to cross-tabulate, e.g. status,assignee"
+// @Success 200 {object} models.IssueStats
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /stats [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	f, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	groupBy := models.GroupByFields
+	if v := r.URL.Query().Get("group_by"); v != "" {
+		dims, err := parseDimensions(v)
+		if err != nil {
+			writeQueryError(w, r, "group_by", err)
+			return
+		}
+		groupBy = dims
+	}
+
+	var pivot []string
+	if v := r.URL.Query().Get("pivot"); v != "" {
+		dims, err := parseDimensions(v)
+		if err == nil && (len(dims) != 2 || dims[0] == dims[1]) {
+			err = fmt.Errorf("pivot needs two different dimensions, e.g. status,assignee")
+		}
+		if err != nil {
+			writeQueryError(w, r, "pivot", err)
+			return
+		}
+		pivot = dims
+	}
+
+	stats, err := h.issueStats(ctx, f, groupBy, pivot)
+	if err != nil {
+		slog.Error("Failed to compute stats", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to compute stats", nil)
+		return
+	}
+
+	utils.WriteJSONCached(w, r, http.StatusOK, stats)
+}
+
+// parseReportFilter reads the issue filters of stats and reports, custom
+// fields included. They count archived issues: archiving tidies the board, it
+// doesn't undo the work. It writes the error response and returns false for
+// an invalid custom field filter.
+func (h *Handler) parseReportFilter(w http.ResponseWriter, r *http.Request) (models.IssueFilter, bool) {
+	q := models.IssueQuery{IssueFilter: parseIssueFilter(r)}
+	q.IncludeArchived = true
+	if !h.parseFieldQuery(w, r, &q) {
+		return models.IssueFilter{}, false
+	}
+	return q.IssueFilter, true
+}
+
+func (h *Handler) issueStats(ctx context.Context, f models.IssueFilter, groupBy, pivot []string) (*models.IssueStats, error) {
+	totals, err := h.Repo.CountIssues(ctx, f, nil)
+	if err != nil {
+		return nil, err
+	}
+	stats := &models.IssueStats{Groups: make(map[string][]models.StatGroup)}
+	if len(totals) > 0 {
+		stats.Total = totals[0].Count
+	}
+
+	names, err := h.userNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dim := range groupBy {
+		counts, err := h.Repo.CountIssues(ctx, f, []string{dim})
+		if err != nil {
+			return nil, err
+		}
+		groups := make([]models.StatGroup, 0, len(counts))
+		for _, c := range counts {
+			g := models.StatGroup{Key: c.Keys[0], Count: c.Count}
+			if dim == models.GroupByAssignee && g.Key != nil {
+				g.Name = names[*g.Key]
+			}
+			groups = append(groups, g)
+		}
+		analytics.SortGroups(dim, groups)
+		stats.Groups[dim] = groups
+	}
+
+	if pivot != nil {
+		counts, err := h.Repo.CountIssues(ctx, f, pivot)
+		if err != nil {
+			return nil, err
+		}
+		p := &models.Pivot{Rows: pivot[0], Columns: pivot[1], Cells: make([]models.PivotCell, 0, len(counts))}
+		for _, c := range counts {
+			p.Cells = append(p.Cells, models.PivotCell{Row: c.Keys[0], Column: c.Keys[1], Count: c.Count})
+		}
+		sort.SliceStable(p.Cells, func(i, j int) bool {
+			a, b := p.Cells[i], p.Cells[j]
+			if analytics.GroupLess(p.Rows, a.Row, b.Row) {
+				return true
+			}
+			if analytics.GroupLess(p.Rows, b.Row, a.Row) {
+				return false
+			}
+			return analytics.GroupLess(p.Columns, a.Column, b.Column)
+		})
+		stats.Pivot = p
+	}
+
+	return stats, nil
+}
+
+// GetCreatedVsClosed godoc
+// @Summary Get created vs closed issues over time
+// @Description Count issues created and closed (moved to Done or Canceled) per day or week.
+// @Description Accepts the same filters as the issue list.
+// @Tags stats
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago"
+// @Param to query string false "End date, inclusive for YYYY-MM-DD (default now)"
+// @Param interval query string false "Bucket size: day (default) or week"
+// @Param status query string false "Filter by status"
+// @Param assignee query string false "Filter by assignee ID"
+// @Param priority query string false "Filter by priority"
+// @Param labels query string false "Filter by label name"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several"
+// @Success 200 {object} models.CreatedVsClosedReport
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /stats/created-vs-closed [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetCreatedVsClosed(w http.ResponseWriter, r *http.Request) {
+	from, to, interval, err := parseSeriesRange(r)
+	if err != nil {
+		writeQueryError(w, r, "", err)
+		return
+	}
+	f, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	issues, history, err := h.issueHistory(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to compute created vs closed", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to compute stats", nil)
+		return
+	}
+
+	utils.WriteJSONCached(w, r, http.StatusOK, models.CreatedVsClosedReport{
+		Interval: string(interval),
+		From:     from,
+		To:       to,
+		Buckets:  analytics.CreatedVsClosed(issues, history, from, to, interval),
+	})
+}
+
+// GetFlowTimes godoc
+// @Summary Get cycle and lead time percentiles
+// @Description Percentiles (in hours) of cycle time (first move to In Progress until Done) and
+// @Description lead time (created until Done) for issues completed in the range, from status history.
+// @Tags stats
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago"
+// @Param to query string false "End date, inclusive for YYYY-MM-DD (default now)"
+// @Param status query string false "Filter by status"
+// @Param assignee query string false "Filter by assignee ID"
+// @Param priority query string false "Filter by priority"
+// @Param labels query string false "Filter by label name"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several"
+// @Success 200 {object} models.FlowTimes
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /stats/cycle-time [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetFlowTimes(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r)
+	if err != nil {
+		writeQueryError(w, r, "", err)
+		return
+	}
+	f, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	issues, history, err := h.issueHistory(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to compute flow times", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to compute stats", nil)
+		return
+	}
+
+	utils.WriteJSONCached(w, r, http.StatusOK, analytics.FlowTimes(issues, history, from, to))
+}
+
+// GetThroughput godoc
+// @Summary Get throughput per assignee
+// @Description Number of issues each assignee completed (moved to Done) in the range.
+// @Tags stats
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC 3339), default 30 days ago"
+// @Param to query string false "End date, inclusive for YYYY-MM-DD (default now)"
+// @Param status query string false "Filter by status"
+// @Param assignee query string false "Filter by assignee ID"
+// @Param priority query string false "Filter by priority"
+// @Param labels query string false "Filter by label name"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.severity=High; repeat for any of several"
+// @Success 200 {object} models.ThroughputReport
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /stats/throughput [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetThroughput(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r)
+	if err != nil {
+		writeQueryError(w, r, "", err)
+		return
+	}
+	f, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	issues, history, err := h.issueHistory(r.Context(), f)
+	if err != nil {
+		slog.Error("Failed to compute throughput", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to compute stats", nil)
+		return
+	}
+
+	utils.WriteJSONCached(w, r, http.StatusOK, models.ThroughputReport{
+		From:      from,
+		To:        to,
+		Assignees: analytics.Throughput(issues, history, from, to),
+	})
+}
+
+// issueHistory loads the issues matching f with their status history
+func (h *Handler) issueHistory(ctx context.Context, f models.IssueFilter) ([]models.Issue, []models.StatusChange, error) {
+	list, err := h.Repo.ListIssues(ctx, models.IssueQuery{
+		IssueFilter: f,
+		Fields:      []string{"status", "assignee_id", "created_at", "updated_at"},
+		Expand:      &models.IssueExpand{Assignee: true},
+		Page:        1,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := h.Repo.GetStatusHistory(ctx, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return list.Items, history, nil
+}
+
+// userNames maps user IDs to names for labelling assignee groups
+func (h *Handler) userNames(ctx context.Context) (map[string]string, error) {
+	users, err := h.Repo.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	return names, nil
+}
+
+// parseDimensions parses a comma-separated list of stats dimensions
+func parseDimensions(s string) ([]string, error) {
+	var dims []string
+	for _, d := range strings.Split(s, ",") {
+		d = strings.TrimSpace(d)
+		valid := false
+		for _, f := range models.GroupByFields {
+			valid = valid || f == d
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid dimension %q, allowed: %s", d, strings.Join(models.GroupByFields, ", "))
+		}
+		dims = append(dims, d)
+	}
+	return dims, nil
+}
+
+// parseTimeRange reads the from/to parameters as RFC 3339 timestamps or
+// YYYY-MM-DD dates; a date for to includes that whole day. The default range
+// is the last 30 days.
+func parseTimeRange(r *http.Request) (time.Time, time.Time, error) {
+	v := validator.New()
+
+	to := time.Now().UTC()
+	if s := r.URL.Query().Get("to"); s != "" {
+		t, isDate, err := parseTime(s)
+		if err != nil {
+			v.Add("to", validator.CodeInvalidDate, err.Error())
+		}
+		if isDate {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultStatsRange)
+	if s := r.URL.Query().Get("from"); s != "" {
+		t, _, err := parseTime(s)
+		if err != nil {
+			v.Add("from", validator.CodeInvalidDate, err.Error())
+		}
+		from = t
+	}
+
+	if v.Valid() {
+		v.DateRange("from", "to", from, to)
+	}
+	return from, to, v.Err()
+}
+
+// parseSeriesRange reads the time range and bucket interval of a time series,
+// rejecting ranges with too many buckets
+func parseSeriesRange(r *http.Request) (time.Time, time.Time, analytics.Interval, error) {
+	from, to, err := parseTimeRange(r)
+	if err != nil {
+		return from, to, "", err
+	}
+	interval, err := analytics.ParseInterval(r.URL.Query().Get("interval"))
+	if err != nil {
+		return from, to, "", validator.Field("interval", validator.CodeOneOf, err.Error())
+	}
+	if len(interval.Buckets(from, to)) > maxStatsBuckets {
+		return from, to, "", validator.Field("from", validator.CodeInvalidRange, fmt.Sprintf("time range too large: at most %d buckets", maxStatsBuckets))
+	}
+	return from, to, interval, nil
+}
+
+func parseTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", s)
+	}
+	return t, false, nil
+}
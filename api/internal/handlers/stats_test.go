@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestGetStats(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	ctx := context.Background()
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES ('user1', 'Test User')")
+	repo.DB.Exec("INSERT INTO labels (id, name, color) VALUES ('bug', 'Bug', '#FF0000')")
+
+	now := time.Now()
+	assigneeID := "user1"
+	repo.CreateIssue(ctx, models.Issue{ID: "1", Title: "Issue 1", Status: "Todo", Priority: "Low", AssigneeID: &assigneeID, CreatedAt: now, UpdatedAt: now})
+	repo.CreateIssue(ctx, models.Issue{ID: "2", Title: "Issue 2", Status: "Todo", Priority: "High", CreatedAt: now, UpdatedAt: now})
+	repo.CreateIssue(ctx, models.Issue{ID: "3", Title: "Issue 3", Status: "Done", Priority: "High", AssigneeID: &assigneeID, CreatedAt: now, UpdatedAt: now})
+	repo.UpdateIssueLabels(ctx, "1", []string{"bug"})
+
+	t.Run("Grouped Counts", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var stats models.IssueStats
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		if stats.Total != 3 {
+			t.Errorf("Expected total 3, got %d", stats.Total)
+		}
+
+		status := stats.Groups["status"]
+		if len(status) != 2 || *status[0].Key != "Todo" || status[0].Count != 2 || *status[1].Key != "Done" {
+			t.Errorf("Expected Todo: 2 then Done: 1, got %+v", status)
+		}
+		assignee := stats.Groups["assignee"]
+		if len(assignee) != 2 || assignee[0].Name != "Test User" || assignee[0].Count != 2 || assignee[1].Key != nil {
+			t.Errorf("Expected Test User: 2 then unassigned, got %+v", assignee)
+		}
+		if len(stats.Groups["priority"]) != 2 || len(stats.Groups["label"]) != 2 {
+			t.Errorf("Expected priority and label groups, got %+v", stats.Groups)
+		}
+	})
+
+	t.Run("Filtered Pivot", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats?priority=High&group_by=status&pivot=status,assignee", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var stats models.IssueStats
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		if stats.Total != 2 || len(stats.Groups) != 1 {
+			t.Errorf("Expected 2 issues grouped by status only, got %+v", stats)
+		}
+		if stats.Pivot == nil || len(stats.Pivot.Cells) != 2 {
+			t.Fatalf("Expected 2 pivot cells, got %+v", stats.Pivot)
+		}
+		first := stats.Pivot.Cells[0]
+		if *first.Row != "Todo" || first.Column != nil || first.Count != 1 {
+			t.Errorf("Expected Todo/unassigned first, got %+v", first)
+		}
+	})
+
+	t.Run("Invalid Dimensions", func(t *testing.T) {
+		for _, query := range []string{"group_by=title", "pivot=status", "pivot=status,status"} {
+			req, _ := http.NewRequest("GET", "/stats?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+		}
+	})
+}
+
+func TestStatsTimeSeries(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	ctx := context.Background()
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES ('user1', 'Test User')")
+
+	created := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	assigneeID := "user1"
+	repo.CreateIssue(ctx, models.Issue{ID: "1", Title: "Issue 1", Status: "Todo", Priority: "Low", AssigneeID: &assigneeID, CreatedAt: created, UpdatedAt: created})
+	repo.CreateIssue(ctx, models.Issue{ID: "2", Title: "Issue 2", Status: "Todo", Priority: "Low", CreatedAt: created, UpdatedAt: created})
+	repo.UpdateIssue(ctx, "1", map[string]interface{}{"status": "In Progress", "updated_at": created.Add(24 * time.Hour)})
+	repo.UpdateIssue(ctx, "1", map[string]interface{}{"status": "Done", "updated_at": created.Add(30 * time.Hour)})
+
+	t.Run("Created Vs Closed", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats/created-vs-closed?from=2024-03-04&to=2024-03-06", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var report models.CreatedVsClosedReport
+		json.Unmarshal(w.Body.Bytes(), &report)
+		if report.Interval != "day" || len(report.Buckets) != 3 {
+			t.Fatalf("Expected 3 daily buckets, got %+v", report)
+		}
+		if report.Buckets[0].Created != 2 || report.Buckets[1].Closed != 1 || report.Buckets[2].Created != 0 {
+			t.Errorf("Unexpected buckets: %+v", report.Buckets)
+		}
+	})
+
+	t.Run("Cycle Time", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats/cycle-time?from=2024-03-01&to=2024-03-31", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var times models.FlowTimes
+		json.Unmarshal(w.Body.Bytes(), &times)
+		if times.CycleTime.Count != 1 || times.CycleTime.P50 != 6 || times.LeadTime.P50 != 30 {
+			t.Errorf("Expected 6h cycle time and 30h lead time, got %+v", times)
+		}
+	})
+
+	t.Run("Throughput", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats/throughput?from=2024-03-01&to=2024-03-31", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var report models.ThroughputReport
+		json.Unmarshal(w.Body.Bytes(), &report)
+		if len(report.Assignees) != 1 || report.Assignees[0].Name != "Test User" || report.Assignees[0].Completed != 1 {
+			t.Errorf("Expected Test User with 1 completed, got %+v", report.Assignees)
+		}
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		for _, query := range []string{"from=yesterday", "from=2024-03-05&to=2024-03-01", "interval=month", "from=2000-01-01&to=2024-01-01"} {
+			req, _ := http.NewRequest("GET", "/stats/created-vs-closed?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+		}
+	})
+}
+
+func TestGetCumulativeFlow(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	ctx := context.Background()
+	created := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo.CreateIssue(ctx, models.Issue{ID: "1", Title: "Issue 1", Status: "Todo", Priority: "Low", CreatedAt: created, UpdatedAt: created})
+	repo.UpdateIssue(ctx, "1", map[string]interface{}{"status": "Done", "updated_at": created.Add(24 * time.Hour)})
+
+	// An issue from before history tracking, moved to In Progress on day 2
+	repo.DB.Exec("INSERT INTO issues (id, title, status, priority, created_at, updated_at) VALUES ('2', 'Old', 'In Progress', 'Low', ?, ?)",
+		created, created.Add(48*time.Hour))
+
+	t.Run("Daily", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/reports/cumulative-flow?from=2024-03-04&to=2024-03-06&interval=day", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var flow models.CumulativeFlow
+		json.Unmarshal(w.Body.Bytes(), &flow)
+		if len(flow.Dates) != 3 {
+			t.Fatalf("Expected 3 dates, got %v", flow.Dates)
+		}
+
+		want := map[string][]int{
+			"Todo":        {2, 1, 0},
+			"In Progress": {0, 0, 1},
+			"Done":        {0, 1, 1},
+		}
+		for _, s := range flow.Series {
+			w, ok := want[s.Status]
+			if !ok {
+				w = []int{0, 0, 0}
+			}
+			for i := range w {
+				if s.Counts[i] != w[i] {
+					t.Errorf("%s: expected %v, got %v", s.Status, w, s.Counts)
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("Invalid Interval", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/reports/cumulative-flow?interval=hour", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
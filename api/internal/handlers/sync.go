@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+	maxSyncBatch     = 100
+)
+
+// GetSync godoc
+// @Summary Sync changes
+// @Description Get the issues, labels and users created, updated or deleted since a sync token.
+// @Description Without since the response is a full snapshot of the board (full is true).
+// @Description Each entity appears once, in its current state; deleted entities are listed as tombstones.
+// @Description Store next and pass it as since on the next sync; has_more means more changes are waiting.
+// @Description A token newer than the server's change log, e.g. after a restore, gets 410 and calls for a full sync.
+// @Tags sync
+// @Produce json
+// @Param since query string false "Sync token from the previous response"
+// @Param limit query int false "Maximum changed entities to return (default 500, max 1000)"
+// @Success 200 {object} models.SyncResponse
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 410 {object} utils.Problem "Sync token no longer valid"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /sync [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetSync(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var since int64
+	full := true
+	if s := query.Get("since"); s != "" {
+		seq, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || seq < 0 {
+			writeQueryError(w, r, "since", validator.Field("since", validator.CodeInvalid, "must be a sync token"))
+			return
+		}
+		since, full = seq, false
+	}
+	limit := defaultSyncLimit
+	if s := query.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxSyncLimit {
+			writeQueryError(w, r, "limit", validator.Field("limit", validator.CodeInvalid, fmt.Sprintf("must be an integer between 1 and %d", maxSyncLimit)))
+			return
+		}
+		limit = n
+	}
+
+	// Read the token first: a write landing during the snapshot is then
+	// sent again on the next sync rather than missed
+	latest, err := h.Repo.GetChangeSeq(ctx)
+	if err != nil {
+		slog.Error("Failed to fetch change sequence", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch changes", nil)
+		return
+	}
+	if since > latest {
+		utils.WriteProblem(w, r, utils.CodeSyncTokenExpired, "the token is newer than the change log; sync again without since", nil)
+		return
+	}
+
+	var resp *models.SyncResponse
+	if full {
+		resp, err = h.syncSnapshot(ctx, latest)
+	} else {
+		resp, err = h.syncChanges(ctx, since, limit)
+	}
+	if err != nil {
+		slog.Error("Failed to fetch changes", "since", since, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch changes", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
+
+// syncSnapshot returns the whole board as of token
+func (h *Handler) syncSnapshot(ctx context.Context, token int64) (*models.SyncResponse, error) {
+	resp := newSyncResponse(token)
+	resp.Full = true
+
+	// Archived issues are part of the delta too, so clients need them
+	issues, err := h.Repo.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{IncludeArchived: true}})
+	if err != nil {
+		return nil, err
+	}
+	labels, err := h.Repo.GetLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := h.Repo.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp.Issues = append(resp.Issues, issues.Items...)
+	resp.Labels = append(resp.Labels, labels...)
+	resp.Users = append(resp.Users, users...)
+	return resp, nil
+}
+
+// syncChanges returns up to limit entities changed after since, in their
+// current state or as tombstones
+func (h *Handler) syncChanges(ctx context.Context, since int64, limit int) (*models.SyncResponse, error) {
+	changes, err := h.Repo.GetChanges(ctx, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	resp := newSyncResponse(since)
+	if len(changes) > limit {
+		changes, resp.HasMore = changes[:limit], true
+	}
+
+	// Changed entities are fetched in one query per kind, then listed in
+	// change order
+	var issueIDs, labelIDs, userIDs []string
+	for _, c := range changes {
+		resp.Next = strconv.FormatInt(c.Seq, 10)
+		if c.Deleted {
+			resp.Deleted = append(resp.Deleted, models.Tombstone{Entity: c.Entity, ID: c.EntityID, DeletedAt: c.ChangedAt})
+			continue
+		}
+
+		switch c.Entity {
+		case models.EntityIssue:
+			issueIDs = append(issueIDs, c.EntityID)
+		case models.EntityLabel:
+			labelIDs = append(labelIDs, c.EntityID)
+		case models.EntityUser:
+			userIDs = append(userIDs, c.EntityID)
+		}
+	}
+
+	if len(issueIDs) > 0 {
+		// Archived issues are part of the delta too, like in the snapshot
+		issues, err := h.Repo.ListIssues(ctx, models.IssueQuery{IssueFilter: models.IssueFilter{IDs: issueIDs, IncludeArchived: true}})
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]models.Issue, len(issues.Items))
+		for _, issue := range issues.Items {
+			byID[issue.ID] = issue
+		}
+		for _, id := range issueIDs {
+			if issue, ok := byID[id]; ok {
+				resp.Issues = append(resp.Issues, issue)
+			}
+		}
+	}
+
+	if len(labelIDs) > 0 {
+		all, err := h.Repo.GetLabels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]models.Label, len(all))
+		for _, l := range all {
+			byID[l.ID] = l
+		}
+		for _, id := range labelIDs {
+			if l, ok := byID[id]; ok {
+				resp.Labels = append(resp.Labels, l)
+			}
+		}
+	}
+
+	if len(userIDs) > 0 {
+		users, err := h.Repo.GetUsersByID(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]models.User, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
+		for _, id := range userIDs {
+			if u, ok := byID[id]; ok {
+				resp.Users = append(resp.Users, u)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// newSyncResponse returns an empty response, so the lists encode as []
+func newSyncResponse(token int64) *models.SyncResponse {
+	return &models.SyncResponse{
+		Issues:  []models.Issue{},
+		Labels:  []models.Label{},
+		Users:   []models.User{},
+		Deleted: []models.Tombstone{},
+		Next:    strconv.FormatInt(token, 10),
+	}
+}
+
+// PostSync godoc
+// @Summary Apply queued mutations
+// @Description Apply a batch of issue mutations queued by an offline client, in order, and report the result of each.
+// @Description A mutation runs as the equivalent create, update, move or delete request, with the same validation, WIP limits and permissions, and is rejected with that request's problem if it fails.
+// @Description A mutation with a base sync token conflicts, and is not applied, when the issue changed after base; the result carries the server's copy.
+// @Description Mutations are applied independently: a rejected or conflicting one does not stop the rest.
+// @Tags sync
+// @Accept json
+// @Produce json
+// @Param mutations body models.SyncRequest true "Queued mutations"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 200 {object} models.SyncResult
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /sync [post]
+// @Security ApiKeyAuth
+func (h *Handler) PostSync(w http.ResponseWriter, r *http.Request) {
+	var req models.SyncRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode sync request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+	if err := validateSyncRequest(&req); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	result := models.SyncResult{Results: make([]models.MutationResult, 0, len(req.Mutations))}
+	for _, m := range req.Mutations {
+		res, err := h.applyMutation(r, m)
+		if err != nil {
+			// Earlier mutations are applied, so report this one as failed
+			// rather than the batch
+			slog.Error("Failed to apply mutation", "client_id", m.ClientID, "issue_id", m.ID, "error", err)
+			rec := newMutationRecorder()
+			utils.WriteProblem(rec, r, utils.CodeInternal, "Failed to apply mutation", nil)
+			res = models.MutationResult{ClientID: m.ClientID, Result: models.MutationRejected, Problem: rec.body.Bytes()}
+		}
+		result.Results = append(result.Results, res)
+	}
+	utils.WriteJSON(w, http.StatusOK, result)
+}
+
+// applyMutation checks m against its base token and, unless it conflicts,
+// serves it as the equivalent request through the mutation routes
+func (h *Handler) applyMutation(r *http.Request, m models.Mutation) (models.MutationResult, error) {
+	ctx := r.Context()
+	res := models.MutationResult{ClientID: m.ClientID}
+
+	if m.Base != "" {
+		base, _ := strconv.ParseInt(m.Base, 10, 64) // checked by validateSyncRequest
+		seq, err := h.Repo.GetEntityChangeSeq(ctx, models.EntityIssue, m.ID)
+		if err != nil {
+			return res, err
+		}
+		if seq > base {
+			current, err := h.Repo.GetIssue(ctx, m.ID)
+			if err != nil {
+				return res, err
+			}
+			res.Result, res.Issue, res.Deleted = models.MutationConflict, current, current == nil
+			return res, nil
+		}
+	}
+
+	id := url.PathEscape(m.ID)
+	method, path := http.MethodPost, "/issues"
+	switch m.Op {
+	case models.MutationUpdate:
+		method, path = http.MethodPatch, "/issues/"+id
+	case models.MutationMove:
+		method, path = http.MethodPatch, "/issues/"+id+"/move"
+	case models.MutationDelete:
+		method, path = http.MethodDelete, "/issues/"+id
+	}
+
+	// A fresh route context makes the mutation routes match path rather
+	// than continue routing the sync request
+	subCtx := context.WithValue(ctx, chi.RouteCtxKey, chi.NewRouteContext())
+	sub, err := http.NewRequestWithContext(subCtx, method, path, bytes.NewReader(m.Data))
+	if err != nil {
+		return res, err
+	}
+	sub.Header.Set("Content-Type", "application/json")
+	sub.Header.Set("Accept", r.Header.Get("Accept"))
+
+	rec := newMutationRecorder()
+	h.mutationRoutes().ServeHTTP(rec, sub)
+
+	if rec.status >= http.StatusBadRequest {
+		res.Result, res.Problem = models.MutationRejected, json.RawMessage(rec.body.Bytes())
+		return res, nil
+	}
+
+	res.Result = models.MutationApplied
+	switch m.Op {
+	case models.MutationCreate, models.MutationUpdate:
+		var issue models.Issue
+		if err := json.Unmarshal(rec.body.Bytes(), &issue); err != nil {
+			return res, err
+		}
+		res.Issue = &issue
+	case models.MutationMove:
+		var moved models.MoveIssueResponse
+		if err := json.Unmarshal(rec.body.Bytes(), &moved); err != nil {
+			return res, err
+		}
+		res.Issue = &moved.Issue
+	case models.MutationDelete:
+		res.Deleted = true
+	}
+	return res, nil
+}
+
+// mutationRoutes returns the routes sync mutations are served by. Without
+// Mutations set they are the handler's own issue routes, with no
+// permission checks.
+func (h *Handler) mutationRoutes() http.Handler {
+	if h.Mutations != nil {
+		return h.Mutations
+	}
+	r := chi.NewRouter()
+	r.Post("/issues", h.CreateIssue)
+	r.Patch("/issues/{id}", h.UpdateIssue)
+	r.Patch("/issues/{id}/move", h.MoveIssue)
+	r.Delete("/issues/{id}", h.DeleteIssue)
+	return r
+}
+
+// mutationRecorder buffers the response to a sync mutation
+type mutationRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newMutationRecorder() *mutationRecorder {
+	return &mutationRecorder{header: make(http.Header)}
+}
+
+func (m *mutationRecorder) Header() http.Header {
+	return m.header
+}
+
+func (m *mutationRecorder) WriteHeader(status int) {
+	if m.status == 0 {
+		m.status = status
+	}
+}
+
+func (m *mutationRecorder) Write(b []byte) (int, error) {
+	m.WriteHeader(http.StatusOK)
+	return m.body.Write(b)
+}
+
+// validateSyncRequest validates a batch of mutations
+func validateSyncRequest(req *models.SyncRequest) error {
+	v := validator.New()
+
+	if len(req.Mutations) == 0 {
+		v.Add("mutations", validator.CodeRequired, "is required")
+	}
+	if len(req.Mutations) > maxSyncBatch {
+		v.Add("mutations", validator.CodeTooLong, fmt.Sprintf("must have at most %d items", maxSyncBatch))
+	}
+	ops := []string{models.MutationCreate, models.MutationUpdate, models.MutationMove, models.MutationDelete}
+	for i, m := range req.Mutations {
+		field := fmt.Sprintf("mutations[%d]", i)
+		v.MaxLength(field+".client_id", m.ClientID, 100)
+		v.OneOf(field+".op", m.Op, ops)
+		if m.Op == models.MutationCreate {
+			if m.ID != "" || m.Base != "" {
+				v.AddError(field, "id and base do not apply to create")
+			}
+		} else {
+			v.Required(field+".id", m.ID)
+		}
+		if m.Op != models.MutationDelete && len(m.Data) == 0 {
+			v.Add(field+".data", validator.CodeRequired, "is required")
+		}
+		if m.Base != "" {
+			if seq, err := strconv.ParseInt(m.Base, 10, 64); err != nil || seq < 0 {
+				v.AddError(field+".base", "must be a sync token")
+			}
+		}
+	}
+
+	return v.Err()
+}
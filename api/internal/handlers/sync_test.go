@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestSync(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	sync := func(query string) models.SyncResponse {
+		t.Helper()
+		w := serve(r, "GET", "/sync"+query, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp models.SyncResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+	apply := func(body string) []models.MutationResult {
+		t.Helper()
+		w := serve(r, "POST", "/sync", body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var result models.SyncResult
+		json.Unmarshal(w.Body.Bytes(), &result)
+		return result.Results
+	}
+
+	first := createIssue(t, r, issueJSON("First", "Todo"))
+	second := createIssue(t, r, issueJSON("Second", "Todo"))
+	snapshot := sync("")
+	if !snapshot.Full || len(snapshot.Issues) != 2 || snapshot.Next == "" {
+		t.Fatalf("Expected a full snapshot with 2 issues, got %+v", snapshot)
+	}
+
+	t.Run("Delta With Tombstones", func(t *testing.T) {
+		serve(r, "PATCH", "/issues/"+first.ID, `{"title": "First, renamed"}`)
+		serve(r, "DELETE", "/issues/"+second.ID, "")
+		third := createIssue(t, r, issueJSON("Third", "Todo"))
+
+		delta := sync("?since=" + snapshot.Next)
+		if delta.Full || len(delta.Issues) != 2 || len(delta.Deleted) != 1 {
+			t.Fatalf("Expected 2 changed issues and 1 tombstone, got %+v", delta)
+		}
+		if delta.Issues[0].Title != "First, renamed" || delta.Issues[1].ID != third.ID {
+			t.Errorf("Expected the current state in change order, got %+v", delta.Issues)
+		}
+		if tomb := delta.Deleted[0]; tomb.Entity != models.EntityIssue || tomb.ID != second.ID {
+			t.Errorf("Expected a tombstone for %s, got %+v", second.ID, tomb)
+		}
+
+		if again := sync("?since=" + delta.Next); len(again.Issues) != 0 || len(again.Deleted) != 0 || again.Next != delta.Next {
+			t.Errorf("Expected nothing new since %s, got %+v", delta.Next, again)
+		}
+	})
+
+	t.Run("Paging", func(t *testing.T) {
+		page := sync("?since=" + snapshot.Next + "&limit=1")
+		if !page.HasMore || len(page.Issues)+len(page.Deleted) != 1 {
+			t.Fatalf("Expected one change and more to come, got %+v", page)
+		}
+		if rest := sync("?since=" + page.Next); rest.HasMore || len(rest.Issues)+len(rest.Deleted) != 2 {
+			t.Errorf("Expected the remaining 2 changes, got %+v", rest)
+		}
+	})
+
+	t.Run("Invalid Tokens", func(t *testing.T) {
+		if w := serve(r, "GET", "/sync?since=abc", ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a malformed token, got %d", w.Code)
+		}
+		w := serve(r, "GET", "/sync?since=99999", "")
+		var p utils.Problem
+		json.Unmarshal(w.Body.Bytes(), &p)
+		if w.Code != http.StatusGone || p.Code != utils.CodeSyncTokenExpired {
+			t.Errorf("Expected 410 SYNC_TOKEN_EXPIRED for a future token, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Apply Mutations", func(t *testing.T) {
+		token := sync("").Next
+		target := createIssue(t, r, issueJSON("Target", "Todo"))
+		results := apply(fmt.Sprintf(`{"mutations": [
+			{"client_id": "c1", "op": "create", "data": {"title": "Offline", "status": "Backlog", "priority": "High"}},
+			{"client_id": "c2", "op": "move", "id": %q, "data": {"status": "Done", "order_index": 3}},
+			{"client_id": "c3", "op": "update", "id": %q, "data": {"title": ""}},
+			{"client_id": "c4", "op": "update", "id": %q, "base": %q, "data": {"title": "Stale"}},
+			{"client_id": "c5", "op": "delete", "id": %q}
+		]}`, target.ID, target.ID, target.ID, token, first.ID))
+
+		if len(results) != 5 {
+			t.Fatalf("Expected 5 results, got %+v", results)
+		}
+		if res := results[0]; res.ClientID != "c1" || res.Result != models.MutationApplied || res.Issue == nil || res.Issue.Title != "Offline" {
+			t.Errorf("Expected the create applied, got %+v", res)
+		}
+		if res := results[1]; res.Result != models.MutationApplied || res.Issue == nil || res.Issue.Status != "Done" {
+			t.Errorf("Expected the move applied, got %+v", res)
+		}
+		var p utils.Problem
+		json.Unmarshal(results[2].Problem, &p)
+		if results[2].Result != models.MutationRejected || p.Code != utils.CodeValidationFailed {
+			t.Errorf("Expected the invalid update rejected, got %+v", results[2])
+		}
+		if res := results[3]; res.Result != models.MutationConflict || res.Issue == nil || res.Issue.Status != "Done" {
+			t.Errorf("Expected a conflict with the server's copy, got %+v", res)
+		}
+		if res := results[4]; res.Result != models.MutationApplied || !res.Deleted {
+			t.Errorf("Expected the delete applied, got %+v", res)
+		}
+
+		// Updating a deleted issue conflicts too
+		results = apply(fmt.Sprintf(`{"mutations": [{"op": "update", "id": %q, "base": %q, "data": {"title": "Gone"}}]}`, first.ID, token))
+		if len(results) != 1 || results[0].Result != models.MutationConflict || !results[0].Deleted {
+			t.Errorf("Expected a conflict for a deleted issue, got %+v", results)
+		}
+	})
+
+	t.Run("Invalid Batch", func(t *testing.T) {
+		for _, body := range []string{
+			`{"mutations": []}`,
+			`{"mutations": [{"op": "rename", "id": "x"}]}`,
+			`{"mutations": [{"op": "update", "data": {}}]}`,
+			`{"mutations": [{"op": "delete", "id": "x", "base": "soon"}]}`,
+		} {
+			if w := serve(r, "POST", "/sync", body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+			}
+		}
+	})
+}
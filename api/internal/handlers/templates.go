@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetTemplates godoc
+// @Summary List issue templates
+// @Description List all issue templates, ordered by name
+// @Tags templates
+// @Produce json
+// @Success 200 {array} models.IssueTemplate
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /templates [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.Repo.ListTemplates(r.Context())
+	if err != nil {
+		slog.Error("Failed to fetch templates", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch templates", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary Get an issue template
+// @Tags templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.IssueTemplate
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /templates/{id} [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	template, err := h.Repo.GetTemplate(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to fetch template", "template_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch template", nil)
+		return
+	}
+	if template == nil {
+		utils.WriteProblem(w, r, utils.CodeTemplateNotFound, "", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, template)
+}
+
+// CreateTemplate godoc
+// @Summary Create an issue template
+// @Description Create a template whose defaults are applied to issues created with its template_id.
+// @Description Empty status, priority and type leave them to the create request.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body models.TemplateRequest true "Template content"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 201 {object} models.IssueTemplate
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 409 {object} utils.Problem "Name already in use"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 422 {object} utils.Problem "Unknown labels"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /templates [post]
+// @Security ApiKeyAuth
+func (h *Handler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeTemplateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	template := newTemplate(uuid.New().String(), req, now)
+	template.CreatedAt = now
+	if err := h.Repo.CreateTemplate(r.Context(), template); err != nil {
+		writeTemplateError(w, r, err, "Failed to create template", template.ID)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Replace an issue template
+// @Description Replace every field of a template. Issues already created from it are unchanged.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param template body models.TemplateRequest true "Template content"
+// @Success 200 {object} models.IssueTemplate
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 409 {object} utils.Problem "Name already in use"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 422 {object} utils.Problem "Unknown labels"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /templates/{id} [put]
+// @Security ApiKeyAuth
+func (h *Handler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	req, ok := h.decodeTemplateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.UpdateTemplate(ctx, newTemplate(id, req, time.Now())); err != nil {
+		writeTemplateError(w, r, err, "Failed to update template", id)
+		return
+	}
+
+	template, err := h.Repo.GetTemplate(ctx, id)
+	if err != nil {
+		slog.Error("Failed to fetch updated template", "template_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch updated template", nil)
+		return
+	}
+	if template == nil {
+		// Deleted between the update and the read
+		utils.WriteProblem(w, r, utils.CodeTemplateNotFound, "", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete an issue template
+// @Description Delete a template. Issues already created from it are unchanged.
+// @Tags templates
+// @Param id path string true "Template ID"
+// @Success 204 {object} nil
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /templates/{id} [delete]
+// @Security ApiKeyAuth
+func (h *Handler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.Repo.DeleteTemplate(r.Context(), id); err != nil {
+		writeStoreError(w, r, err, utils.CodeTemplateNotFound, "Failed to delete template", "template_id", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeTemplateRequest decodes and validates a template body, checking that
+// its labels exist. It writes the error response and returns false if not.
+func (h *Handler) decodeTemplateRequest(w http.ResponseWriter, r *http.Request) (*models.TemplateRequest, bool) {
+	var req models.TemplateRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode template request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return nil, false
+	}
+	if err := validateTemplateRequest(&req); err != nil {
+		writeValidationError(w, r, err)
+		return nil, false
+	}
+	return &req, true
+}
+
+// newTemplate builds the template stored for a request
+func newTemplate(id string, req *models.TemplateRequest, now time.Time) models.IssueTemplate {
+	return models.IssueTemplate{
+		ID:          id,
+		Name:        req.Name,
+		TitlePrefix: req.TitlePrefix,
+		Description: req.Description,
+		Status:      req.Status,
+		Priority:    req.Priority,
+		Type:        req.Type,
+		LabelIDs:    req.LabelIDs,
+		UpdatedAt:   now,
+	}
+}
+
+// writeTemplateError responds to a failed template write. The only
+// conflict a valid template can hit is its name.
+func writeTemplateError(w http.ResponseWriter, r *http.Request, err error, msg, id string) {
+	if errors.Is(err, database.ErrConflict) {
+		utils.WriteProblem(w, r, utils.CodeTemplateNameTaken, "", nil)
+		return
+	}
+	writeStoreError(w, r, err, utils.CodeTemplateNotFound, msg, "template_id", id)
+}
+
+// applyTemplate fills in the fields of a create request left out from its
+// template. It writes the error response and returns false if the template
+// does not exist.
+func (h *Handler) applyTemplate(w http.ResponseWriter, r *http.Request, req *models.CreateIssueRequest) bool {
+	v := validator.New()
+	v.UUID("template_id", *req.TemplateID)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, r, err)
+		return false
+	}
+
+	template, err := h.Repo.GetTemplate(r.Context(), *req.TemplateID)
+	if err != nil {
+		slog.Error("Failed to fetch template", "template_id", *req.TemplateID, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch template", nil)
+		return false
+	}
+	if template == nil {
+		writeReferenceError(w, r, validator.Field("template_id", validator.CodeNotFound, "unknown ID: "+*req.TemplateID))
+		return false
+	}
+	template.Apply(req)
+	return true
+}
+
+// validateTemplateRequest validates a template body. Duplicate label IDs
+// are dropped.
+func validateTemplateRequest(req *models.TemplateRequest) error {
+	v := validator.New()
+
+	req.Name = strings.TrimSpace(req.Name)
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 100)
+	v.MaxLength("title_prefix", req.TitlePrefix, 50)
+	v.MaxLength("description", req.Description, 5000)
+	if req.Status != "" {
+		v.OneOf("status", req.Status, models.ValidStatuses)
+	}
+	if req.Priority != "" {
+		v.OneOf("priority", req.Priority, models.ValidPriorities)
+	}
+	if req.Type != "" {
+		v.OneOf("type", req.Type, models.ValidTypes)
+	}
+
+	seen := make(map[string]bool, len(req.LabelIDs))
+	var labelIDs []string
+	for _, id := range req.LabelIDs {
+		if !seen[id] {
+			seen[id] = true
+			labelIDs = append(labelIDs, id)
+		}
+	}
+	req.LabelIDs = labelIDs
+
+	return validateReferences(v, nil, req.LabelIDs)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestTemplates(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	bugLabel := "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f"
+	docsLabel := "7a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d"
+	repo.DB.Exec("INSERT INTO labels (id, name, color) VALUES (?, 'Bug', '#FF0000'), (?, 'Docs', '#0000FF')", bugLabel, docsLabel)
+
+	var bug models.IssueTemplate
+	t.Run("Create", func(t *testing.T) {
+		w := serve(r, "POST", "/templates", fmt.Sprintf(`{
+			"name": " Bug report ", "title_prefix": "[Bug] ", "description": "## Steps to reproduce\n\n## Expected",
+			"priority": "High", "status": "Todo", "type": "bug", "label_ids": [%q, %q]
+		}`, bugLabel, bugLabel))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		json.Unmarshal(w.Body.Bytes(), &bug)
+		if bug.ID == "" || bug.Name != "Bug report" || len(bug.LabelIDs) != 1 {
+			t.Errorf("Expected the trimmed template with one label, got %+v", bug)
+		}
+
+		if w := serve(r, "POST", "/templates", `{"name": "Bug report"}`); w.Code != http.StatusConflict || problemCode(w) != utils.CodeTemplateNameTaken {
+			t.Errorf("Expected 409 TEMPLATE_NAME_TAKEN, got %d %s", w.Code, w.Body.String())
+		}
+		if w := serve(r, "POST", "/templates", `{"name": "", "status": "Later", "label_ids": ["bug"]}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for an invalid template, got %d", w.Code)
+		}
+		if w := serve(r, "POST", "/templates", `{"name": "Story", "type": "story"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for an unknown type, got %d", w.Code)
+		}
+		unknown := `{"name": "Docs", "label_ids": ["00000000-0000-4000-8000-000000000000"]}`
+		if w := serve(r, "POST", "/templates", unknown); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for an unknown label, got %d", w.Code)
+		}
+	})
+
+	t.Run("Read", func(t *testing.T) {
+		w := serve(r, "GET", "/templates/"+bug.ID, "")
+		var got models.IssueTemplate
+		json.Unmarshal(w.Body.Bytes(), &got)
+		if w.Code != http.StatusOK || got.Description != bug.Description {
+			t.Errorf("Expected the template, got %d %+v", w.Code, got)
+		}
+		var list []models.IssueTemplate
+		json.Unmarshal(serve(r, "GET", "/templates", "").Body.Bytes(), &list)
+		if len(list) != 1 || list[0].ID != bug.ID {
+			t.Errorf("Expected one template, got %+v", list)
+		}
+		if w := serve(r, "GET", "/templates/missing", ""); w.Code != http.StatusNotFound || problemCode(w) != utils.CodeTemplateNotFound {
+			t.Errorf("Expected 404 TEMPLATE_NOT_FOUND, got %d", w.Code)
+		}
+	})
+
+	t.Run("Create Issue From Template", func(t *testing.T) {
+		issue := createIssue(t, r, fmt.Sprintf(`{"template_id": %q, "title": "Login fails"}`, bug.ID))
+		if issue.Title != "[Bug] Login fails" || issue.Description != bug.Description || issue.Status != "Todo" || issue.Priority != "High" || issue.Type != "bug" {
+			t.Errorf("Expected the template's defaults, got %+v", issue)
+		}
+		if len(issue.Labels) != 1 || issue.Labels[0].ID != bugLabel {
+			t.Errorf("Expected the template's labels, got %+v", issue.Labels)
+		}
+
+		// Fields in the request win, and the prefix isn't doubled
+		issue = createIssue(t, r, fmt.Sprintf(`{"template_id": %q, "title": "[Bug] Crash", "description": "Stack trace", "status": "Backlog", "priority": "Low", "type": "task", "label_ids": [%q]}`, bug.ID, docsLabel))
+		if issue.Title != "[Bug] Crash" || issue.Description != "Stack trace" || issue.Status != "Backlog" || issue.Priority != "Low" || issue.Type != "task" {
+			t.Errorf("Expected the request's fields, got %+v", issue)
+		}
+		if len(issue.Labels) != 1 || issue.Labels[0].ID != docsLabel {
+			t.Errorf("Expected the request's labels, got %+v", issue.Labels)
+		}
+		if issue := createIssue(t, r, fmt.Sprintf(`{"template_id": %q, "title": "No labels", "label_ids": []}`, bug.ID)); len(issue.Labels) != 0 {
+			t.Errorf("Expected an empty label_ids to clear the template's labels, got %+v", issue.Labels)
+		}
+
+		missing := `{"template_id": "00000000-0000-4000-8000-000000000000", "title": "X", "status": "Todo", "priority": "Low"}`
+		if w := serve(r, "POST", "/issues", missing); w.Code != http.StatusUnprocessableEntity || problemCode(w) != utils.CodeUnknownReference {
+			t.Errorf("Expected 422 UNKNOWN_REFERENCE for a missing template, got %d %s", w.Code, w.Body.String())
+		}
+		if w := serve(r, "POST", "/issues", `{"template_id": "bug", "title": "X"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a malformed template_id, got %d", w.Code)
+		}
+	})
+
+	t.Run("Update And Delete", func(t *testing.T) {
+		w := serve(r, "PUT", "/templates/"+bug.ID, `{"name": "Bug", "title_prefix": "Bug: "}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var got models.IssueTemplate
+		json.Unmarshal(w.Body.Bytes(), &got)
+		if got.Name != "Bug" || got.Priority != "" || len(got.LabelIDs) != 0 || !got.CreatedAt.Equal(bug.CreatedAt) {
+			t.Errorf("Expected the template replaced, got %+v", got)
+		}
+		if w := serve(r, "PUT", "/templates/missing", `{"name": "X"}`); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 updating a missing template, got %d", w.Code)
+		}
+
+		if w := serve(r, "DELETE", "/templates/"+bug.ID, ""); w.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", w.Code)
+		}
+		if w := serve(r, "DELETE", "/templates/"+bug.ID, ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 deleting twice, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// trashSort lists the most recently deleted issues first: deleting an issue
+// sets its updated_at
+var trashSort = []models.SortKey{{Field: models.SortUpdatedAt, Desc: true}}
+
+// GetTrash godoc
+// @Summary List the trash
+// @Description List deleted issues, most recently deleted first, until they are restored or purged.
+// @Description Accepts the filters, fieldsets, sort and pagination parameters of GET /issues.
+// @Tags issues
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param assignee query string false "Filter by assignee ID"
+// @Param priority query string false "Filter by priority"
+// @Param labels query string false "Filter by label name"
+// @Param sort query string false "Comma-separated sort keys (default -updated_at)"
+// @Param cursor query string false "Opaque cursor from a previous next_cursor"
+// @Param limit query int false "Keyset page size (default 50, max 200)"
+// @Success 200 {object} models.IssueList
+// @Success 200 {array} models.Issue
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /trash [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	q, err := parseIssueQuery(r)
+	if err != nil {
+		writeQueryError(w, r, "", err)
+		return
+	}
+	if !h.parseFieldQuery(w, r, &q) {
+		return
+	}
+	q.Trashed = true
+	q.IncludeArchived = true
+	if len(q.Sort) == 0 {
+		q.Sort = trashSort
+	}
+
+	list, err := h.Repo.ListIssues(r.Context(), q)
+	if errors.Is(err, models.ErrInvalidCursor) {
+		writeQueryError(w, r, "cursor", err)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to fetch trash", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch trash", nil)
+		return
+	}
+
+	if q.Keyset() || q.PageSize > 0 {
+		w.Header().Set("X-Total-Count", strconv.Itoa(list.TotalCount))
+		if links := paginationLinks(r, q, list); len(links) > 0 {
+			w.Header().Set("Link", strings.Join(links, ", "))
+		}
+	}
+
+	items := projectIssues(list.Items, q)
+	if q.Keyset() {
+		utils.WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"items":       items,
+			"next_cursor": list.NextCursor,
+			"total_count": list.TotalCount,
+		})
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, items)
+}
+
+// RestoreIssue godoc
+// @Summary Restore an issue
+// @Description Move an issue out of the trash, back to its status column and position
+// @Tags issues
+// @Produce json
+// @Param id path string true "Issue ID"
+// @Success 200 {object} models.Issue
+// @Failure 404 {object} utils.Problem "Not in the trash"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issues/{id}/restore [post]
+// @Security ApiKeyAuth
+func (h *Handler) RestoreIssue(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.Repo.RestoreIssue(r.Context(), id); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to restore issue", "issue_id", id)
+		return
+	}
+	h.writeIssue(w, r, id, http.StatusOK)
+}
+
+// PurgeIssue godoc
+// @Summary Permanently delete an issue
+// @Description Delete an issue, live or in the trash, together with its labels and history. It cannot be restored.
+// @Tags issues
+// @Param id path string true "Issue ID"
+// @Success 204 {object} nil
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issues/{id}/permanent [delete]
+// @Security ApiKeyAuth
+func (h *Handler) PurgeIssue(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.Repo.PurgeIssue(r.Context(), id); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueNotFound, "Failed to delete issue", "issue_id", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestTrash(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	trash := func() []models.Issue {
+		t.Helper()
+		w := serve(r, "GET", "/trash", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var issues []models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issues)
+		return issues
+	}
+
+	kept := createIssue(t, r, issueJSON("Kept", "Todo"))
+	first := createIssue(t, r, issueJSON("First", "Todo"))
+	second := createIssue(t, r, issueJSON("Second", "Todo"))
+
+	t.Run("Delete Moves To Trash", func(t *testing.T) {
+		for _, id := range []string{first.ID, second.ID} {
+			if w := serve(r, "DELETE", "/issues/"+id, ""); w.Code != http.StatusNoContent {
+				t.Fatalf("Expected status 204, got %d", w.Code)
+			}
+		}
+		if w := serve(r, "GET", "/issues/"+first.ID, ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected a trashed issue to be hidden, got %d", w.Code)
+		}
+		var live []models.Issue
+		json.Unmarshal(serve(r, "GET", "/issues", "").Body.Bytes(), &live)
+		if len(live) != 1 || live[0].ID != kept.ID {
+			t.Errorf("Expected only %s to be listed, got %+v", kept.ID, live)
+		}
+
+		issues := trash()
+		if len(issues) != 2 || issues[0].ID != second.ID || issues[0].DeletedAt == nil {
+			t.Errorf("Expected both issues in the trash, most recent first, got %+v", issues)
+		}
+		if w := serve(r, "DELETE", "/issues/"+first.ID, ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 deleting a trashed issue, got %d", w.Code)
+		}
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		w := serve(r, "POST", "/issues/"+first.ID+"/restore", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var issue models.Issue
+		json.Unmarshal(w.Body.Bytes(), &issue)
+		if issue.ID != first.ID || issue.DeletedAt != nil || issue.Status != "Todo" {
+			t.Errorf("Expected the restored issue, got %+v", issue)
+		}
+		if w := serve(r, "POST", "/issues/"+first.ID+"/restore", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 restoring a live issue, got %d", w.Code)
+		}
+		if issues := trash(); len(issues) != 1 || issues[0].ID != second.ID {
+			t.Errorf("Expected only %s left in the trash, got %+v", second.ID, issues)
+		}
+	})
+
+	t.Run("Permanent Delete", func(t *testing.T) {
+		for _, id := range []string{first.ID, second.ID} {
+			if w := serve(r, "DELETE", "/issues/"+id+"/permanent", ""); w.Code != http.StatusNoContent {
+				t.Errorf("Expected status 204 for %s, got %d", id, w.Code)
+			}
+		}
+		if issues := trash(); len(issues) != 0 {
+			t.Errorf("Expected an empty trash, got %+v", issues)
+		}
+		if w := serve(r, "POST", "/issues/"+second.ID+"/restore", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 restoring a purged issue, got %d", w.Code)
+		}
+		if w := serve(r, "DELETE", "/issues/"+second.ID+"/permanent", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 purging twice, got %d", w.Code)
+		}
+	})
+}
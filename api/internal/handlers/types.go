@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetIssueTypes godoc
+// @Summary List issue types
+// @Description List the issue types with their icon, color and rules, in display order
+// @Tags issue-types
+// @Produce json
+// @Success 200 {array} models.IssueType
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issue-types [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetIssueTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := h.Repo.ListIssueTypes(r.Context())
+	if err != nil {
+		slog.Error("Failed to fetch issue types", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issue types", nil)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, types)
+}
+
+// GetIssueType godoc
+// @Summary Get an issue type
+// @Tags issue-types
+// @Produce json
+// @Param key path string true "Type key (bug, feature, task or epic)"
+// @Success 200 {object} models.IssueType
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issue-types/{key} [get]
+// @Security ApiKeyAuth
+func (h *Handler) GetIssueType(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.findIssueType(w, r, chi.URLParam(r, "key"))
+	if !ok {
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, t)
+}
+
+// UpdateIssueType godoc
+// @Summary Update an issue type
+// @Description Replace the display and rules of an issue type. Required fields are description, assignee_id,
+// @Description label_ids or cf.<key>; statuses restricts the statuses its issues may be in, all if empty.
+// @Description Rules apply to issues as they are created or changed, not to existing issues.
+// @Tags issue-types
+// @Accept json
+// @Produce json
+// @Param key path string true "Type key (bug, feature, task or epic)"
+// @Param type body models.IssueTypeRequest true "Issue type"
+// @Success 200 {object} models.IssueType
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 404 {object} utils.Problem "Not Found"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /issue-types/{key} [put]
+// @Security ApiKeyAuth
+func (h *Handler) UpdateIssueType(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := chi.URLParam(r, "key")
+	var req models.IssueTypeRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode update issue type request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+
+	t, ok := h.findIssueType(w, r, key)
+	if !ok {
+		return
+	}
+	fields, err := h.fieldsByKey(ctx)
+	if err != nil {
+		slog.Error("Failed to fetch custom fields", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch custom fields", nil)
+		return
+	}
+	if err := validateIssueTypeRequest(&req, fields); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	t.Name = req.Name
+	t.Icon = req.Icon
+	t.Color = req.Color
+	t.RequiredFields = req.RequiredFields
+	t.Statuses = req.Statuses
+	t.UpdatedAt = time.Now()
+	if err := h.Repo.UpdateIssueType(ctx, *t); err != nil {
+		writeStoreError(w, r, err, utils.CodeIssueTypeNotFound, "Failed to update issue type", "type", key)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, t)
+}
+
+// findIssueType fetches an issue type. It writes the error response and
+// returns false if it does not exist.
+func (h *Handler) findIssueType(w http.ResponseWriter, r *http.Request, key string) (*models.IssueType, bool) {
+	t, err := h.Repo.GetIssueType(r.Context(), key)
+	if err != nil {
+		slog.Error("Failed to fetch issue type", "type", key, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issue type", nil)
+		return nil, false
+	}
+	if t == nil {
+		utils.WriteProblem(w, r, utils.CodeIssueTypeNotFound, "", nil)
+		return nil, false
+	}
+	return t, true
+}
+
+// checkTypeRules checks an issue, as it will be stored, against the rules
+// of its type. Only the rules on the fields in changed are checked, or all
+// of them if changed is nil, so that issues created before a rule keep
+// accepting unrelated changes. It writes the error response and returns
+// false if a rule is broken.
+func (h *Handler) checkTypeRules(w http.ResponseWriter, r *http.Request, issue models.Issue, changed []string) bool {
+	ctx := r.Context()
+	t, err := h.Repo.GetIssueType(ctx, issue.Type)
+	if err == nil && t != nil && requiresCustomFields(*t) {
+		// Requirements on since deleted custom fields no longer apply
+		var fields map[string]models.CustomField
+		fields, err = h.fieldsByKey(ctx)
+		t.RequiredFields = existingRequirements(t.RequiredFields, fields)
+	}
+	if err != nil {
+		slog.Error("Failed to fetch issue type", "type", issue.Type, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to fetch issue type", nil)
+		return false
+	}
+	if t == nil {
+		return true
+	}
+
+	v := validator.New()
+	if (changed == nil || contains(changed, "status")) && !t.AllowsStatus(issue.Status) {
+		v.Add("status", validator.CodeOneOf, fmt.Sprintf("must be one of: %s for %s issues", strings.Join(t.AllowedStatuses(), ", "), t.Key))
+	}
+	for _, f := range t.MissingFields(issue) {
+		if changed == nil || contains(changed, f) {
+			v.Add(f, validator.CodeRequired, fmt.Sprintf("is required for %s issues", t.Key))
+		}
+	}
+	if err := v.Err(); err != nil {
+		writeValidationError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// checkUpdateTypeRules checks an issue with the changes in req and
+// fieldValues applied against the rules of its type, see checkTypeRules.
+// Changing the type checks all rules of the new type. An issue that does
+// not exist passes, leaving the update to report it.
+func (h *Handler) checkUpdateTypeRules(w http.ResponseWriter, r *http.Request, id string, req *models.UpdateIssueRequest, fieldValues models.FieldValues) bool {
+	changed := []string{}
+	if req.Description != nil {
+		changed = append(changed, "description")
+	}
+	if req.Status != nil {
+		changed = append(changed, "status")
+	}
+	if req.AssigneeID != nil {
+		changed = append(changed, "assignee_id")
+	}
+	if req.LabelIDs != nil {
+		changed = append(changed, "label_ids")
+	}
+	for key := range fieldValues {
+		changed = append(changed, "custom_fields."+key)
+	}
+	if req.Type == nil && len(changed) == 0 {
+		return true
+	}
+
+	current, err := h.Repo.GetIssue(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to fetch issue", "issue_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to check issue type rules", nil)
+		return false
+	}
+	if current == nil {
+		return true
+	}
+
+	issue := *current
+	if req.Description != nil {
+		issue.Description = *req.Description
+	}
+	if req.Status != nil {
+		issue.Status = *req.Status
+	}
+	if req.AssigneeID != nil {
+		issue.AssigneeID = req.AssigneeID
+	}
+	if req.LabelIDs != nil {
+		issue.Labels = nil
+		for _, labelID := range req.LabelIDs {
+			issue.Labels = append(issue.Labels, models.Label{ID: labelID})
+		}
+	}
+	if len(fieldValues) > 0 {
+		issue.CustomFields = make(models.FieldValues, len(current.CustomFields)+len(fieldValues))
+		for _, src := range []models.FieldValues{current.CustomFields, fieldValues} {
+			for key, value := range src {
+				issue.CustomFields[key] = value
+			}
+		}
+	}
+	if req.Type != nil {
+		issue.Type = *req.Type
+		changed = nil
+	}
+	return h.checkTypeRules(w, r, issue, changed)
+}
+
+func requiresCustomFields(t models.IssueType) bool {
+	for _, f := range t.RequiredFields {
+		if strings.HasPrefix(f, models.CustomFieldPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// existingRequirements drops the required custom fields not in fields
+func existingRequirements(required []string, fields map[string]models.CustomField) []string {
+	var kept []string
+	for _, f := range required {
+		if key, ok := models.ParseFieldKey(f); ok {
+			if _, exists := fields[key]; !exists {
+				continue
+			}
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// validateIssueTypeRequest validates the display and rules of an issue
+// type against the defined custom fields. The name and icon are trimmed and
+// duplicate rules dropped.
+func validateIssueTypeRequest(req *models.IssueTypeRequest, fields map[string]models.CustomField) error {
+	v := validator.New()
+
+	req.Name = strings.TrimSpace(req.Name)
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 50)
+	req.Icon = strings.TrimSpace(req.Icon)
+	v.Required("icon", req.Icon)
+	v.MaxLength("icon", req.Icon, 50)
+	v.HexColor("color", req.Color)
+
+	required := []string{}
+	for i, f := range req.RequiredFields {
+		name := fmt.Sprintf("required_fields[%d]", i)
+		if key, ok := models.ParseFieldKey(f); ok {
+			if _, exists := fields[key]; !exists {
+				v.AddError(name, fmt.Sprintf("unknown custom field %q", key))
+				continue
+			}
+		} else if !contains(models.RequirableFields, f) {
+			v.Add(name, validator.CodeOneOf, fmt.Sprintf("must be one of: %s or cf.<key>", strings.Join(models.RequirableFields, ", ")))
+			continue
+		}
+		if !contains(required, f) {
+			required = append(required, f)
+		}
+	}
+	req.RequiredFields = required
+
+	statuses := []string{}
+	for i, s := range req.Statuses {
+		v.OneOf(fmt.Sprintf("statuses[%d]", i), s, models.ValidStatuses)
+		if !contains(statuses, s) {
+			statuses = append(statuses, s)
+		}
+	}
+	req.Statuses = statuses
+
+	return v.Err()
+}
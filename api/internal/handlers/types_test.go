@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+)
+
+func TestIssueTypes(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	alice := "5b0c1d2e-3f4a-4b5c-8d6e-7f8091a2b3c4"
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES (?, 'Alice')", alice)
+
+	problem := func(w *httptest.ResponseRecorder) (utils.Code, []validator.ValidationError) {
+		var p struct {
+			Code   utils.Code                  `json:"code"`
+			Errors []validator.ValidationError `json:"errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &p)
+		return p.Code, p.Errors
+	}
+	expectInvalid := func(t *testing.T, w *httptest.ResponseRecorder, field string) {
+		t.Helper()
+		code, errs := problem(w)
+		if w.Code != http.StatusBadRequest || code != utils.CodeValidationFailed {
+			t.Fatalf("Expected a 400 validation error, got %d: %s", w.Code, w.Body.String())
+		}
+		for _, e := range errs {
+			if e.Field == field {
+				return
+			}
+		}
+		t.Errorf("Expected an error on %s, got %+v", field, errs)
+	}
+
+	t.Run("List", func(t *testing.T) {
+		w := serve(r, "GET", "/issue-types", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var types []models.IssueType
+		json.Unmarshal(w.Body.Bytes(), &types)
+		if len(types) != 4 || types[0].Key != models.TypeBug || types[0].Icon != "bug" || types[0].Color != "#ef4444" {
+			t.Errorf("Expected the four types starting with bug, got %+v", types)
+		}
+
+		if w := serve(r, "GET", "/issue-types/story", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown type, got %d", w.Code)
+		} else if code, _ := problem(w); code != utils.CodeIssueTypeNotFound {
+			t.Errorf("Expected code %s, got %s", utils.CodeIssueTypeNotFound, code)
+		}
+	})
+
+	t.Run("Default And Invalid Type", func(t *testing.T) {
+		issue := createIssue(t, r, `{"title": "Plain", "status": "Todo", "priority": "Low"}`)
+		if issue.Type != models.TypeTask {
+			t.Errorf("Expected issues to default to task, got %q", issue.Type)
+		}
+		expectInvalid(t, serve(r, "POST", "/issues", `{"title": "Story", "status": "Todo", "priority": "Low", "type": "story"}`), "type")
+		expectInvalid(t, serve(r, "PATCH", "/issues/"+issue.ID, `{"type": "story"}`), "type")
+	})
+
+	t.Run("Required Fields", func(t *testing.T) {
+		w := serve(r, "POST", "/issues", `{"title": "Crash", "status": "Todo", "priority": "High", "type": "bug", "description": "  "}`)
+		expectInvalid(t, w, "description")
+
+		bug := createIssue(t, r, `{"title": "Crash", "status": "Todo", "priority": "High", "type": "bug", "description": "Open the board, click twice"}`)
+		if bug.Type != models.TypeBug {
+			t.Errorf("Expected a bug, got %q", bug.Type)
+		}
+		expectInvalid(t, serve(r, "PATCH", "/issues/"+bug.ID, `{"description": ""}`), "description")
+
+		// Turning a task into a bug checks every rule of the new type
+		task := createIssue(t, r, `{"title": "Vague", "status": "Todo", "priority": "Low"}`)
+		expectInvalid(t, serve(r, "PATCH", "/issues/"+task.ID, `{"type": "bug"}`), "description")
+		if w := serve(r, "PATCH", "/issues/"+task.ID, `{"type": "bug", "description": "Steps"}`); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 with the description, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Update Type", func(t *testing.T) {
+		w := serve(r, "POST", "/fields", `{"key": "severity", "name": "Severity", "type": "select", "options": ["Low", "High"]}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		w = serve(r, "PUT", "/issue-types/epic", `{"name": "Nope", "icon": "", "color": "red", "required_fields": ["title", "cf.missing"], "statuses": ["Later"]}`)
+		for _, field := range []string{"icon", "color", "required_fields[0]", "required_fields[1]", "statuses[0]"} {
+			expectInvalid(t, w, field)
+		}
+		if w := serve(r, "PUT", "/issue-types/story", `{"name": "Story", "icon": "book", "color": "#000000"}`); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown type, got %d", w.Code)
+		}
+
+		w = serve(r, "PUT", "/issue-types/epic", `{"name": " Initiative ", "icon": "flag", "color": "#123456", "required_fields": ["assignee_id", "cf.severity", "assignee_id"], "statuses": ["Backlog", "In Progress", "Done"]}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var epic models.IssueType
+		json.Unmarshal(w.Body.Bytes(), &epic)
+		if epic.Name != "Initiative" || len(epic.RequiredFields) != 2 || len(epic.Statuses) != 3 {
+			t.Errorf("Expected the trimmed, deduplicated type, got %+v", epic)
+		}
+	})
+
+	t.Run("Rules Of Updated Type", func(t *testing.T) {
+		w := serve(r, "POST", "/issues", `{"title": "Roadmap", "status": "Todo", "priority": "Low", "type": "epic", "assignee_id": "`+alice+`", "custom_fields": {"severity": "High"}}`)
+		expectInvalid(t, w, "status")
+
+		w = serve(r, "POST", "/issues", `{"title": "Roadmap", "status": "Backlog", "priority": "Low", "type": "epic"}`)
+		expectInvalid(t, w, "assignee_id")
+		expectInvalid(t, w, "custom_fields.severity")
+
+		epic := createIssue(t, r, `{"title": "Roadmap", "status": "Backlog", "priority": "Low", "type": "epic", "assignee_id": "`+alice+`", "custom_fields": {"severity": "High"}}`)
+		expectInvalid(t, serve(r, "PATCH", "/issues/"+epic.ID+"/move", `{"status": "Todo"}`), "status")
+		expectInvalid(t, serve(r, "PATCH", "/issues/"+epic.ID, `{"custom_fields": {"severity": null}}`), "custom_fields.severity")
+		if w := serve(r, "PATCH", "/issues/"+epic.ID+"/move", `{"status": "In Progress"}`); w.Code != http.StatusOK {
+			t.Errorf("Expected an allowed move to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := serve(r, "PATCH", "/issues/"+epic.ID, `{"title": "Roadmap 2025"}`); w.Code != http.StatusOK {
+			t.Errorf("Expected an unrelated change to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		var issues []models.Issue
+		w := serve(r, "GET", "/issues?type=bug&type=epic", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		json.Unmarshal(w.Body.Bytes(), &issues)
+		if len(issues) != 3 {
+			t.Fatalf("Expected the 2 bugs and the epic, got %+v", issues)
+		}
+		for _, issue := range issues {
+			if issue.Type != models.TypeBug && issue.Type != models.TypeEpic {
+				t.Errorf("Expected only bugs and epics, got %q", issue.Type)
+			}
+		}
+	})
+}
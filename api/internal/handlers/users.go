@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+
+	"github.com/google/uuid"
+)
+
+// CreateUser godoc
+// @Summary Create a user
+// @Description Create a user, optionally with a local account (email and password) and a global role.
+// @Description Requires the admin role.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body models.CreateUserRequest true "User details"
+// @Param Idempotency-Key header string false "Replays the first response to retries with the same key"
+// @Success 201 {object} models.User
+// @Failure 400 {object} utils.Problem "Bad Request"
+// @Failure 413 {object} utils.Problem "Request body too large"
+// @Failure 403 {object} utils.Problem "Forbidden"
+// @Failure 409 {object} utils.Problem "Email already in use"
+// @Failure 500 {object} utils.Problem "Internal Server Error"
+// @Router /users [post]
+// @Security ApiKeyAuth
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req models.CreateUserRequest
+	if err := utils.DecodeJSON(r.Body, &req); err != nil {
+		slog.Warn("Failed to decode create user request", "error", err)
+		utils.WriteDecodeError(w, r, err)
+		return
+	}
+	if err := validateCreateUserRequest(&req); err != nil {
+		writeValidationError(w, r, err)
+		return
+	}
+
+	var hash string
+	if req.Email != "" {
+		existing, err := h.Repo.GetAccountByEmail(ctx, req.Email)
+		if err != nil {
+			slog.Error("Failed to fetch account", "error", err)
+			utils.WriteProblem(w, r, utils.CodeInternal, "Failed to create user", nil)
+			return
+		}
+		if existing != nil {
+			utils.WriteProblem(w, r, utils.CodeEmailTaken, "", nil)
+			return
+		}
+		if hash, err = auth.HashPassword(req.Password); err != nil {
+			slog.Error("Failed to hash password", "error", err)
+			utils.WriteProblem(w, r, utils.CodeInternal, "Failed to create user", nil)
+			return
+		}
+	}
+
+	user := models.User{ID: uuid.New().String(), Name: req.Name, AvatarURL: req.AvatarURL}
+	if err := h.Repo.CreateUser(ctx, user); err != nil {
+		writeStoreError(w, r, err, utils.CodeUserNotFound, "Failed to create user")
+		return
+	}
+	if hash != "" {
+		if err := h.Repo.CreateAccount(ctx, models.Account{UserID: user.ID, Email: req.Email, PasswordHash: hash}); err != nil {
+			if errors.Is(err, database.ErrConflict) {
+				// Taken by a concurrent request since the check above
+				utils.WriteProblem(w, r, utils.CodeEmailTaken, "", nil)
+				return
+			}
+			slog.Error("Failed to create account", "user_id", user.ID, "error", err)
+			utils.WriteProblem(w, r, utils.CodeInternal, "Failed to create user", nil)
+			return
+		}
+	}
+	if req.Role != "" {
+		if err := h.Repo.SetUserRole(ctx, models.RoleAssignment{UserID: user.ID, Role: req.Role}); err != nil {
+			slog.Error("Failed to set user role", "user_id", user.ID, "error", err)
+			utils.WriteProblem(w, r, utils.CodeInternal, "Failed to create user", nil)
+			return
+		}
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, user)
+}
+
+func validateCreateUserRequest(req *models.CreateUserRequest) error {
+	v := validator.New()
+
+	req.Name = strings.TrimSpace(req.Name)
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, 200)
+	if req.AvatarURL != "" {
+		v.URL("avatar_url", req.AvatarURL)
+	}
+
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email != "" && !strings.Contains(req.Email, "@") {
+		v.AddError("email", "must be an email address")
+	}
+	if req.Email != "" {
+		v.MinLength("password", req.Password, auth.MinPasswordLength)
+	}
+	if req.Email == "" && req.Password != "" {
+		v.Add("email", validator.CodeRequired, "is required with a password")
+	}
+
+	if req.Role != "" {
+		if _, err := models.ParseRole(string(req.Role)); err != nil {
+			v.Add("role", validator.CodeOneOf, err.Error())
+		}
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestCreateUser(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+	ctx := context.Background()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/users", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("With Account And Role", func(t *testing.T) {
+		w := post(`{"name": "Alice", "email": "alice@example.com", "password": "correct horse", "role": "member"}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var user models.User
+		json.Unmarshal(w.Body.Bytes(), &user)
+		if user.ID == "" || user.Name != "Alice" {
+			t.Fatalf("Unexpected user: %+v", user)
+		}
+
+		account, _ := repo.GetAccountByEmail(ctx, "alice@example.com")
+		if account == nil || account.UserID != user.ID {
+			t.Fatalf("Expected an account for %s, got %+v", user.ID, account)
+		}
+		if ok, _ := auth.CheckPassword(account.PasswordHash, "correct horse"); !ok {
+			t.Error("Expected the stored hash to match the password")
+		}
+		if role, _ := repo.GetUserRole(ctx, user.ID, ""); role != models.RoleMember {
+			t.Errorf("Expected role member, got %q", role)
+		}
+	})
+
+	t.Run("Without Account", func(t *testing.T) {
+		if w := post(`{"name": "Bot"}`); w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", w.Code)
+		}
+	})
+
+	t.Run("Email Already In Use", func(t *testing.T) {
+		w := post(`{"name": "Alice 2", "email": "ALICE@example.com", "password": "another one"}`)
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", w.Code)
+		}
+	})
+
+	for _, body := range []string{
+		`{"name": ""}`,
+		`{"name": "Carol", "email": "carol", "password": "long enough"}`,
+		`{"name": "Carol", "email": "carol@example.com", "password": "short"}`,
+		`{"name": "Carol", "password": "no email here"}`,
+		`{"name": "Carol", "role": "owner"}`,
+	} {
+		if w := post(body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, w.Code)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/abhir9/issue-board/api/internal/validator"
+)
+
+// writeValidationError responds 400 with the field-level errors in err, or
+// 500 if err is a failure to look up referenced records
+func writeValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	errs, ok := validator.As(err)
+	if !ok {
+		slog.Error("Failed to validate request", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to validate request", nil)
+		return
+	}
+	utils.WriteProblem(w, r, utils.CodeValidationFailed, "", map[string]interface{}{"errors": errs})
+}
+
+// writeQueryError responds 400 for query parameters that failed to parse.
+// Errors not already tied to a parameter are reported against field.
+func writeQueryError(w http.ResponseWriter, r *http.Request, field string, err error) {
+	errs, ok := validator.As(err)
+	if !ok {
+		errs = validator.Field(field, validator.CodeInvalid, err.Error())
+	}
+	utils.WriteProblem(w, r, utils.CodeInvalidQuery, "", map[string]interface{}{"errors": errs})
+}
+
+// writeReferenceError responds 422 naming the unknown IDs in err, which is
+// either a *database.ReferenceError from the store or not_found field errors
+// from a lookup such as missingUsers
+func writeReferenceError(w http.ResponseWriter, r *http.Request, err error) {
+	var refErr *database.ReferenceError
+	if errors.As(err, &refErr) {
+		err = referenceErrors(refErr)
+	}
+	errs, ok := validator.As(err)
+	if !ok {
+		slog.Error("Failed to check references", "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to check references", nil)
+		return
+	}
+	utils.WriteProblem(w, r, utils.CodeUnknownReference, "", map[string]interface{}{"errors": errs})
+}
+
+// isReferenceError reports whether a store error is a *database.ReferenceError
+func isReferenceError(err error) bool {
+	var refErr *database.ReferenceError
+	return errors.As(err, &refErr)
+}
+
+// referenceErrors converts a store's reference error to not_found field
+// errors
+func referenceErrors(e *database.ReferenceError) validator.ValidationErrors {
+	v := validator.New()
+	if e.AssigneeID != "" {
+		v.Add("assignee_id", validator.CodeNotFound, "unknown ID: "+e.AssigneeID)
+	}
+	if len(e.LabelIDs) > 0 {
+		v.Add("label_ids", validator.CodeNotFound, fmt.Sprintf("unknown ID: %s", strings.Join(e.LabelIDs, ", ")))
+	}
+	if e.ParentID != "" {
+		v.Add("parent_id", validator.CodeNotFound, "unknown ID: "+e.ParentID)
+	}
+	if v.Valid() {
+		// A reference vanished while writing and could not be identified
+		v.Add("", validator.CodeNotFound, "a referenced assignee, label or parent issue no longer exists")
+	}
+	return v.Errors()
+}
+
+// missingUsers is a validator.LookupFunc for user IDs
+func (h *Handler) missingUsers(ctx context.Context, ids []string) ([]string, error) {
+	var missing []string
+	for _, id := range ids {
+		user, err := h.Repo.GetUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
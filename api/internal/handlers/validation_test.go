@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/database"
+	"github.com/abhir9/issue-board/api/internal/validator"
+)
+
+func TestValidationErrors(t *testing.T) {
+	repo := setupTestDB(t)
+	r := setupRouter(repo)
+
+	const (
+		userID  = "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a"
+		labelID = "9b2d4e61-7c3a-4f85-b1e0-5a6c7d8e9f01"
+		unknown = "00000000-0000-4000-8000-000000000000"
+	)
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES (?, 'Test User')", userID)
+	repo.DB.Exec("INSERT INTO labels (id, name, color) VALUES (?, 'Bug', '#FF0000')", labelID)
+
+	// send returns the response code and the field-level errors it carries
+	send := func(method, url, body string) (int, map[string]validator.ValidationError) {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp struct {
+			Errors []validator.ValidationError `json:"errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		byField := make(map[string]validator.ValidationError)
+		for _, e := range resp.Errors {
+			byField[e.Field] = e
+		}
+		return w.Code, byField
+	}
+
+	t.Run("Every Field Is Reported", func(t *testing.T) {
+		code, errs := send("POST", "/issues", `{"title": "", "status": "Later", "priority": "Low", "assignee_id": "user1"}`)
+		if code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", code)
+		}
+		for field, want := range map[string]string{
+			"title":       validator.CodeRequired,
+			"status":      validator.CodeOneOf,
+			"assignee_id": validator.CodeInvalidUUID,
+		} {
+			if errs[field].Code != want {
+				t.Errorf("Expected %s on %s, got %+v", want, field, errs[field])
+			}
+		}
+		if _, ok := errs["priority"]; ok {
+			t.Errorf("Expected no error on a valid priority, got %+v", errs["priority"])
+		}
+	})
+
+	t.Run("Unknown References", func(t *testing.T) {
+		body := `{"title": "Issue", "status": "Todo", "priority": "Low", "assignee_id": "` + unknown + `", "label_ids": ["` + labelID + `", "` + unknown + `"]}`
+		code, errs := send("POST", "/issues", body)
+		if code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected status 422, got %d", code)
+		}
+		if errs["assignee_id"].Code != validator.CodeNotFound {
+			t.Errorf("Expected not_found on assignee_id, got %+v", errs["assignee_id"])
+		}
+		if errs["label_ids"].Code != validator.CodeNotFound || errs["label_ids"].Message != "unknown ID: "+unknown {
+			t.Errorf("Expected not_found naming the unknown label, got %+v", errs["label_ids"])
+		}
+	})
+
+	t.Run("Unknown References On Update", func(t *testing.T) {
+		repo.DB.Exec("INSERT INTO issues (id, title, description, status, priority) VALUES ('existing', 'Existing', '', 'Todo', 'Low')")
+
+		code, errs := send("PATCH", "/issues/existing", `{"title": "Renamed", "label_ids": ["`+unknown+`"]}`)
+		if code != http.StatusUnprocessableEntity || errs["label_ids"].Message != "unknown ID: "+unknown {
+			t.Fatalf("Expected 422 naming the unknown label, got %d %+v", code, errs)
+		}
+		var title string
+		repo.DB.QueryRow("SELECT title FROM issues WHERE id = 'existing'").Scan(&title)
+		if title != "Existing" {
+			t.Errorf("Expected the issue to be unchanged, got title %q", title)
+		}
+	})
+
+	t.Run("Malformed Label ID", func(t *testing.T) {
+		code, errs := send("POST", "/issues", `{"title": "Issue", "status": "Todo", "priority": "Low", "label_ids": ["`+labelID+`", "bug"]}`)
+		if code != http.StatusBadRequest || errs["label_ids[1]"].Code != validator.CodeInvalidUUID {
+			t.Errorf("Expected invalid_uuid on label_ids[1], got %d %+v", code, errs)
+		}
+	})
+
+	t.Run("Known References", func(t *testing.T) {
+		body := `{"title": "Issue", "status": "Todo", "priority": "Low", "assignee_id": "` + userID + `", "label_ids": ["` + labelID + `"]}`
+		if code, errs := send("POST", "/issues", body); code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d %+v", code, errs)
+		}
+	})
+
+	t.Run("Create User", func(t *testing.T) {
+		code, errs := send("POST", "/users", `{"name": "Bob", "avatar_url": "javascript:alert(1)", "email": "bob@example.com", "password": "short"}`)
+		if code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", code)
+		}
+		if errs["avatar_url"].Code != validator.CodeInvalidURL || errs["password"].Code != validator.CodeTooShort {
+			t.Errorf("Expected avatar_url and password errors, got %+v", errs)
+		}
+	})
+
+	t.Run("Query Parameters", func(t *testing.T) {
+		code, errs := send("GET", "/stats/cycle-time?from=2024-03-10&to=2024-03-01", "")
+		if code != http.StatusBadRequest || errs["from"].Code != validator.CodeInvalidRange {
+			t.Errorf("Expected invalid_range on from, got %d %+v", code, errs)
+		}
+
+		code, errs = send("GET", "/issues?limit=abc", "")
+		if code != http.StatusBadRequest || errs["limit"].Field != "limit" {
+			t.Errorf("Expected an error on limit, got %d %+v", code, errs)
+		}
+	})
+}
+
+func TestWriteReferenceError(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeReferenceError(w, httptest.NewRequest("POST", "/issues", nil), &database.ReferenceError{AssigneeID: "ghost", LabelIDs: []string{"a", "b"}})
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", w.Code)
+	}
+	var resp struct {
+		Errors []validator.ValidationError `json:"errors"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	want := []validator.ValidationError{
+		{Field: "assignee_id", Code: validator.CodeNotFound, Message: "unknown ID: ghost"},
+		{Field: "label_ids", Code: validator.CodeNotFound, Message: "unknown ID: a, b"},
+	}
+	if len(resp.Errors) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, resp.Errors)
+	}
+	for i := range want {
+		if resp.Errors[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], resp.Errors[i])
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// wipWarningHeader lists the WIP limits a change exceeded when not in strict mode
+const wipWarningHeader = "X-WIP-Warning"
+
+// columnCountsHeader carries the column counts of a plain array issue list
+const columnCountsHeader = "X-Column-Counts"
+
+// checkWIP warns about the WIP limits of the column an issue ends up in.
+// current is the issue before the change, nil on create. Over a limit it sets
+// the warning header. Strict limits are enforced by the store inside the
+// write, which fails with a *database.WIPError, so they are not checked here.
+func (h *Handler) checkWIP(w http.ResponseWriter, r *http.Request, current *models.Issue, status string, assigneeID *string) bool {
+	if !h.WIP.Enabled() || h.WIP.Strict {
+		return true
+	}
+
+	violations, err := h.WIP.Violations(current, status, assigneeID, func(status string, assigneeID *string) (int, error) {
+		f := models.IssueFilter{Status: []string{status}}
+		if assigneeID != nil {
+			f.AssigneeID = *assigneeID
+		}
+		return h.countIssues(r.Context(), f)
+	})
+	if err != nil {
+		slog.Error("Failed to check WIP limits", "status", status, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to check WIP limits", nil)
+		return false
+	}
+	if len(violations) == 0 {
+		return true
+	}
+
+	warnings := make([]string, len(violations))
+	for i, v := range violations {
+		if v.AssigneeID != nil {
+			warnings[i] = fmt.Sprintf("%q limit of %d per assignee exceeded for %s (%d issues)", v.Status, v.Limit, *v.AssigneeID, v.Count)
+		} else {
+			warnings[i] = fmt.Sprintf("%q limit of %d exceeded (%d issues)", v.Status, v.Limit, v.Count)
+		}
+	}
+	w.Header().Set(wipWarningHeader, strings.Join(warnings, "; "))
+	return true
+}
+
+func (h *Handler) countIssues(ctx context.Context, f models.IssueFilter) (int, error) {
+	counts, err := h.Repo.CountIssues(ctx, f, nil)
+	if err != nil || len(counts) == 0 {
+		return 0, err
+	}
+	return counts[0].Count, nil
+}
+
+// columnCounts counts the issues matching f per status column, with the
+// configured limits
+func (h *Handler) columnCounts(ctx context.Context, f models.IssueFilter) ([]models.ColumnCount, error) {
+	counts, err := h.Repo.CountIssues(ctx, f, []string{models.GroupByStatus})
+	if err != nil {
+		return nil, err
+	}
+	byStatus := make(map[string]int, len(counts))
+	for _, c := range counts {
+		if c.Keys[0] != nil {
+			byStatus[*c.Keys[0]] = c.Count
+		}
+	}
+
+	statuses := models.ValidStatuses
+	if len(f.Status) > 0 {
+		statuses = f.Status
+	}
+	columns := make([]models.ColumnCount, len(statuses))
+	for i, s := range statuses {
+		columns[i] = models.ColumnCount{Status: s, Count: byStatus[s]}
+		if limit, ok := h.WIP.Status[s]; ok {
+			columns[i].Limit = &limit
+		}
+		if limit, ok := h.WIP.PerAssignee[s]; ok {
+			columns[i].PerAssigneeLimit = &limit
+		}
+	}
+	return columns, nil
+}
+
+// checkIssueWIP warns about the WIP limits for a change to an existing issue.
+// Unknown issues pass so the update reports them as usual.
+func (h *Handler) checkIssueWIP(w http.ResponseWriter, r *http.Request, id string, status, assigneeID *string) bool {
+	if !h.WIP.Enabled() || h.WIP.Strict {
+		return true
+	}
+
+	current, err := h.Repo.GetIssue(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to fetch issue", "issue_id", id, "error", err)
+		utils.WriteProblem(w, r, utils.CodeInternal, "Failed to check WIP limits", nil)
+		return false
+	}
+	if current == nil {
+		return true
+	}
+
+	target := current.Status
+	if status != nil {
+		target = *status
+	}
+	assignee := current.AssigneeID
+	if assigneeID != nil {
+		assignee = assigneeID
+	}
+	return h.checkWIP(w, r, current, target, assignee)
+}
+
+// setColumnCountsHeader sets the column counts header to columns as JSON
+func setColumnCountsHeader(w http.ResponseWriter, columns []models.ColumnCount) error {
+	b, err := json.Marshal(columns)
+	if err != nil {
+		return err
+	}
+	w.Header().Set(columnCountsHeader, string(b))
+	return nil
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestWIPLimits(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+	repo.DB.Exec("INSERT INTO users (id, name) VALUES ('6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a', 'Test User')")
+
+	now := time.Now()
+	assigneeID := "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a"
+	repo.CreateIssue(ctx, models.Issue{ID: "1", Title: "Issue 1", Status: "In Progress", Priority: "Low", AssigneeID: &assigneeID, CreatedAt: now, UpdatedAt: now, OrderIndex: 1})
+	repo.CreateIssue(ctx, models.Issue{ID: "2", Title: "Issue 2", Status: "In Progress", Priority: "Low", CreatedAt: now, UpdatedAt: now, OrderIndex: 2})
+	repo.CreateIssue(ctx, models.Issue{ID: "3", Title: "Issue 3", Status: "Todo", Priority: "Low", CreatedAt: now, UpdatedAt: now, OrderIndex: 1})
+
+	limits := models.WIPLimits{
+		Status:      map[string]int{"In Progress": 2},
+		PerAssignee: map[string]int{"Todo": 1},
+	}
+
+	// strict returns a handler whose store enforces limits in strict mode
+	strict := func(t *testing.T, l models.WIPLimits) *Handler {
+		l.Strict = true
+		repo.SetWIPLimits(l)
+		t.Cleanup(func() { repo.SetWIPLimits(models.WIPLimits{}) })
+		return &Handler{Repo: repo, WIP: l}
+	}
+
+	send := func(h *Handler, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupHandlerRouter(h).ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Warns When Moving Into A Full Column", func(t *testing.T) {
+		h := &Handler{Repo: repo, WIP: limits}
+		w := send(h, "PATCH", "/issues/3/move", `{"status": "In Progress"}`)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		warning := w.Header().Get("X-WIP-Warning")
+		if !strings.Contains(warning, `"In Progress" limit of 2 exceeded (3 issues)`) {
+			t.Errorf("Expected WIP warning, got %q", warning)
+		}
+
+		repo.UpdateIssue(ctx, "3", map[string]interface{}{"status": "Todo"})
+	})
+
+	t.Run("Reordering Within A Full Column", func(t *testing.T) {
+		h := strict(t, models.WIPLimits{Status: limits.Status})
+		w := send(h, "PATCH", "/issues/2/move", `{"status": "In Progress", "order_index": 0}`)
+
+		if w.Code != http.StatusOK || w.Header().Get("X-WIP-Warning") != "" {
+			t.Errorf("Expected move without warning, got %d %q", w.Code, w.Header().Get("X-WIP-Warning"))
+		}
+	})
+
+	t.Run("Strict Mode Rejects", func(t *testing.T) {
+		h := strict(t, models.WIPLimits{Status: limits.Status})
+		for _, tc := range []struct{ method, url, body string }{
+			{"PATCH", "/issues/3/move", `{"status": "In Progress"}`},
+			{"PATCH", "/issues/3", `{"status": "In Progress"}`},
+			{"POST", "/issues", `{"title": "New", "status": "In Progress", "priority": "Low"}`},
+		} {
+			w := send(h, tc.method, tc.url, tc.body)
+			if w.Code != http.StatusConflict {
+				t.Fatalf("%s %s: expected status 409, got %d. Body: %s", tc.method, tc.url, w.Code, w.Body.String())
+			}
+
+			var problem utils.Problem
+			json.Unmarshal(w.Body.Bytes(), &problem)
+			violations, _ := problem.Extensions["violations"].([]interface{})
+			if problem.Code != utils.CodeWIPLimitExceeded || len(violations) != 1 {
+				t.Errorf("Expected 1 violation, got %s", w.Body.String())
+			}
+		}
+
+		issue, _ := repo.GetIssue(ctx, "3")
+		if issue.Status != "Todo" {
+			t.Errorf("Expected issue to stay in Todo, got %s", issue.Status)
+		}
+	})
+
+	t.Run("Per Assignee Limit", func(t *testing.T) {
+		h := strict(t, models.WIPLimits{PerAssignee: limits.PerAssignee})
+
+		w := send(h, "PATCH", "/issues/3", `{"assignee_id": "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected first assignment to pass, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		w = send(h, "POST", "/issues", `{"title": "New", "status": "Todo", "priority": "Low", "assignee_id": "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a"}`)
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for second Todo issue of the assignee, got %d", w.Code)
+		}
+		w = send(h, "POST", "/issues", `{"title": "New", "status": "Todo", "priority": "Low"}`)
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected unassigned issue to pass, got %d", w.Code)
+		}
+	})
+
+	t.Run("Column Counts", func(t *testing.T) {
+		h := &Handler{Repo: repo, WIP: limits}
+		w := send(h, "GET", "/issues?limit=1", "")
+
+		var resp struct {
+			Columns []models.ColumnCount `json:"columns"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if len(resp.Columns) != len(models.ValidStatuses) {
+			t.Fatalf("Expected a column per status, got %+v", resp.Columns)
+		}
+		for _, c := range resp.Columns {
+			switch c.Status {
+			case "In Progress":
+				if c.Count != 2 || c.Limit == nil || *c.Limit != 2 {
+					t.Errorf("Expected In Progress 2/2, got %+v", c)
+				}
+			case "Todo":
+				if c.Count != 2 || c.Limit != nil || c.PerAssigneeLimit == nil || *c.PerAssigneeLimit != 1 {
+					t.Errorf("Expected Todo with 2 issues and a per-assignee limit, got %+v", c)
+				}
+			}
+		}
+	})
+
+	t.Run("Column Counts Of A Plain Array", func(t *testing.T) {
+		h := &Handler{Repo: repo, WIP: limits}
+		w := send(h, "GET", "/issues?status=In%20Progress", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var columns []models.ColumnCount
+		if err := json.Unmarshal([]byte(w.Header().Get("X-Column-Counts")), &columns); err != nil {
+			t.Fatalf("Expected column counts header, got %q: %v", w.Header().Get("X-Column-Counts"), err)
+		}
+		if len(columns) != 1 || columns[0].Status != "In Progress" || columns[0].Count != 2 || columns[0].Limit == nil || *columns[0].Limit != 2 {
+			t.Errorf("Expected In Progress 2/2, got %+v", columns)
+		}
+	})
+}
@@ -1,24 +1,20 @@
 package middleware
 
 import (
-	"encoding/json"
 	"net/http"
+
+	"github.com/abhir9/issue-board/api/internal/models"
 )
 
-// APIKeyAuth creates a middleware that checks for a valid API key in the header
+// APIKeyAuth creates a middleware that checks for a valid API key in the header.
+// Requests with the key act as an admin.
 func APIKeyAuth(validAPIKey string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			apiKey := r.Header.Get("X-API-Key")
-			if apiKey == "" || apiKey != validAPIKey {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				json.NewEncoder(w).Encode(map[string]string{
-					"error": "Unauthorized: Invalid or missing API key",
-				})
-				return
-			}
-			next.ServeHTTP(w, r)
-		})
-	}
+	return APIKeyAuthAs(validAPIKey, models.RoleAdmin)
+}
+
+// APIKeyAuthAs is APIKeyAuth with the role granted to holders of the key. It
+// is Authenticate with only the API key accepted, so a bad or missing key
+// gets the same 401 problem.
+func APIKeyAuthAs(validAPIKey string, role models.Role) func(http.Handler) http.Handler {
+	return Authenticate(APIKeyAuthenticator{Key: validAPIKey, Role: role})
 }
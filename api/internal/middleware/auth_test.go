@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
 )
 
 func TestAPIKeyAuth(t *testing.T) {
@@ -47,11 +50,11 @@ func TestAPIKeyAuth(t *testing.T) {
 			t.Errorf("Expected status 401, got %d", w.Code)
 		}
 
-		var response map[string]string
-		json.Unmarshal(w.Body.Bytes(), &response)
+		var problem utils.Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
 
-		if response["error"] == "" {
-			t.Error("Expected error message in response")
+		if problem.Code != utils.CodeUnauthenticated {
+			t.Errorf("Expected code %s, got %q", utils.CodeUnauthenticated, problem.Code)
 		}
 	})
 
@@ -98,8 +101,23 @@ func TestAPIKeyAuth(t *testing.T) {
 		handler.ServeHTTP(w, req)
 
 		contentType := w.Header().Get("Content-Type")
-		if contentType != "application/json" {
-			t.Errorf("Expected Content-Type 'application/json', got '%s'", contentType)
+		if contentType != utils.ProblemContentType {
+			t.Errorf("Expected Content-Type '%s', got '%s'", utils.ProblemContentType, contentType)
 		}
 	})
 }
+
+func TestAPIKeyAuthAs(t *testing.T) {
+	var got *Principal
+	handler := APIKeyAuthAs("key", models.RoleViewer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = PrincipalFrom(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-API-Key", "key")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got == nil || got.Role != models.RoleViewer || got.UserID != "" {
+		t.Errorf("Expected viewer principal, got %+v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+var (
+	// ErrInvalidCredentials is returned by authenticators for credentials they
+	// recognise but reject, e.g. a wrong API key or an expired session
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrInvalidCSRF is returned for cookie-authenticated unsafe requests
+	// without the session's CSRF token
+	ErrInvalidCSRF = errors.New("invalid CSRF token")
+)
+
+// Authenticator identifies the caller of a request. It returns nil, nil when
+// the request carries no credentials of its kind.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Authenticate creates a middleware that identifies the caller with the first
+// authenticator that recognises the request's credentials. Requests without
+// valid credentials get a 401.
+func Authenticate(authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, a := range authenticators {
+				p, err := a.Authenticate(r)
+				switch {
+				case errors.Is(err, ErrInvalidCSRF):
+					utils.WriteProblem(w, r, utils.CodeCSRFTokenInvalid, "", nil)
+					return
+				case errors.Is(err, ErrInvalidCredentials):
+					utils.WriteProblem(w, r, utils.CodeUnauthenticated, "", nil)
+					return
+				case err != nil:
+					slog.Error("Failed to authenticate request", "error", err)
+					utils.WriteProblem(w, r, utils.CodeInternal, "Failed to authenticate request", nil)
+					return
+				case p != nil:
+					next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+					return
+				}
+			}
+			utils.WriteProblem(w, r, utils.CodeUnauthenticated, "", nil)
+		})
+	}
+}
+
+// APIKeyAuthenticator accepts the shared X-API-Key
+type APIKeyAuthenticator struct {
+	Key  string
+	Role models.Role
+}
+
+func (a APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		return nil, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(key), []byte(a.Key)) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{Role: a.Role}, nil
+}
+
+// JWTAuthenticator accepts "Authorization: Bearer" JWTs from service
+// callers. The sub claim names the user whose role assignments apply, Role is
+// used for subjects without one, and the scope (space-separated) or scp (array)
+// claim limits the token to the listed permissions.
+type JWTAuthenticator struct {
+	Verifier *auth.Verifier
+	Role     models.Role
+}
+
+func (a JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, nil
+	}
+
+	claims, err := a.Verifier.Verify(r.Context(), strings.TrimSpace(token))
+	if errors.Is(err, auth.ErrInvalidToken) {
+		slog.Warn("Rejected bearer token", "error", err)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub := claims.String("sub")
+	if sub == "" {
+		slog.Warn("Rejected bearer token", "error", "missing sub")
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{UserID: sub, Role: a.Role, Scopes: tokenScopes(claims)}, nil
+}
+
+// tokenScopes returns the permissions granted by a token's scope or scp
+// claim, or nil if it has neither
+func tokenScopes(c auth.Claims) []models.Permission {
+	var names []string
+	switch {
+	case c["scope"] != nil:
+		names = strings.Fields(c.String("scope"))
+	case c["scp"] != nil:
+		list, _ := c["scp"].([]interface{})
+		for _, s := range list {
+			if name, ok := s.(string); ok {
+				names = append(names, name)
+			}
+		}
+	default:
+		return nil
+	}
+
+	scopes := make([]models.Permission, 0, len(names))
+	for _, name := range names {
+		scopes = append(scopes, models.Permission(name))
+	}
+	return scopes
+}
+
+// Session cookie and CSRF header names
+const (
+	SessionCookieName = "session"
+	CSRFHeaderName    = "X-CSRF-Token"
+)
+
+// SessionStore looks up browser sessions by the hash of their token
+type SessionStore interface {
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+}
+
+// SessionAuthenticator accepts the session cookie set at login. Unsafe
+// methods must also send the session's CSRF token in X-CSRF-Token.
+type SessionAuthenticator struct {
+	Sessions    SessionStore
+	DefaultRole models.Role // role of users without a role assignment
+}
+
+func (a SessionAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil, nil
+	}
+
+	s, err := a.Sessions.GetSession(r.Context(), auth.HashToken(cookie.Value))
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || !time.Now().Before(s.ExpiresAt) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if !safeMethod(r.Method) && subtle.ConstantTimeCompare([]byte(r.Header.Get(CSRFHeaderName)), []byte(s.CSRFToken)) != 1 {
+		return nil, ErrInvalidCSRF
+	}
+	return &Principal{UserID: s.UserID, Role: a.DefaultRole}, nil
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+type fakeSessions map[string]models.Session
+
+func (f fakeSessions) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	s, ok := f[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	sessions := fakeSessions{
+		auth.HashToken("live"):    {UserID: "alice", CSRFToken: "csrf", ExpiresAt: time.Now().Add(time.Hour)},
+		auth.HashToken("expired"): {UserID: "alice", CSRFToken: "csrf", ExpiresAt: time.Now().Add(-time.Hour)},
+	}
+
+	var got *Principal
+	handler := Authenticate(
+		APIKeyAuthenticator{Key: "key", Role: models.RoleAdmin},
+		SessionAuthenticator{Sessions: sessions, DefaultRole: models.RoleViewer},
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = PrincipalFrom(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range []struct {
+		name    string
+		method  string
+		apiKey  string
+		cookie  string
+		csrf    string
+		want    int
+		wantWho string
+	}{
+		{name: "API key", method: "DELETE", apiKey: "key", want: http.StatusOK},
+		{name: "Wrong API key", method: "GET", apiKey: "nope", cookie: "live", want: http.StatusUnauthorized},
+		{name: "Session read", method: "GET", cookie: "live", want: http.StatusOK, wantWho: "alice"},
+		{name: "Session write with CSRF token", method: "PATCH", cookie: "live", csrf: "csrf", want: http.StatusOK, wantWho: "alice"},
+		{name: "Session write without CSRF token", method: "PATCH", cookie: "live", want: http.StatusForbidden},
+		{name: "Session write with wrong CSRF token", method: "POST", cookie: "live", csrf: "other", want: http.StatusForbidden},
+		{name: "Expired session", method: "GET", cookie: "expired", want: http.StatusUnauthorized},
+		{name: "Unknown session", method: "GET", cookie: "forged", want: http.StatusUnauthorized},
+		{name: "No credentials", method: "GET", want: http.StatusUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: tt.cookie})
+			}
+			if tt.csrf != "" {
+				req.Header.Set(CSRFHeaderName, tt.csrf)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d", tt.want, w.Code)
+			}
+			if tt.want == http.StatusOK && (got == nil || got.UserID != tt.wantWho) {
+				t.Errorf("Expected principal %q, got %+v", tt.wantWho, got)
+			}
+		})
+	}
+}
+
+// hs256 builds a compact JWT signed with secret
+func hs256(secret string, claims auth.Claims) string {
+	b64 := base64.RawURLEncoding
+	payload, _ := json.Marshal(claims)
+	input := b64.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + b64.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + b64.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	a := JWTAuthenticator{
+		Verifier: &auth.Verifier{Keys: auth.Secret(secret), Issuer: "https://auth.example.com", Audience: "issue-board"},
+		Role:     models.RoleViewer,
+	}
+	claims := func(overrides auth.Claims) auth.Claims {
+		c := auth.Claims{
+			"iss": "https://auth.example.com",
+			"aud": "issue-board",
+			"sub": "svc-importer",
+			"exp": float64(time.Now().Add(time.Hour).Unix()),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	authenticate := func(header string) (*Principal, error) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		return a.Authenticate(req)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		claims     auth.Claims
+		wantScopes []models.Permission
+	}{
+		{name: "No Scope Claim", claims: claims(nil)},
+		{name: "Scope String", claims: claims(auth.Claims{"scope": "issues:read issues:write"}),
+			wantScopes: []models.Permission{models.PermIssuesRead, models.PermIssuesWrite}},
+		{name: "Scp Array", claims: claims(auth.Claims{"scp": []interface{}{"stats:read"}}),
+			wantScopes: []models.Permission{models.PermStatsRead}},
+		{name: "Empty Scope", claims: claims(auth.Claims{"scope": ""}), wantScopes: []models.Permission{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := authenticate("Bearer " + hs256(secret, tt.claims))
+			if err != nil || p == nil {
+				t.Fatalf("Expected a principal, got %v, %v", p, err)
+			}
+			if p.UserID != "svc-importer" || p.Role != models.RoleViewer {
+				t.Errorf("Unexpected principal: %+v", p)
+			}
+			if !reflect.DeepEqual(p.Scopes, tt.wantScopes) {
+				t.Errorf("Expected scopes %v, got %v", tt.wantScopes, p.Scopes)
+			}
+		})
+	}
+
+	t.Run("Rejected Tokens", func(t *testing.T) {
+		for name, token := range map[string]string{
+			"expired":        hs256(secret, claims(auth.Claims{"exp": float64(time.Now().Add(-time.Hour).Unix())})),
+			"not yet valid":  hs256(secret, claims(auth.Claims{"nbf": float64(time.Now().Add(time.Hour).Unix())})),
+			"wrong audience": hs256(secret, claims(auth.Claims{"aud": "other"})),
+			"wrong issuer":   hs256(secret, claims(auth.Claims{"iss": "https://evil.example.com"})),
+			"wrong secret":   hs256("fedcba9876543210fedcba9876543210", claims(nil)),
+			"missing sub":    hs256(secret, claims(auth.Claims{"sub": nil})),
+		} {
+			if _, err := authenticate("Bearer " + token); err != ErrInvalidCredentials {
+				t.Errorf("%s: expected ErrInvalidCredentials, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Other Schemes", func(t *testing.T) {
+		for _, header := range []string{"", "Basic dXNlcjpwYXNz", "Bearer "} {
+			if p, err := authenticate(header); p != nil || err != nil {
+				t.Errorf("%q: expected no principal and no error, got %v, %v", header, p, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// MaxBodySize creates a middleware that caps request bodies at limit bytes.
+// Requests declaring a larger Content-Length get a 413 straight away; bodies
+// that turn out larger fail to read with *http.MaxBytesError, which
+// utils.WriteDecodeError turns into a 413.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				utils.WriteProblem(w, r, utils.CodeBodyTooLarge, fmt.Sprintf("request bodies are limited to %d bytes", limit), map[string]interface{}{"limit_bytes": limit})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
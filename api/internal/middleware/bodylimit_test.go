@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	handler := MaxBodySize(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]string
+		if err := utils.DecodeJSON(r.Body, &v); err != nil {
+			utils.WriteDecodeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("Within Limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader(`{"a": "b"}`)))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("Declared Length Too Large", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/test", strings.NewReader(`{"a": "`+strings.Repeat("b", 32)+`"}`)))
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413, got %d", w.Code)
+		}
+	})
+
+	t.Run("Streamed Body Too Large", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/test", io.MultiReader(strings.NewReader(`{"a": "`+strings.Repeat("b", 32)+`"}`)))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import "net/http"
+
+// CacheControl sets the Cache-Control policy of the routes it wraps.
+// Handlers may still override it.
+func CacheControl(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", policy)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheControl(t *testing.T) {
+	handler := CacheControl("no-store")(CacheControl("private, no-cache")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/issues", nil))
+	if got := w.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("Expected the innermost policy to win, got %q", got)
+	}
+}
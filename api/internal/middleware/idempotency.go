@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/utils"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// IdempotencyKeyHeader is the request header naming a retryable POST
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// Idempotency replays the response to a POST request carrying an
+// Idempotency-Key when the same caller retries it, so a retried create does
+// not create twice. Keys are scoped to the caller (as for rate limiting) and
+// the route, so it should run after RealIP and Authenticate.
+//
+// A key reused with a different body is rejected with 422 and a retry that
+// arrives while the first request is still running with 409. Server errors
+// and 429s are not kept, so those requests can be retried for real.
+type Idempotency struct {
+	TTL     time.Duration // how long responses are replayed; 0 disables the middleware
+	MaxKeys int           // responses kept before the oldest are evicted
+	Now     func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+// idempotencyEntry is a request seen under a key. Until done is set the
+// request is in flight.
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	done        bool
+	status      int
+	header      http.Header
+	body        []byte
+	expires     time.Time
+}
+
+// NewIdempotency creates the middleware keeping responses for ttl
+func NewIdempotency(ttl time.Duration) *Idempotency {
+	return &Idempotency{TTL: ttl}
+}
+
+// Handle is the middleware
+func (m *Idempotency) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" || m.TTL <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.WriteProblem(w, r, utils.CodeInvalidIdempotencyKey, fmt.Sprintf("keys are limited to %d characters", maxIdempotencyKeyLength), nil)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			utils.WriteDecodeError(w, r, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := CallerKey(r) + "|" + r.URL.Path + "|" + key
+		fingerprint := sha256.Sum256(body)
+		if existing := m.begin(scope, fingerprint); existing != nil {
+			replay(w, r, fingerprint, existing)
+			return
+		}
+
+		// Headers set before the handler runs, e.g. by the rate limiter,
+		// describe this request rather than the response and are not kept
+		before := w.Header().Clone()
+		var buf bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&buf)
+
+		completed := false
+		defer func() {
+			if !completed {
+				// The handler panicked; let the client retry
+				m.forget(scope)
+			}
+		}()
+		next.ServeHTTP(ww, r)
+		completed = true
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			m.forget(scope)
+			return
+		}
+		m.finish(scope, status, changedHeaders(before, w.Header()), buf.Bytes())
+	})
+}
+
+// begin records a request in flight under scope. If the scope is already
+// taken it returns a copy of the existing entry instead.
+func (m *Idempotency) begin(scope string, fingerprint [sha256.Size]byte) *idempotencyEntry {
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries == nil {
+		m.entries = make(map[string]*idempotencyEntry)
+	}
+	if e, ok := m.entries[scope]; ok && (!e.done || now.Before(e.expires)) {
+		cp := *e
+		return &cp
+	}
+	m.evict(now)
+	m.entries[scope] = &idempotencyEntry{fingerprint: fingerprint}
+	return nil
+}
+
+// replay answers a request whose key was seen before: with the stored
+// response for an exact retry, otherwise with a problem
+func replay(w http.ResponseWriter, r *http.Request, fingerprint [sha256.Size]byte, existing *idempotencyEntry) {
+	switch {
+	case existing.fingerprint != fingerprint:
+		utils.WriteProblem(w, r, utils.CodeIdempotencyKeyReused, "the key was first used with a different request body", nil)
+	case !existing.done:
+		w.Header().Set("Retry-After", "1")
+		utils.WriteProblem(w, r, utils.CodeIdempotencyInProgress, "a request with this key is still being processed", nil)
+	default:
+		for k, v := range existing.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(existing.status)
+		w.Write(existing.body)
+	}
+}
+
+// finish stores the response of the request in flight under scope
+func (m *Idempotency) finish(scope string, status int, header http.Header, body []byte) {
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[scope]; ok {
+		e.done = true
+		e.status = status
+		e.header = header
+		e.body = body
+		e.expires = now.Add(m.TTL)
+	}
+}
+
+// forget drops the entry under scope so the request can be sent again
+func (m *Idempotency) forget(scope string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, scope)
+}
+
+// evict drops expired responses, at most once a minute unless MaxKeys are
+// held, and then the response closest to expiring if MaxKeys are still held.
+// Requests in flight are kept. Callers hold m.mu.
+func (m *Idempotency) evict(now time.Time) {
+	maxKeys := m.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = defaultMaxKeys
+	}
+	if now.Sub(m.lastSweep) < time.Minute && len(m.entries) < maxKeys {
+		return
+	}
+	m.lastSweep = now
+
+	var oldestKey string
+	var oldest time.Time
+	for key, e := range m.entries {
+		if !e.done {
+			continue
+		}
+		if !now.Before(e.expires) {
+			delete(m.entries, key)
+		} else if oldestKey == "" || e.expires.Before(oldest) {
+			oldestKey, oldest = key, e.expires
+		}
+	}
+	if len(m.entries) >= maxKeys && oldestKey != "" {
+		delete(m.entries, oldestKey)
+	}
+}
+
+func (m *Idempotency) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// changedHeaders returns the headers of after that are new or differ from before
+func changedHeaders(before, after http.Header) http.Header {
+	changed := make(http.Header)
+	for k, v := range after {
+		if !equalValues(before[k], v) {
+			changed[k] = append([]string(nil), v...)
+		}
+	}
+	return changed
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
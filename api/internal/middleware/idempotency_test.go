@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+func TestIdempotency(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	m := NewIdempotency(time.Hour)
+	m.Now = func() time.Time { return now }
+
+	var created int32
+	handler := m.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		n := atomic.AddInt32(&created, 1)
+		w.Header().Set("Location", fmt.Sprintf("/issues/%d", n))
+		utils.WriteJSON(w, http.StatusCreated, map[string]interface{}{"id": n, "title": string(body)})
+	}))
+	serve := func(method, key, body string, p *Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/issues", strings.NewReader(body))
+		req.RemoteAddr = "10.0.0.1:12345"
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		if p != nil {
+			req = req.WithContext(WithPrincipal(req.Context(), p))
+		}
+		w := httptest.NewRecorder()
+		w.Header().Set("RateLimit-Remaining", "7") // set by earlier middleware
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	problemCode := func(w *httptest.ResponseRecorder) utils.Code {
+		var p utils.Problem
+		json.Unmarshal(w.Body.Bytes(), &p)
+		return p.Code
+	}
+
+	t.Run("Replays The First Response", func(t *testing.T) {
+		first := serve("POST", "key-1", "Bug", nil)
+		if first.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", first.Code)
+		}
+		retry := serve("POST", "key-1", "Bug", nil)
+		if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+			t.Errorf("Expected the first response again, got %d %s", retry.Code, retry.Body.String())
+		}
+		if retry.Header().Get("Location") != "/issues/1" || retry.Header().Get("Idempotent-Replayed") != "true" {
+			t.Errorf("Expected replayed headers, got %v", retry.Header())
+		}
+		if got := atomic.LoadInt32(&created); got != 1 {
+			t.Errorf("Expected the handler to run once, ran %d times", got)
+		}
+	})
+
+	t.Run("Different Body", func(t *testing.T) {
+		w := serve("POST", "key-1", "Feature", nil)
+		if w.Code != http.StatusUnprocessableEntity || problemCode(w) != utils.CodeIdempotencyKeyReused {
+			t.Errorf("Expected 422 IDEMPOTENCY_KEY_REUSED, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Keys Are Scoped To The Caller", func(t *testing.T) {
+		if w := serve("POST", "key-1", "Bug", &Principal{UserID: "alice"}); w.Header().Get("Idempotent-Replayed") != "" {
+			t.Error("Expected another caller's key not to be replayed")
+		}
+	})
+
+	t.Run("Without A Key", func(t *testing.T) {
+		before := atomic.LoadInt32(&created)
+		serve("POST", "", "Bug", nil)
+		serve("POST", "", "Bug", nil)
+		if got := atomic.LoadInt32(&created) - before; got != 2 {
+			t.Errorf("Expected both requests to run, ran %d", got)
+		}
+	})
+
+	t.Run("Server Errors Are Not Kept", func(t *testing.T) {
+		serve("POST", "key-2", "fail", nil)
+		if w := serve("POST", "key-2", "fail", nil); w.Header().Get("Idempotent-Replayed") != "" {
+			t.Error("Expected a failed request to run again")
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		if w := serve("POST", "key-1", "Feature", nil); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+			t.Errorf("Expected an expired key to be usable again, got %d", w.Code)
+		}
+	})
+
+	t.Run("Key Too Long", func(t *testing.T) {
+		w := serve("POST", strings.Repeat("k", 256), "Bug", nil)
+		if w.Code != http.StatusBadRequest || problemCode(w) != utils.CodeInvalidIdempotencyKey {
+			t.Errorf("Expected 400 INVALID_IDEMPOTENCY_KEY, got %d", w.Code)
+		}
+	})
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	m := NewIdempotency(time.Hour)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := m.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/issues", strings.NewReader("{}"))
+		req.Header.Set(IdempotencyKeyHeader, "key")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve() }()
+	<-started
+
+	w := serve()
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 409 with Retry-After while in flight, got %d", w.Code)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("Expected the first request to complete with 201, got %d", first.Code)
+	}
+	if w := serve(); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Expected a replay once complete, got %d", w.Code)
+	}
+}
+
+func TestIdempotencyPanic(t *testing.T) {
+	m := NewIdempotency(time.Hour)
+	calls := 0
+	handler := Recoverer(m.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		panic("boom")
+	})))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("POST", "/issues", strings.NewReader("{}"))
+		req.Header.Set(IdempotencyKeyHeader, "key")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if calls != 2 {
+		t.Errorf("Expected a panicked request to be retryable, ran %d times", calls)
+	}
+}
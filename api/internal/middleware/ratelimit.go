@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/abhir9/issue-board/api/internal/auth"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// defaultMaxKeys bounds the buckets a RateLimiter keeps when MaxKeys is unset
+const defaultMaxKeys = 10000
+
+// RateLimiter is a token bucket rate limiter with separate budgets for reads
+// (GET, HEAD, OPTIONS) and writes. Callers are identified by Key, which
+// defaults to CallerKey. It should run after RealIP, and after Authenticate
+// unless keyed by ClientIPKey.
+type RateLimiter struct {
+	Read    int           // read requests per Window; 0 disables the limit
+	Write   int           // write requests per Window; 0 disables the limit
+	Window  time.Duration // time for an empty bucket to refill
+	MaxKeys int           // buckets kept before the least recently used are evicted
+	Key     func(r *http.Request) string
+	Now     func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket holds the tokens left for one caller and budget
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing read and write requests per window
+func NewRateLimiter(read, write int, window time.Duration) *RateLimiter {
+	return &RateLimiter{Read: read, Write: write, Window: window}
+}
+
+// Limit is the middleware. Every limited response carries RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers; rejected requests get a 429
+// with Retry-After.
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget, limit := "write", l.Write
+		if safeMethod(r.Method) {
+			budget, limit = "read", l.Read
+		}
+		if limit <= 0 || l.Window <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := CallerKey
+		if l.Key != nil {
+			key = l.Key
+		}
+		ok, remaining, reset, retryAfter := l.take(budget+"|"+key(r), limit)
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+		w.Header().Set("RateLimit-Policy", strconv.Itoa(limit)+";w="+strconv.Itoa(seconds(l.Window)))
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+			utils.WriteProblem(w, r, utils.CodeRateLimited, fmt.Sprintf("the %s limit of %d requests per %s is used up", budget, limit, l.Window), map[string]interface{}{
+				"retry_after": seconds(retryAfter),
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take spends a token from key's bucket. It returns whether the request is
+// allowed, the whole tokens left, the time until the bucket is full again and,
+// when rejected, the time until a token is available.
+func (l *RateLimiter) take(key string, limit int) (ok bool, remaining int, reset, retryAfter time.Duration) {
+	now := time.Now()
+	if l.Now != nil {
+		now = l.Now()
+	}
+	perToken := l.Window / time.Duration(limit)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+	b, found := l.buckets[key]
+	if !found {
+		l.evict(now)
+		b = &bucket{tokens: float64(limit), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit), b.tokens+float64(now.Sub(b.last))/float64(perToken))
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		ok = true
+	} else {
+		retryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	reset = time.Duration((float64(limit) - b.tokens) * float64(perToken))
+	return ok, int(b.tokens), reset, retryAfter
+}
+
+// evict drops buckets idle for a full window, which have refilled and are
+// indistinguishable from new ones. If the limiter still holds MaxKeys buckets
+// the least recently used one goes too. Callers hold l.mu.
+func (l *RateLimiter) evict(now time.Time) {
+	maxKeys := l.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = defaultMaxKeys
+	}
+	if now.Sub(l.lastSweep) < l.Window && len(l.buckets) < maxKeys {
+		return
+	}
+	l.lastSweep = now
+
+	var oldestKey string
+	var oldest time.Time
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.Window {
+			delete(l.buckets, key)
+		} else if oldestKey == "" || b.last.Before(oldest) {
+			oldestKey, oldest = key, b.last
+		}
+	}
+	if len(l.buckets) >= maxKeys {
+		delete(l.buckets, oldestKey)
+	}
+}
+
+// CallerKey keys authenticated callers by user, or by API key when the key
+// is not tied to one, and everyone else by client IP. Request headers are
+// only trusted once Authenticate has verified them.
+func CallerKey(r *http.Request) string {
+	if p := PrincipalFrom(r.Context()); p != nil {
+		if p.UserID != "" {
+			return "user:" + p.UserID
+		}
+		if key := r.Header.Get("X-API-Key"); key != "" {
+			return "key:" + auth.HashToken(key)
+		}
+	}
+	return ClientIPKey(r)
+}
+
+// ClientIPKey keys callers by client IP alone, for limits that run before
+// authentication
+func ClientIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds rounds d up to whole seconds for headers
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(4, 2, time.Minute)
+	limiter.Now = func() time.Time { return now }
+
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, ip string, p *Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/test", nil)
+		req.RemoteAddr = ip + ":12345"
+		if p != nil {
+			req = req.WithContext(WithPrincipal(req.Context(), p))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Write Budget", func(t *testing.T) {
+		for i, wantRemaining := range []string{"1", "0"} {
+			w := serve("POST", "10.0.0.1", nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Request %d: expected status 200, got %d", i+1, w.Code)
+			}
+			if got := w.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+				t.Errorf("Request %d: expected RateLimit-Remaining %s, got %s", i+1, wantRemaining, got)
+			}
+			if got := w.Header().Get("RateLimit-Limit"); got != "2" {
+				t.Errorf("Expected RateLimit-Limit 2, got %s", got)
+			}
+		}
+
+		w := serve("POST", "10.0.0.1", nil)
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status 429, got %d", w.Code)
+		}
+		// One token refills every 30s
+		if got := w.Header().Get("Retry-After"); got != "30" {
+			t.Errorf("Expected Retry-After 30, got %q", got)
+		}
+		if got := w.Header().Get("RateLimit-Reset"); got != "60" {
+			t.Errorf("Expected RateLimit-Reset 60, got %q", got)
+		}
+	})
+
+	t.Run("Reads Have Their Own Budget", func(t *testing.T) {
+		if w := serve("GET", "10.0.0.1", nil); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "4" {
+			t.Errorf("Expected read to pass with limit 4, got %d %s", w.Code, w.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+	t.Run("Callers Are Independent", func(t *testing.T) {
+		if w := serve("POST", "10.0.0.2", nil); w.Code != http.StatusOK {
+			t.Errorf("Expected another IP to pass, got %d", w.Code)
+		}
+		alice := &Principal{UserID: "alice"}
+		serve("POST", "10.0.0.3", alice)
+		serve("POST", "10.0.0.4", alice)
+		if w := serve("POST", "10.0.0.5", alice); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected a user to be limited across IPs, got %d", w.Code)
+		}
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+		if w := serve("POST", "10.0.0.1", nil); w.Code != http.StatusOK {
+			t.Fatalf("Expected a token after 30s, got %d", w.Code)
+		}
+		if w := serve("POST", "10.0.0.1", nil); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected only one token after 30s, got %d", w.Code)
+		}
+	})
+
+	t.Run("Idle Buckets Are Evicted", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		serve("GET", "10.0.0.9", nil)
+
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		if len(limiter.buckets) != 1 {
+			t.Errorf("Expected only the new bucket to remain, got %d", len(limiter.buckets))
+		}
+	})
+}
+
+func TestRateLimiterMaxKeys(t *testing.T) {
+	limiter := NewRateLimiter(10, 10, time.Hour)
+	limiter.MaxKeys = 3
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5"} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = ip + ":12345"
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if len(limiter.buckets) != 3 {
+		t.Errorf("Expected 3 buckets, got %d", len(limiter.buckets))
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := NewRateLimiter(0, 0, time.Minute)
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for i := 0; i < 100; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/test", nil))
+		if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("Expected unlimited requests, got %d", w.Code)
+		}
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	req.Header.Set("X-API-Key", "guess")
+
+	// An unverified API key must not buy a fresh bucket
+	if got := CallerKey(req); got != "ip:10.0.0.1" {
+		t.Errorf("Expected the client IP for an unauthenticated caller, got %q", got)
+	}
+	authed := req.WithContext(WithPrincipal(req.Context(), &Principal{}))
+	if got := CallerKey(authed); got == "ip:10.0.0.1" || got == "" {
+		t.Errorf("Expected a verified API key to be keyed by key, got %q", got)
+	}
+	user := req.WithContext(WithPrincipal(req.Context(), &Principal{UserID: "alice"}))
+	if got := CallerKey(user); got != "user:alice" {
+		t.Errorf("Expected user:alice, got %q", got)
+	}
+	if got := ClientIPKey(user); got != "ip:10.0.0.1" {
+		t.Errorf("Expected ClientIPKey to ignore the principal, got %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+	"github.com/abhir9/issue-board/api/internal/utils"
+)
+
+// Principal is the authenticated caller of a request. Callers tied to a user
+// get their role from the user's role assignments; Role applies otherwise,
+// e.g. to the shared API key. Scopes, when not nil, further limit the caller
+// to the listed permissions whatever its role.
+type Principal struct {
+	UserID string
+	Role   models.Role
+	Scopes []models.Permission
+}
+
+// InScope reports whether the caller's scopes allow perm
+func (p *Principal) InScope(perm models.Permission) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == perm {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the authenticated caller, or nil
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// RoleStore looks up the role assignments of users
+type RoleStore interface {
+	GetUserRole(ctx context.Context, userID, projectID string) (models.Role, error)
+}
+
+// ProjectFunc returns the project a request targets, or "" for none
+type ProjectFunc func(r *http.Request) string
+
+// Authorizer checks the permissions of authenticated callers
+type Authorizer struct {
+	Roles   RoleStore
+	Project ProjectFunc // nil when routes are not project-scoped
+}
+
+// Require creates a middleware that rejects callers whose role or scopes
+// lack perm
+func (a *Authorizer) Require(perm models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p := PrincipalFrom(r.Context()); p != nil && !p.InScope(perm) {
+				utils.WriteProblem(w, r, utils.CodeInsufficientScope, fmt.Sprintf("the token's scopes do not include %s", perm), map[string]interface{}{
+					"required_permission": perm,
+					"scopes":              p.Scopes,
+				})
+				return
+			}
+			role, err := a.role(r)
+			if err != nil {
+				slog.Error("Failed to resolve role", "error", err)
+				utils.WriteProblem(w, r, utils.CodeInternal, "Failed to check permissions", nil)
+				return
+			}
+			if !role.Can(perm) {
+				utils.WriteProblem(w, r, utils.CodePermissionDenied, fmt.Sprintf("role %s lacks %s", role, perm), map[string]interface{}{
+					"required_permission": perm,
+					"role":                role,
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// role resolves the caller's role for the request's project
+func (a *Authorizer) role(r *http.Request) (models.Role, error) {
+	p := PrincipalFrom(r.Context())
+	if p == nil {
+		return "", nil
+	}
+	if p.UserID == "" || a.Roles == nil {
+		return p.Role, nil
+	}
+
+	project := ""
+	if a.Project != nil {
+		project = a.Project(r)
+	}
+	role, err := a.Roles.GetUserRole(r.Context(), p.UserID, project)
+	if err != nil || role == "" {
+		return p.Role, err
+	}
+	return role, nil
+}
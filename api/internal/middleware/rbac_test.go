@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abhir9/issue-board/api/internal/models"
+)
+
+func TestRolePermissions(t *testing.T) {
+	// Expected permission matrix: each role inherits the ones before it
+	matrix := map[models.Permission][]models.Role{
+		models.PermIssuesRead:   {models.RoleViewer, models.RoleMember, models.RoleMaintainer, models.RoleAdmin},
+		models.PermUsersRead:    {models.RoleViewer, models.RoleMember, models.RoleMaintainer, models.RoleAdmin},
+		models.PermLabelsRead:   {models.RoleViewer, models.RoleMember, models.RoleMaintainer, models.RoleAdmin},
+		models.PermStatsRead:    {models.RoleViewer, models.RoleMember, models.RoleMaintainer, models.RoleAdmin},
+		models.PermIssuesWrite:  {models.RoleMember, models.RoleMaintainer, models.RoleAdmin},
+		models.PermIssuesDelete: {models.RoleMaintainer, models.RoleAdmin},
+		models.PermUsersManage:  {models.RoleAdmin},
+		models.PermRolesManage:  {models.RoleAdmin},
+	}
+
+	roles := []models.Role{models.RoleViewer, models.RoleMember, models.RoleMaintainer, models.RoleAdmin, "", "owner"}
+	for perm, allowed := range matrix {
+		for _, role := range roles {
+			want := false
+			for _, r := range allowed {
+				want = want || r == role
+			}
+			if got := role.Can(perm); got != want {
+				t.Errorf("%q.Can(%s) = %v, want %v", role, perm, got, want)
+			}
+		}
+	}
+}
+
+type fakeRoles map[[2]string]models.Role
+
+func (f fakeRoles) GetUserRole(ctx context.Context, userID, projectID string) (models.Role, error) {
+	if role, ok := f[[2]string{userID, projectID}]; ok {
+		return role, nil
+	}
+	return f[[2]string{userID, ""}], nil
+}
+
+func TestAuthorizerRequire(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	authz := &Authorizer{
+		Roles: fakeRoles{
+			{"alice", ""}:    models.RoleMember,
+			{"alice", "ops"}: models.RoleMaintainer,
+		},
+		Project: func(r *http.Request) string { return r.URL.Query().Get("project") },
+	}
+	handler := authz.Require(models.PermIssuesDelete)(testHandler)
+
+	serve := func(p *Principal, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("DELETE", url, nil)
+		if p != nil {
+			req = req.WithContext(WithPrincipal(req.Context(), p))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Principal Role", func(t *testing.T) {
+		if w := serve(&Principal{Role: models.RoleMaintainer}, "/test"); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("Forbidden Body", func(t *testing.T) {
+		w := serve(&Principal{Role: models.RoleMember}, "/test")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("Expected status 403, got %d", w.Code)
+		}
+
+		var response struct {
+			Code               string `json:"code"`
+			RequiredPermission string `json:"required_permission"`
+			Role               string `json:"role"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response.Code != "PERMISSION_DENIED" || response.RequiredPermission != "issues:delete" || response.Role != "member" {
+			t.Errorf("Unexpected 403 body: %s", w.Body.String())
+		}
+	})
+
+	t.Run("Missing Principal", func(t *testing.T) {
+		if w := serve(nil, "/test"); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("User Role Per Project", func(t *testing.T) {
+		alice := &Principal{UserID: "alice", Role: models.RoleViewer}
+		if w := serve(alice, "/test"); w.Code != http.StatusForbidden {
+			t.Errorf("Expected global member role to be denied, got %d", w.Code)
+		}
+		if w := serve(alice, "/test?project=ops"); w.Code != http.StatusOK {
+			t.Errorf("Expected maintainer role in project ops, got %d", w.Code)
+		}
+	})
+
+	t.Run("User Without Assignment", func(t *testing.T) {
+		if w := serve(&Principal{UserID: "bob", Role: models.RoleMaintainer}, "/test"); w.Code != http.StatusOK {
+			t.Errorf("Expected fallback to the principal role, got %d", w.Code)
+		}
+	})
+	t.Run("Token Scopes", func(t *testing.T) {
+		scoped := &Principal{Role: models.RoleAdmin, Scopes: []models.Permission{models.PermIssuesRead}}
+		w := serve(scoped, "/test")
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "INSUFFICIENT_SCOPE") {
+			t.Errorf("Expected out-of-scope request to be denied, got %d: %s", w.Code, w.Body.String())
+		}
+
+		scoped.Scopes = append(scoped.Scopes, models.PermIssuesDelete)
+		if w := serve(scoped, "/test"); w.Code != http.StatusOK {
+			t.Errorf("Expected in-scope request to pass, got %d", w.Code)
+		}
+
+		// Scopes never grant more than the role allows
+		limited := &Principal{Role: models.RoleMember, Scopes: []models.Permission{models.PermIssuesDelete}}
+		if w := serve(limited, "/test"); w.Code != http.StatusForbidden {
+			t.Errorf("Expected member role to be denied despite scope, got %d", w.Code)
+		}
+	})
+}
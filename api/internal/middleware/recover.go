@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/abhir9/issue-board/api/internal/utils"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// Recoverer turns a panicking handler into an INTERNAL_ERROR problem and
+// logs the panic with the request ID the problem carries
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Aborted responses must not be recovered
+				panic(rec)
+			}
+			slog.Error("Handler panicked", "panic", rec, "request_id", chimw.GetReqID(r.Context()), "stack", string(debug.Stack()))
+			utils.WriteProblem(w, r, utils.CodeInternal, "", nil)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Account is the local login of a user. Email is stored lowercase.
+type Account struct {
+	UserID       string
+	Email        string
+	PasswordHash string
+}
+
+// Identity links a user to the subject of an external OIDC issuer
+type Identity struct {
+	Issuer  string
+	Subject string
+	UserID  string
+}
+
+// Session is a logged-in browser session. ID is the hash of the token held
+// in the session cookie; CSRFToken must accompany unsafe requests.
+type Session struct {
+	ID        string
+	UserID    string
+	CSRFToken string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// LoginRequest is the body of POST /api/auth/login
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// SessionInfo describes the current session. The CSRF token must be sent in
+// the X-CSRF-Token header of every non-GET request.
+type SessionInfo struct {
+	User      User      `json:"user"`
+	CSRFToken string    `json:"csrf_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateUserRequest is the body of POST /api/users. Email and Password
+// create a local account; Role assigns a global role.
+type CreateUserRequest struct {
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Password  string `json:"password,omitempty"`
+	Role      Role   `json:"role,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Comment is a message in an issue's discussion thread
+type Comment struct {
+	ID        string    `json:"id"`
+	IssueID   string    `json:"issue_id"`
+	AuthorID  *string   `json:"author_id"` // the caller that wrote it, if known
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateCommentRequest is the body of POST /api/issues/{id}/comments
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Custom field types
+const (
+	FieldText        = "text"
+	FieldNumber      = "number"
+	FieldDate        = "date"         // YYYY-MM-DD
+	FieldSelect      = "select"       // one of the field's options
+	FieldMultiSelect = "multi_select" // any of the field's options
+	FieldUser        = "user"         // a user ID
+)
+
+// FieldTypes lists the supported custom field types
+var FieldTypes = []string{FieldText, FieldNumber, FieldDate, FieldSelect, FieldMultiSelect, FieldUser}
+
+// CustomFieldPrefix marks a custom field key in the sort parameter and the
+// issue list filters, e.g. sort=-cf.severity or cf.customer=Acme
+const CustomFieldPrefix = "cf."
+
+// DateLayout is the format of date field values
+const DateLayout = "2006-01-02"
+
+var fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// ValidFieldKey reports whether key can identify a custom field: lowercase
+// letters, digits and underscores, starting with a letter
+func ValidFieldKey(key string) bool {
+	return fieldKeyPattern.MatchString(key)
+}
+
+// CustomField defines an issue attribute beyond the built-in ones. Issues
+// refer to it by Key, which cannot change.
+type CustomField struct {
+	ID        string    `json:"id"`
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options,omitempty"` // choices of select and multi_select fields, in display order
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HasOptions reports whether the field's values are picked from its options
+func (f CustomField) HasOptions() bool {
+	return f.Type == FieldSelect || f.Type == FieldMultiSelect
+}
+
+// Sortable reports whether issues can be sorted by the field. Multi-select
+// fields hold several values and user fields would sort by ID, so neither is.
+func (f CustomField) Sortable() bool {
+	return f.Type != FieldMultiSelect && f.Type != FieldUser
+}
+
+// CreateFieldRequest is the body of POST /api/fields
+type CreateFieldRequest struct {
+	Key     string   `json:"key"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Options []string `json:"options"`
+}
+
+// UpdateFieldRequest is the body of PATCH /api/fields/{id}. Options
+// replaces the list but must keep every existing option.
+type UpdateFieldRequest struct {
+	Name    *string  `json:"name"`
+	Options []string `json:"options"`
+}
+
+// FieldValues holds an issue's custom field values by field key: a string
+// for text, date, select and user fields, a float64 for number fields and
+// a sorted []string for multi-select fields. In an update a nil value
+// clears the field.
+type FieldValues map[string]interface{}
+
+// CustomFieldFilter matches issues whose field holds any of Values, given
+// in their stored form (see StoredValues)
+type CustomFieldFilter struct {
+	Field  CustomField
+	Values []string
+}
+
+// FormatNumber returns the stored form of a number field value
+func FormatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// StoredValues returns the strings a FieldValues value is stored as, one
+// per option of a multi-select value
+func StoredValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{FormatNumber(v)}
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// FieldValue converts the stored strings of a field back to its FieldValues
+// value, or nil if there are none
+func (f CustomField) FieldValue(stored []string) interface{} {
+	if len(stored) == 0 {
+		return nil
+	}
+	switch f.Type {
+	case FieldNumber:
+		n, _ := strconv.ParseFloat(stored[0], 64)
+		return n
+	case FieldMultiSelect:
+		values := append([]string(nil), stored...)
+		sort.Strings(values)
+		return values
+	}
+	return stored[0]
+}
+
+// ParseFieldKey splits a "cf.<key>" name, as used by the sort parameter and
+// issue type rules, into its custom field key
+func ParseFieldKey(field string) (string, bool) {
+	key, ok := strings.CutPrefix(field, CustomFieldPrefix)
+	return key, ok && ValidFieldKey(key)
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type User struct {
 	ID        string `json:"id"`
@@ -15,17 +18,49 @@ type Label struct {
 }
 
 type Issue struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`   // Backlog, Todo, In Progress, Done, Canceled
-	Priority    string    `json:"priority"` // Low, Medium, High, Critical
-	AssigneeID  *string   `json:"assignee_id"`
-	Assignee    *User     `json:"assignee,omitempty"` // For response population
-	Labels      []Label   `json:"labels,omitempty"`   // For response population
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	OrderIndex  float64   `json:"order_index"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`   // Backlog, Todo, In Progress, Done, Canceled
+	Priority    string     `json:"priority"` // Low, Medium, High, Critical
+	Type        string     `json:"type"`     // bug, feature, task, epic
+	AssigneeID  *string    `json:"assignee_id"`
+	ParentID    *string    `json:"parent_id"`
+	Assignee    *User      `json:"assignee,omitempty"` // For response population
+	Labels      []Label    `json:"labels,omitempty"`   // For response population
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	OrderIndex  float64    `json:"order_index"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`  // set while the issue is in the trash
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"` // set while the issue is archived
+
+	CustomFields FieldValues `json:"custom_fields,omitempty"` // values by custom field key
+
+	CommentsCount *int       `json:"comments_count,omitempty"` // set when expanded
+	Children      []IssueRef `json:"children,omitempty"`       // sub-issues, set when expanded
+}
+
+// IssueRef is the summary of an issue embedded in another
+type IssueRef struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Type   string `json:"type"`
+}
+
+// BoardVersion is the board-wide change counter. Every write to issues,
+// labels or users advances it.
+type BoardVersion struct {
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MoveIssueResponse is a moved issue with its new neighbours in the column's
+// board order. Previous or Next is null at either end of the column.
+type MoveIssueResponse struct {
+	Issue    Issue  `json:"issue"`
+	Previous *Issue `json:"previous"`
+	Next     *Issue `json:"next"`
 }
 
 type CreateIssueRequest struct {
@@ -33,8 +68,13 @@ type CreateIssueRequest struct {
 	Description string   `json:"description"`
 	Status      string   `json:"status"`
 	Priority    string   `json:"priority"`
+	Type        string   `json:"type"` // defaults to task
 	AssigneeID  *string  `json:"assignee_id"`
+	ParentID    *string  `json:"parent_id"`
 	LabelIDs    []string `json:"label_ids"`
+	TemplateID  *string  `json:"template_id"` // defaults for the fields left out
+
+	CustomFields map[string]json.RawMessage `json:"custom_fields" swaggertype:"object"` // values by custom field key
 }
 
 type UpdateIssueRequest struct {
@@ -42,13 +82,22 @@ type UpdateIssueRequest struct {
 	Description *string  `json:"description"`
 	Status      *string  `json:"status"`
 	Priority    *string  `json:"priority"`
+	Type        *string  `json:"type"`
 	AssigneeID  *string  `json:"assignee_id"`
+	ParentID    *string  `json:"parent_id"` // "" detaches the issue from its parent
 	LabelIDs    []string `json:"label_ids"`
 	OrderIndex  *float64 `json:"order_index"`
+
+	// CustomFields sets the given fields, by key; null clears one
+	CustomFields map[string]json.RawMessage `json:"custom_fields" swaggertype:"object"`
 }
 
 // Valid status values
 var ValidStatuses = []string{"Backlog", "Todo", "In Progress", "Done", "Canceled"}
 
+// ClosedStatuses are the statuses of finished issues, which are archived
+// after a while
+var ClosedStatuses = []string{"Done", "Canceled"}
+
 // Valid priority values
 var ValidPriorities = []string{"Low", "Medium", "High", "Critical"}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IssueFilter holds the filters accepted by the issue list
+type IssueFilter struct {
+	Status     []string
+	AssigneeID string
+	Priority   []string
+	Types      []string
+	Labels     []string // label names, matches issues having any of them
+	IDs        []string // matches only these issues, when set
+	Trashed    bool     // issues in the trash instead of live ones
+
+	// IncludeArchived also matches archived issues, which are left out by default
+	IncludeArchived bool
+
+	// CustomFields matches issues satisfying every custom field filter
+	CustomFields []CustomFieldFilter
+}
+
+// IssueQuery describes a page of issues to fetch
+type IssueQuery struct {
+	IssueFilter
+
+	// Fields limits the issue attributes loaded and returned; nil means all
+	Fields []string
+
+	// Expand selects the related resources to load; nil means DefaultExpand
+	Expand *IssueExpand
+
+	// Sort keys in priority order; empty means DefaultSort. The issue ID is
+	// always appended as a final tie-breaker.
+	Sort []SortKey
+
+	// Offset pagination (legacy): Page is 1-based, PageSize 0 returns everything
+	Page     int
+	PageSize int
+
+	// Keyset pagination: up to Limit issues positioned after Cursor
+	Cursor *Cursor
+	Limit  int
+}
+
+// Issue attributes accepted by the fields parameter
+var IssueFields = []string{"id", "title", "description", "status", "priority", "type", "assignee_id", "parent_id", "created_at", "updated_at", "order_index", "custom_fields"}
+
+// Related resources accepted by the expand parameter
+const (
+	ExpandAssignee      = "assignee"
+	ExpandLabels        = "labels"
+	ExpandCommentsCount = "comments_count"
+	ExpandChildren      = "children"
+)
+
+// ExpandFields lists the supported expansions
+var ExpandFields = []string{ExpandAssignee, ExpandLabels, ExpandCommentsCount, ExpandChildren}
+
+// IssueExpand selects the related resources loaded with each issue
+type IssueExpand struct {
+	Assignee      bool
+	Labels        bool
+	CommentsCount bool
+	Children      bool
+}
+
+// DefaultExpand is used when no expansion is requested, matching the
+// original list payload
+var DefaultExpand = IssueExpand{Assignee: true, Labels: true}
+
+// ParseFields parses a comma-separated list of issue attributes
+func ParseFields(s string) ([]string, error) {
+	fields := []string{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !oneOf(IssueFields, f) {
+			return nil, fmt.Errorf("invalid field %q, allowed: %s", f, strings.Join(IssueFields, ", "))
+		}
+		if !oneOf(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
+
+// ParseExpand parses a comma-separated list of expansions
+func ParseExpand(s string) (IssueExpand, error) {
+	var e IssueExpand
+	for _, f := range strings.Split(s, ",") {
+		switch strings.TrimSpace(f) {
+		case "":
+		case ExpandAssignee:
+			e.Assignee = true
+		case ExpandLabels:
+			e.Labels = true
+		case ExpandCommentsCount:
+			e.CommentsCount = true
+		case ExpandChildren:
+			e.Children = true
+		default:
+			return e, fmt.Errorf("invalid expand %q, allowed: %s", strings.TrimSpace(f), strings.Join(ExpandFields, ", "))
+		}
+	}
+	return e, nil
+}
+
+// WantsField reports whether the query returns the given issue attribute
+func (q IssueQuery) WantsField(field string) bool {
+	return q.Fields == nil || oneOf(q.Fields, field)
+}
+
+// Expansions returns the effective expansions of the query
+func (q IssueQuery) Expansions() IssueExpand {
+	if q.Expand == nil {
+		return DefaultExpand
+	}
+	return *q.Expand
+}
+
+// Keyset reports whether the query uses cursor pagination
+func (q IssueQuery) Keyset() bool {
+	return q.Cursor != nil || q.Limit > 0
+}
+
+// IssueList is the paginated issue list envelope
+type IssueList struct {
+	Items      []Issue       `json:"items"`
+	NextCursor *string       `json:"next_cursor"`
+	TotalCount int           `json:"total_count"`
+	Columns    []ColumnCount `json:"columns,omitempty"` // set by the API, not the store
+}
+
+// Sortable issue fields accepted by the sort parameter
+const (
+	SortOrderIndex = "order_index"
+	SortPriority   = "priority"
+	SortStatus     = "status"
+	SortAssignee   = "assignee"
+	SortTitle      = "title"
+	SortCreatedAt  = "created_at"
+	SortUpdatedAt  = "updated_at"
+)
+
+// SortFields lists the whitelisted sort fields
+var SortFields = []string{SortOrderIndex, SortPriority, SortStatus, SortAssignee, SortTitle, SortCreatedAt, SortUpdatedAt}
+
+// DefaultSort is the board ordering used when no sort is requested
+var DefaultSort = []SortKey{{Field: SortOrderIndex}}
+
+// SortKey is a single sort field and its direction
+type SortKey struct {
+	Field string
+	Desc  bool
+
+	// Custom is the definition of a "cf.<key>" field, looked up by the API
+	// before the query reaches the store
+	Custom *CustomField
+}
+
+// ParseSort parses a comma-separated sort expression such as
+// "-priority,created_at", where a leading "-" sorts descending. Custom
+// fields are given as "cf.<key>"; whether they exist is checked by the caller.
+func ParseSort(s string) ([]SortKey, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, custom := ParseFieldKey(key.Field); !custom && !oneOf(SortFields, key.Field) {
+			return nil, fmt.Errorf("invalid sort field %q, allowed: %s or cf.<key>", key.Field, strings.Join(SortFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// FormatSort returns the canonical sort expression for keys
+func FormatSort(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if k.Desc {
+			parts[i] = "-" + k.Field
+		} else {
+			parts[i] = k.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func oneOf(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// SortKeys returns the effective sort of the query
+func (q IssueQuery) SortKeys() []SortKey {
+	if len(q.Sort) == 0 {
+		return DefaultSort
+	}
+	return q.Sort
+}
+
+// Cursor is the position of the last issue of a page in the list ordering.
+// Values holds the sort key values of that issue, Sort the sort expression
+// they belong to, and ID breaks ties.
+type Cursor struct {
+	Values []interface{} `json:"v"`
+	Sort   string        `json:"s"`
+	ID     string        `json:"id"`
+}
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode returns the opaque string representation of the cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
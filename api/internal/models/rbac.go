@@ -0,0 +1,89 @@
+package models
+
+import "fmt"
+
+// Role is the access level of a user, globally or within a project
+type Role string
+
+// Roles from least to most privileged
+const (
+	RoleViewer     Role = "viewer"
+	RoleMember     Role = "member"
+	RoleMaintainer Role = "maintainer"
+	RoleAdmin      Role = "admin"
+)
+
+// ValidRoles lists the roles from least to most privileged
+var ValidRoles = []Role{RoleViewer, RoleMember, RoleMaintainer, RoleAdmin}
+
+// Permission is an action a route requires
+type Permission string
+
+const (
+	PermIssuesRead      Permission = "issues:read"
+	PermIssuesWrite     Permission = "issues:write"  // create, update and move
+	PermIssuesDelete    Permission = "issues:delete" // move to and restore from the trash
+	PermIssuesPurge     Permission = "issues:purge"  // delete permanently
+	PermUsersRead       Permission = "users:read"
+	PermUsersManage     Permission = "users:manage" // create users and accounts
+	PermLabelsRead      Permission = "labels:read"
+	PermTemplatesRead   Permission = "templates:read"
+	PermTemplatesManage Permission = "templates:manage" // create, update and delete issue templates
+	PermFieldsRead      Permission = "fields:read"
+	PermFieldsManage    Permission = "fields:manage" // define, change and delete custom fields
+	PermTypesRead       Permission = "types:read"
+	PermTypesManage     Permission = "types:manage" // change the display and rules of issue types
+	PermStatsRead       Permission = "stats:read"
+	PermRolesManage     Permission = "roles:manage"
+)
+
+// rolePermissions is the permission matrix. Each role also holds the
+// permissions of the roles below it.
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:     {PermIssuesRead, PermUsersRead, PermLabelsRead, PermTemplatesRead, PermFieldsRead, PermTypesRead, PermStatsRead},
+	RoleMember:     {PermIssuesWrite},
+	RoleMaintainer: {PermIssuesDelete, PermTemplatesManage, PermFieldsManage, PermTypesManage},
+	RoleAdmin:      {PermIssuesPurge, PermUsersManage, PermRolesManage},
+}
+
+// ParseRole parses a role name
+func ParseRole(s string) (Role, error) {
+	for _, r := range ValidRoles {
+		if string(r) == s {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("invalid role %q, allowed: viewer, member, maintainer, admin", s)
+}
+
+// Can reports whether the role grants p
+func (r Role) Can(p Permission) bool {
+	if _, err := ParseRole(string(r)); err != nil {
+		return false
+	}
+	for _, role := range ValidRoles {
+		for _, granted := range rolePermissions[role] {
+			if granted == p {
+				return true
+			}
+		}
+		if role == r {
+			break
+		}
+	}
+	return false
+}
+
+// RoleAssignment grants a user a role. An empty ProjectID applies to every
+// project the user has no project-specific role in.
+type RoleAssignment struct {
+	UserID    string `json:"user_id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Role      Role   `json:"role"`
+}
+
+// SetRoleRequest is the body of PUT /api/users/{id}/role
+type SetRoleRequest struct {
+	Role      Role   `json:"role"`
+	ProjectID string `json:"project_id,omitempty"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import "time"
+
+// StatusChange is an entry of an issue's status history. From is nil for the
+// status the issue was created with.
+type StatusChange struct {
+	IssueID   string    `json:"issue_id"`
+	From      *string   `json:"from_status"`
+	To        string    `json:"to_status"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Dimensions issues can be grouped by in stats
+const (
+	GroupByStatus   = "status"
+	GroupByPriority = "priority"
+	GroupByAssignee = "assignee"
+	GroupByLabel    = "label"
+)
+
+// GroupByFields lists the supported stats dimensions
+var GroupByFields = []string{GroupByStatus, GroupByPriority, GroupByAssignee, GroupByLabel}
+
+// GroupCount is the number of issues sharing a value for each grouped
+// dimension. A nil key stands for unassigned or unlabelled issues; an issue
+// with several labels counts once per label.
+type GroupCount struct {
+	Keys  []*string
+	Count int
+}
+
+// StatGroup is one bucket of a single-dimension breakdown
+type StatGroup struct {
+	Key   *string `json:"key"`
+	Name  string  `json:"name,omitempty"` // assignee name
+	Count int     `json:"count"`
+}
+
+// PivotCell is one cell of a two-dimensional breakdown
+type PivotCell struct {
+	Row    *string `json:"row"`
+	Column *string `json:"column"`
+	Count  int     `json:"count"`
+}
+
+// Pivot counts issues by two dimensions, e.g. status by assignee
+type Pivot struct {
+	Rows    string      `json:"rows"`
+	Columns string      `json:"columns"`
+	Cells   []PivotCell `json:"cells"`
+}
+
+// IssueStats is the response of GET /api/stats
+type IssueStats struct {
+	Total  int                    `json:"total"`
+	Groups map[string][]StatGroup `json:"groups"`
+	Pivot  *Pivot                 `json:"pivot,omitempty"`
+}
+
+// FlowBucket counts issues created and closed in a time bucket
+type FlowBucket struct {
+	Start   time.Time `json:"start"`
+	Created int       `json:"created"`
+	Closed  int       `json:"closed"`
+}
+
+// CreatedVsClosedReport is the created-vs-closed time series
+type CreatedVsClosedReport struct {
+	Interval string       `json:"interval"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Buckets  []FlowBucket `json:"buckets"`
+}
+
+// Percentiles summarizes a distribution of durations in hours
+type Percentiles struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50"`
+	P75   float64 `json:"p75"`
+	P90   float64 `json:"p90"`
+	P95   float64 `json:"p95"`
+}
+
+// FlowTimes holds the cycle time (In Progress to Done) and lead time
+// (created to Done) distributions of completed issues
+type FlowTimes struct {
+	From      time.Time   `json:"from"`
+	To        time.Time   `json:"to"`
+	CycleTime Percentiles `json:"cycle_time"`
+	LeadTime  Percentiles `json:"lead_time"`
+}
+
+// AssigneeThroughput is the number of issues an assignee completed
+type AssigneeThroughput struct {
+	AssigneeID *string `json:"assignee_id"`
+	Name       string  `json:"name,omitempty"`
+	Completed  int     `json:"completed"`
+}
+
+// ThroughputReport lists completed issues per assignee over a time range
+type ThroughputReport struct {
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	Assignees []AssigneeThroughput `json:"assignees"`
+}
+
+// CumulativeFlow is the number of issues in each status at the end of each
+// bucket, shaped for stacked area charts: Series[i].Counts[j] is the count of
+// Series[i].Status at Dates[j]
+type CumulativeFlow struct {
+	Interval string       `json:"interval"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Dates    []time.Time  `json:"dates"`
+	Series   []FlowSeries `json:"series"`
+}
+
+// FlowSeries is the count of one status over time
+type FlowSeries struct {
+	Status string `json:"status"`
+	Counts []int  `json:"counts"`
+}
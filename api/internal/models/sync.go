@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entities recorded in the change log
+const (
+	EntityIssue = "issue"
+	EntityLabel = "label"
+	EntityUser  = "user"
+)
+
+// Change is the latest change-log entry for an entity. Seq increases with
+// every write, so it doubles as the sync token of the change.
+type Change struct {
+	Seq       int64     `json:"seq"`
+	Entity    string    `json:"entity"`
+	EntityID  string    `json:"id"`
+	Deleted   bool      `json:"deleted"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Tombstone marks an entity deleted since the client's sync token
+type Tombstone struct {
+	Entity    string    `json:"entity"`
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SyncResponse is a delta (or, without a token, a full snapshot) of the
+// board. Clients store Next and pass it as since on their next sync.
+type SyncResponse struct {
+	Issues  []Issue     `json:"issues"`
+	Labels  []Label     `json:"labels"`
+	Users   []User      `json:"users"`
+	Deleted []Tombstone `json:"deleted"`
+	Next    string      `json:"next"`
+	HasMore bool        `json:"has_more"`
+	Full    bool        `json:"full"` // a snapshot replacing everything the client holds
+}
+
+// Sync mutation operations
+const (
+	MutationCreate = "create"
+	MutationUpdate = "update"
+	MutationMove   = "move"
+	MutationDelete = "delete"
+)
+
+// Mutation is a change a client made to an issue while offline
+type Mutation struct {
+	ClientID string          `json:"client_id"`
+	Op       string          `json:"op"`
+	ID       string          `json:"id,omitempty"`   // the issue, except for create
+	Base     string          `json:"base,omitempty"` // sync token the change was made against
+	Data     json.RawMessage `json:"data,omitempty"` // the create, update or move request body
+}
+
+// SyncRequest is a batch of queued mutations, applied in order
+type SyncRequest struct {
+	Mutations []Mutation `json:"mutations"`
+}
+
+// Mutation results
+const (
+	MutationApplied  = "applied"
+	MutationConflict = "conflict"
+	MutationRejected = "rejected"
+)
+
+// MutationResult is the outcome of one mutation. An applied create, update
+// or move returns the issue; a conflict returns the server's copy, or
+// Deleted when the issue is gone; a rejection returns the problem the
+// equivalent request would have.
+type MutationResult struct {
+	ClientID string          `json:"client_id"`
+	Result   string          `json:"result"`
+	Issue    *Issue          `json:"issue,omitempty"`
+	Deleted  bool            `json:"deleted,omitempty"`
+	Problem  json.RawMessage `json:"problem,omitempty"`
+}
+
+// SyncResult lists the outcome of each mutation in a SyncRequest
+type SyncResult struct {
+	Results []MutationResult `json:"results"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// IssueTemplate holds the defaults of a kind of issue, such as a bug report.
+// Empty Status, Priority and Type leave them to the create request.
+type IssueTemplate struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	TitlePrefix string    `json:"title_prefix"`
+	Description string    `json:"description"` // markdown skeleton
+	Status      string    `json:"status"`
+	Priority    string    `json:"priority"`
+	Type        string    `json:"type"`
+	LabelIDs    []string  `json:"label_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TemplateRequest is the body of POST /api/templates and PUT /api/templates/{id}
+type TemplateRequest struct {
+	Name        string   `json:"name"`
+	TitlePrefix string   `json:"title_prefix"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Priority    string   `json:"priority"`
+	Type        string   `json:"type"`
+	LabelIDs    []string `json:"label_ids"`
+}
+
+// Apply fills in the fields req leaves empty from the template and prefixes
+// the title. A label_ids list in req, even an empty one, replaces the
+// template's labels.
+func (t IssueTemplate) Apply(req *CreateIssueRequest) {
+	if req.Title != "" && !strings.HasPrefix(req.Title, t.TitlePrefix) {
+		req.Title = t.TitlePrefix + req.Title
+	}
+	if req.Description == "" {
+		req.Description = t.Description
+	}
+	if req.Status == "" {
+		req.Status = t.Status
+	}
+	if req.Priority == "" {
+		req.Priority = t.Priority
+	}
+	if req.Type == "" {
+		req.Type = t.Type
+	}
+	if req.LabelIDs == nil {
+		req.LabelIDs = append([]string(nil), t.LabelIDs...)
+	}
+}
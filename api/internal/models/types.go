@@ -0,0 +1,92 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Issue types
+const (
+	TypeBug     = "bug"
+	TypeFeature = "feature"
+	TypeTask    = "task"
+	TypeEpic    = "epic"
+)
+
+// ValidTypes lists the issue types in display order
+var ValidTypes = []string{TypeBug, TypeFeature, TypeTask, TypeEpic}
+
+// DefaultType is the type of issues created without one
+const DefaultType = TypeTask
+
+// RequirableFields lists the built-in issue attributes a type can require.
+// Custom fields are required as "cf.<key>".
+var RequirableFields = []string{"description", "assignee_id", "label_ids"}
+
+// IssueType is the display and rules of one type of issue
+type IssueType struct {
+	Key            string    `json:"key"`
+	Name           string    `json:"name"`
+	Icon           string    `json:"icon"`
+	Color          string    `json:"color"`
+	RequiredFields []string  `json:"required_fields"` // must be set on issues of the type
+	Statuses       []string  `json:"statuses"`        // statuses issues of the type may be in; empty allows all
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// IssueTypeRequest is the body of PUT /api/issue-types/{key}
+type IssueTypeRequest struct {
+	Name           string   `json:"name"`
+	Icon           string   `json:"icon"`
+	Color          string   `json:"color"`
+	RequiredFields []string `json:"required_fields"`
+	Statuses       []string `json:"statuses"`
+}
+
+// AllowsStatus reports whether issues of the type may be in status
+func (t IssueType) AllowsStatus(status string) bool {
+	return len(t.Statuses) == 0 || oneOf(t.Statuses, status)
+}
+
+// AllowedStatuses returns the statuses issues of the type may be in
+func (t IssueType) AllowedStatuses() []string {
+	if len(t.Statuses) == 0 {
+		return ValidStatuses
+	}
+	return t.Statuses
+}
+
+// MissingFields returns the fields the type requires that issue leaves
+// unset, named as in requests: built-in attributes by their JSON name and
+// custom fields as custom_fields.<key>
+func (t IssueType) MissingFields(issue Issue) []string {
+	var missing []string
+	for _, f := range t.RequiredFields {
+		set := true
+		switch f {
+		case "description":
+			set = strings.TrimSpace(issue.Description) != ""
+		case "assignee_id":
+			set = issue.AssigneeID != nil
+		case "label_ids":
+			set = len(issue.Labels) > 0
+		default:
+			if key, ok := ParseFieldKey(f); ok {
+				set = issue.CustomFields[key] != nil
+				f = "custom_fields." + key
+			}
+		}
+		if !set {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// DefaultIssueTypes are the types a new board starts with
+var DefaultIssueTypes = []IssueType{
+	{Key: TypeBug, Name: "Bug", Icon: "bug", Color: "#ef4444", RequiredFields: []string{"description"}},
+	{Key: TypeFeature, Name: "Feature", Icon: "sparkles", Color: "#8b5cf6"},
+	{Key: TypeTask, Name: "Task", Icon: "check-square", Color: "#3b82f6"},
+	{Key: TypeEpic, Name: "Epic", Icon: "layers", Color: "#f59e0b"},
+}
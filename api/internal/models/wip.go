@@ -0,0 +1,63 @@
+package models
+
+// WIPLimits caps the number of issues in a status column
+type WIPLimits struct {
+	Status      map[string]int // status -> max issues in the column
+	PerAssignee map[string]int // status -> max issues per assignee in the column
+	Strict      bool           // reject changes over a limit instead of warning
+}
+
+// Enabled reports whether any limit is configured
+func (l WIPLimits) Enabled() bool {
+	return len(l.Status) > 0 || len(l.PerAssignee) > 0
+}
+
+// WIPViolation describes a limit a change would exceed
+type WIPViolation struct {
+	Status     string  `json:"status"`
+	AssigneeID *string `json:"assignee_id,omitempty"` // set for per-assignee limits
+	Limit      int     `json:"limit"`
+	Count      int     `json:"count"` // issues in the column after the change
+}
+
+// ColumnCount is the number of issues in a status column and its limits
+type ColumnCount struct {
+	Status           string `json:"status"`
+	Count            int    `json:"count"`
+	Limit            *int   `json:"limit,omitempty"`
+	PerAssigneeLimit *int   `json:"per_assignee_limit,omitempty"`
+}
+
+// Violations returns the limits exceeded once an issue is in status with
+// assigneeID. current is the issue before the change, nil on create, and
+// count returns the number of issues in a column, or an assignee's share of
+// it when assigneeID is set. Only changes that add an issue to a column, or
+// to an assignee's share of it, are checked, so reordering within a full
+// column is allowed.
+func (l WIPLimits) Violations(current *Issue, status string, assigneeID *string, count func(status string, assigneeID *string) (int, error)) ([]WIPViolation, error) {
+	var violations []WIPViolation
+	entersColumn := current == nil || current.Status != status
+
+	if limit, ok := l.Status[status]; ok && entersColumn {
+		n, err := count(status, nil)
+		if err != nil {
+			return nil, err
+		}
+		if n+1 > limit {
+			violations = append(violations, WIPViolation{Status: status, Limit: limit, Count: n + 1})
+		}
+	}
+
+	if limit, ok := l.PerAssignee[status]; ok && assigneeID != nil && *assigneeID != "" {
+		if entersColumn || current.AssigneeID == nil || *current.AssigneeID != *assigneeID {
+			n, err := count(status, assigneeID)
+			if err != nil {
+				return nil, err
+			}
+			if n+1 > limit {
+				violations = append(violations, WIPViolation{Status: status, AssigneeID: assigneeID, Limit: limit, Count: n + 1})
+			}
+		}
+	}
+	return violations, nil
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag returns a strong entity tag derived from parts, e.g. a version and
+// the query the representation was built for
+func ETag(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// NotModified reports whether the conditional headers of r show the client
+// already holds the representation with etag, last modified at lastModified
+// (zero if unknown). If-None-Match takes precedence over If-Modified-Since
+// (RFC 9110, section 13.2.2).
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteValidators sets the ETag and, unless lastModified is zero,
+// Last-Modified headers. It returns true after answering 304 Not Modified
+// when the client's copy is current, in which case the caller writes
+// nothing more.
+func WriteValidators(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if NotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// WriteJSONCached is WriteJSON with a strong ETag computed from the encoded
+// data, answering 304 when it matches If-None-Match. It saves the transfer,
+// not the work of building data.
+func WriteJSONCached(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(data)
+	if WriteValidators(w, r, ETag(buf.String()), time.Time{}) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
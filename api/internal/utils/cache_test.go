@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	a := ETag("7", "/issues", "status=Todo")
+	if a != ETag("7", "/issues", "status=Todo") {
+		t.Error("Expected the same parts to give the same ETag")
+	}
+	for _, other := range []string{ETag("8", "/issues", "status=Todo"), ETag("7", "/issues", "status=Done"), ETag("7", "/issuesstatus=Todo")} {
+		if other == a {
+			t.Errorf("Expected different parts to give a different ETag, got %s twice", a)
+		}
+	}
+	if a[0] != '"' || a[len(a)-1] != '"' {
+		t.Errorf("Expected a quoted strong ETag, got %s", a)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 3, 4, 9, 30, 15, 500, time.UTC)
+	etag := `"abc"`
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"No conditions", nil, false},
+		{"Matching ETag", map[string]string{"If-None-Match": `"abc"`}, true},
+		{"ETag in a list", map[string]string{"If-None-Match": `"x", W/"abc"`}, true},
+		{"Wildcard", map[string]string{"If-None-Match": "*"}, true},
+		{"Stale ETag", map[string]string{"If-None-Match": `"old"`}, false},
+		{"Modified since", map[string]string{"If-Modified-Since": "Mon, 04 Mar 2024 09:30:14 GMT"}, false},
+		{"Not modified since", map[string]string{"If-Modified-Since": "Mon, 04 Mar 2024 09:30:15 GMT"}, true},
+		{"Malformed date", map[string]string{"If-Modified-Since": "yesterday"}, false},
+		{"ETag wins over date", map[string]string{"If-None-Match": `"old"`, "If-Modified-Since": "Mon, 04 Mar 2024 10:00:00 GMT"}, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/issues", nil)
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		if got := NotModified(r, etag, modified); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWriteJSONCached(t *testing.T) {
+	data := map[string]int{"todo": 3}
+
+	w := httptest.NewRecorder()
+	WriteJSONCached(w, httptest.NewRequest("GET", "/stats", nil), http.StatusOK, data)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.String() != "{\"todo\":3}\n" {
+		t.Fatalf("Expected 200 with an ETag, got %d %q %s", w.Code, etag, w.Body.String())
+	}
+
+	r := httptest.NewRequest("GET", "/stats", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	WriteJSONCached(w, r, http.StatusOK, data)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected an empty 304, got %d %s", w.Code, w.Body.String())
+	}
+
+	data["todo"] = 4
+	w = httptest.NewRecorder()
+	WriteJSONCached(w, r, http.StatusOK, data)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with a new ETag after a change, got %d", w.Code)
+	}
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ProblemContentType is the media type of problem responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the type URI of every problem. The type is a
+// relative reference, e.g. /problems/issue-not-found, identifying the code.
+var ProblemTypeBase = "/problems/"
+
+// Code is a stable, machine-readable error code. Clients should branch on
+// the code; titles and details are for people and may change.
+type Code string
+
+const (
+	CodeInvalidBody           Code = "INVALID_BODY"
+	CodeBodyTooLarge          Code = "BODY_TOO_LARGE"
+	CodeValidationFailed      Code = "VALIDATION_FAILED"
+	CodeInvalidQuery          Code = "INVALID_QUERY"
+	CodeUnknownReference      Code = "UNKNOWN_REFERENCE"
+	CodeUnauthenticated       Code = "UNAUTHENTICATED"
+	CodeInvalidLogin          Code = "INVALID_LOGIN"
+	CodeLoginFailed           Code = "LOGIN_FAILED"
+	CodeInvalidLoginState     Code = "INVALID_LOGIN_STATE"
+	CodeCSRFTokenInvalid      Code = "CSRF_TOKEN_INVALID"
+	CodeInsufficientScope     Code = "INSUFFICIENT_SCOPE"
+	CodePermissionDenied      Code = "PERMISSION_DENIED"
+	CodeRouteNotFound         Code = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed      Code = "METHOD_NOT_ALLOWED"
+	CodeIssueNotFound         Code = "ISSUE_NOT_FOUND"
+	CodeUserNotFound          Code = "USER_NOT_FOUND"
+	CodeTemplateNotFound      Code = "TEMPLATE_NOT_FOUND"
+	CodeFieldNotFound         Code = "FIELD_NOT_FOUND"
+	CodeIssueTypeNotFound     Code = "ISSUE_TYPE_NOT_FOUND"
+	CodeOIDCNotConfigured     Code = "OIDC_NOT_CONFIGURED"
+	CodeEmailTaken            Code = "EMAIL_TAKEN"
+	CodeTemplateNameTaken     Code = "TEMPLATE_NAME_TAKEN"
+	CodeFieldKeyTaken         Code = "FIELD_KEY_TAKEN"
+	CodeConflict              Code = "CONFLICT"
+	CodeConstraintViolation   Code = "CONSTRAINT_VIOLATION"
+	CodeWIPLimitExceeded      Code = "WIP_LIMIT_EXCEEDED"
+	CodeRateLimited           Code = "RATE_LIMITED"
+	CodeInvalidIdempotencyKey Code = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyInProgress Code = "IDEMPOTENCY_IN_PROGRESS"
+	CodeSyncTokenExpired      Code = "SYNC_TOKEN_EXPIRED"
+	CodeInternal              Code = "INTERNAL_ERROR"
+	CodeIdentityProviderDown  Code = "IDENTITY_PROVIDER_UNAVAILABLE"
+)
+
+// problemType is the fixed status and title of a code
+type problemType struct {
+	status int
+	title  string
+}
+
+var problemTypes = map[Code]problemType{
+	CodeInvalidBody:           {http.StatusBadRequest, "Invalid request body"},
+	CodeBodyTooLarge:          {http.StatusRequestEntityTooLarge, "Request body too large"},
+	CodeValidationFailed:      {http.StatusBadRequest, "Validation failed"},
+	CodeInvalidQuery:          {http.StatusBadRequest, "Invalid query parameters"},
+	CodeUnknownReference:      {http.StatusUnprocessableEntity, "Unknown references"},
+	CodeUnauthenticated:       {http.StatusUnauthorized, "Invalid or missing credentials"},
+	CodeInvalidLogin:          {http.StatusUnauthorized, "Invalid email or password"},
+	CodeLoginFailed:           {http.StatusUnauthorized, "Login failed"},
+	CodeInvalidLoginState:     {http.StatusBadRequest, "Invalid login state"},
+	CodeCSRFTokenInvalid:      {http.StatusForbidden, "Invalid or missing CSRF token"},
+	CodeInsufficientScope:     {http.StatusForbidden, "Insufficient scope"},
+	CodePermissionDenied:      {http.StatusForbidden, "Insufficient permissions"},
+	CodeRouteNotFound:         {http.StatusNotFound, "Route not found"},
+	CodeMethodNotAllowed:      {http.StatusMethodNotAllowed, "Method not allowed"},
+	CodeIssueNotFound:         {http.StatusNotFound, "Issue not found"},
+	CodeUserNotFound:          {http.StatusNotFound, "User not found"},
+	CodeTemplateNotFound:      {http.StatusNotFound, "Template not found"},
+	CodeFieldNotFound:         {http.StatusNotFound, "Custom field not found"},
+	CodeIssueTypeNotFound:     {http.StatusNotFound, "Issue type not found"},
+	CodeOIDCNotConfigured:     {http.StatusNotFound, "OIDC login is not configured"},
+	CodeEmailTaken:            {http.StatusConflict, "Email already in use"},
+	CodeTemplateNameTaken:     {http.StatusConflict, "Template name already in use"},
+	CodeFieldKeyTaken:         {http.StatusConflict, "Custom field key already in use"},
+	CodeConflict:              {http.StatusConflict, "Conflicts with an existing record"},
+	CodeConstraintViolation:   {http.StatusUnprocessableEntity, "Constraint violation"},
+	CodeWIPLimitExceeded:      {http.StatusConflict, "WIP limit exceeded"},
+	CodeRateLimited:           {http.StatusTooManyRequests, "Too many requests"},
+	CodeInvalidIdempotencyKey: {http.StatusBadRequest, "Invalid idempotency key"},
+	CodeIdempotencyKeyReused:  {http.StatusUnprocessableEntity, "Idempotency key reused with a different request"},
+	CodeIdempotencyInProgress: {http.StatusConflict, "Request with this idempotency key in progress"},
+	CodeSyncTokenExpired:      {http.StatusGone, "Sync token no longer valid"},
+	CodeInternal:              {http.StatusInternalServerError, "Internal server error"},
+	CodeIdentityProviderDown:  {http.StatusBadGateway, "Identity provider unavailable"},
+}
+
+// Problem is an RFC 7807 problem details object. Extensions are written as
+// extension members next to the standard ones, e.g. "errors" for validation
+// failures.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       Code
+	RequestID  string
+	Extensions map[string]interface{}
+}
+
+// NewProblem builds the problem for code on r. detail explains this
+// occurrence and may be empty.
+func NewProblem(r *http.Request, code Code, detail string, ext map[string]interface{}) Problem {
+	t, ok := problemTypes[code]
+	if !ok {
+		t = problemTypes[CodeInternal]
+	}
+	p := Problem{
+		Type:       ProblemTypeBase + strings.ToLower(strings.ReplaceAll(string(code), "_", "-")),
+		Title:      t.title,
+		Status:     t.status,
+		Detail:     detail,
+		Code:       code,
+		Extensions: ext,
+	}
+	if r != nil {
+		p.Instance = r.URL.Path
+		p.RequestID = middleware.GetReqID(r.Context())
+	}
+	return p
+}
+
+// WriteProblem writes the problem for code as application/problem+json
+func WriteProblem(w http.ResponseWriter, r *http.Request, code Code, detail string, ext map[string]interface{}) {
+	p := NewProblem(r, code, detail, ext)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+7)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	m["code"] = p.Code
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		m["request_id"] = p.RequestID
+	}
+	return json.Marshal(m)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*p = Problem{}
+	for key, dst := range map[string]interface{}{
+		"type":       &p.Type,
+		"title":      &p.Title,
+		"status":     &p.Status,
+		"detail":     &p.Detail,
+		"instance":   &p.Instance,
+		"code":       &p.Code,
+		"request_id": &p.RequestID,
+	} {
+		if raw, ok := m[key]; ok {
+			if err := json.Unmarshal(raw, dst); err != nil {
+				return err
+			}
+			delete(m, key)
+		}
+	}
+	if len(m) > 0 {
+		p.Extensions = make(map[string]interface{}, len(m))
+		for k, raw := range m {
+			var v interface{}
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return err
+			}
+			p.Extensions[k] = v
+		}
+	}
+	return nil
+}
+
+// NotFound answers requests for unknown routes with a problem
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, CodeRouteNotFound, "", nil)
+}
+
+// MethodNotAllowed answers requests with an unsupported method with a problem
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, CodeMethodNotAllowed, r.Method+" is not supported on "+r.URL.Path, nil)
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestWriteProblem(t *testing.T) {
+	serve := func(h http.HandlerFunc, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		middleware.RequestID(h).ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("Standard Members", func(t *testing.T) {
+		w := serve(func(w http.ResponseWriter, r *http.Request) {
+			WriteProblem(w, r, CodeIssueNotFound, "no issue with ID 42", nil)
+		}, "GET", "/api/issues/42")
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("Expected Content-Type application/problem+json, got %q", ct)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		want := map[string]interface{}{
+			"type":     "/problems/issue-not-found",
+			"title":    "Issue not found",
+			"status":   float64(404),
+			"detail":   "no issue with ID 42",
+			"instance": "/api/issues/42",
+			"code":     "ISSUE_NOT_FOUND",
+		}
+		for k, v := range want {
+			if body[k] != v {
+				t.Errorf("Expected %s %v, got %v", k, v, body[k])
+			}
+		}
+		if id, _ := body["request_id"].(string); id == "" {
+			t.Error("Expected the request ID to be included")
+		}
+	})
+
+	t.Run("Extension Members", func(t *testing.T) {
+		w := serve(func(w http.ResponseWriter, r *http.Request) {
+			WriteProblem(w, r, CodeRateLimited, "", map[string]interface{}{"retry_after": 30})
+		}, "POST", "/api/issues")
+
+		var p Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if p.Status != http.StatusTooManyRequests || p.Code != CodeRateLimited {
+			t.Errorf("Expected 429 RATE_LIMITED, got %d %s", p.Status, p.Code)
+		}
+		if p.Extensions["retry_after"] != float64(30) {
+			t.Errorf("Expected retry_after extension, got %v", p.Extensions)
+		}
+		if p.Detail != "" {
+			t.Errorf("Expected an empty detail to be omitted, got %q", p.Detail)
+		}
+	})
+
+	t.Run("Standard Members Win", func(t *testing.T) {
+		p := NewProblem(nil, CodeInternal, "", map[string]interface{}{"status": 200})
+		data, _ := json.Marshal(p)
+
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		if body["status"] != float64(500) {
+			t.Errorf("Expected an extension not to override status, got %v", body["status"])
+		}
+	})
+
+	t.Run("Every Code Has A Type", func(t *testing.T) {
+		for code, pt := range problemTypes {
+			if pt.status < 400 || pt.title == "" {
+				t.Errorf("%s: expected an error status and a title, got %d %q", code, pt.status, pt.title)
+			}
+		}
+	})
+}
+
+func TestUnmatchedRoutes(t *testing.T) {
+	w := httptest.NewRecorder()
+	MethodNotAllowed(w, httptest.NewRequest("PUT", "/api/issues", nil))
+
+	var p Problem
+	json.Unmarshal(w.Body.Bytes(), &p)
+	if w.Code != http.StatusMethodNotAllowed || p.Code != CodeMethodNotAllowed {
+		t.Errorf("Expected 405 METHOD_NOT_ALLOWED, got %d %s", w.Code, p.Code)
+	}
+
+	w = httptest.NewRecorder()
+	NotFound(w, httptest.NewRequest("GET", "/api/nope", nil))
+	json.Unmarshal(w.Body.Bytes(), &p)
+	if w.Code != http.StatusNotFound || p.Code != CodeRouteNotFound || p.Instance != "/api/nope" {
+		t.Errorf("Expected 404 ROUTE_NOT_FOUND for /api/nope, got %d %+v", w.Code, p)
+	}
+}
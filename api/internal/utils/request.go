@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DecodeError describes a request body that is not acceptable JSON for its
+// target. Field is the JSON path of the offending field, if any, and Offset
+// the byte offset in the body where the problem was found.
+type DecodeError struct {
+	Message string
+	Field   string
+	Offset  int64
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s (field %q, offset %d)", e.Message, e.Field, e.Offset)
+	}
+	return fmt.Sprintf("%s (offset %d)", e.Message, e.Offset)
+}
+
+// DecodeJSON strictly decodes a single JSON value from body into v. Unknown
+// fields and data after the value are rejected with a *DecodeError. Bodies cut
+// off by http.MaxBytesReader return its *http.MaxBytesError.
+func DecodeJSON(body io.Reader, v interface{}) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return decodeError(data, err, dec.InputOffset())
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &DecodeError{Message: "unexpected data after the JSON value", Offset: dec.InputOffset()}
+	}
+	return nil
+}
+
+// WriteDecodeError writes the problem for an error returned by DecodeJSON:
+// 413 for oversized bodies, otherwise 400 with the problem's field and offset
+func WriteDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		maxBytes *http.MaxBytesError
+		decErr   *DecodeError
+	)
+	switch {
+	case errors.As(err, &maxBytes):
+		WriteProblem(w, r, CodeBodyTooLarge, fmt.Sprintf("request bodies are limited to %d bytes", maxBytes.Limit), map[string]interface{}{"limit_bytes": maxBytes.Limit})
+	case errors.As(err, &decErr):
+		ext := map[string]interface{}{"offset": decErr.Offset}
+		if decErr.Field != "" {
+			ext["field"] = decErr.Field
+		}
+		WriteProblem(w, r, CodeInvalidBody, decErr.Message, ext)
+	default:
+		WriteProblem(w, r, CodeInvalidBody, err.Error(), nil)
+	}
+}
+
+// decodeError converts an encoding/json error for data into a *DecodeError
+func decodeError(data []byte, err error, offset int64) error {
+	const unknownField = "json: unknown field "
+	var (
+		syntax  *json.SyntaxError
+		typeErr *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return &DecodeError{Message: "request body is empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{Message: "request body ends unexpectedly", Offset: int64(len(data))}
+	case errors.As(err, &syntax):
+		return &DecodeError{Message: strings.TrimPrefix(syntax.Error(), "json: "), Offset: syntax.Offset}
+	case errors.As(err, &typeErr):
+		return &DecodeError{
+			Message: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value),
+			Field:   typeErr.Field,
+			Offset:  typeErr.Offset,
+		}
+	case strings.HasPrefix(err.Error(), unknownField):
+		name, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownField))
+		path, keyOffset := findKey(data, name)
+		if path == "" {
+			path, keyOffset = name, offset
+		}
+		return &DecodeError{Message: "unknown field", Field: path, Offset: keyOffset}
+	default:
+		return &DecodeError{Message: strings.TrimPrefix(err.Error(), "json: "), Offset: offset}
+	}
+}
+
+// findKey returns the dotted path of the first object key called name in
+// data and the offset just past it. encoding/json reports unknown fields by
+// name only.
+func findKey(data []byte, name string) (string, int64) {
+	type level struct {
+		key     string
+		isArray bool
+		index   int
+		wantKey bool
+	}
+	var stack []level
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", 0
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1].wantKey {
+			top := &stack[len(stack)-1]
+			if key, ok := tok.(string); ok {
+				top.key, top.wantKey = key, false
+				if key == name {
+					var parts []string
+					for _, l := range stack {
+						if l.isArray {
+							parts[len(parts)-1] += "[" + strconv.Itoa(l.index) + "]"
+						} else {
+							parts = append(parts, l.key)
+						}
+					}
+					return strings.Join(parts, "."), dec.InputOffset()
+				}
+				continue
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, level{wantKey: true})
+			continue
+		case json.Delim('['):
+			stack = append(stack, level{isArray: true})
+			if len(stack) == 1 {
+				// A top-level array has no field to attach indexes to
+				return "", 0
+			}
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+
+		// A value has been consumed: expect the next key or element
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.isArray {
+				top.index++
+			} else {
+				top.wantKey = true
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeTarget struct {
+	Title    string   `json:"title"`
+	Priority *string  `json:"priority"`
+	LabelIDs []string `json:"label_ids"`
+	Meta     struct {
+		Points int `json:"points"`
+	} `json:"meta"`
+	Steps []struct {
+		Name string `json:"name"`
+	} `json:"steps"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		var v decodeTarget
+		if err := DecodeJSON(strings.NewReader(`{"title": "Bug", "label_ids": ["l1"]}`+"\n"), &v); err != nil {
+			t.Fatalf("DecodeJSON() failed: %v", err)
+		}
+		if v.Title != "Bug" || len(v.LabelIDs) != 1 {
+			t.Errorf("Unexpected result: %+v", v)
+		}
+	})
+
+	for _, tt := range []struct {
+		name       string
+		body       string
+		wantField  string
+		wantOffset int64
+	}{
+		{name: "Unknown Field", body: `{"title": "Bug", "asignee_id": "u1"}`, wantField: "asignee_id", wantOffset: 29},
+		{name: "Nested Unknown Field", body: `{"meta": {"point": 3}}`, wantField: "meta.point", wantOffset: 17},
+		{name: "Unknown Field In Array", body: `{"steps": [{"name": "a"}, {"nmae": "b"}]}`, wantField: "steps[1].nmae", wantOffset: 33},
+		{name: "Wrong Type", body: `{"title": 42}`, wantField: "title", wantOffset: 12},
+		{name: "Nested Wrong Type", body: `{"meta": {"points": "3"}}`, wantField: "meta.points", wantOffset: 23},
+		{name: "Syntax Error", body: `{"title": "Bug",}`, wantOffset: 17},
+		{name: "Trailing Data", body: `{"title": "Bug"} {"title": "Again"}`, wantOffset: 18},
+		{name: "Truncated", body: `{"title": "Bu`, wantOffset: 13},
+		{name: "Empty", body: ``},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var v decodeTarget
+			err := DecodeJSON(strings.NewReader(tt.body), &v)
+			var decErr *DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("Expected *DecodeError, got %v", err)
+			}
+			if decErr.Field != tt.wantField || decErr.Offset != tt.wantOffset {
+				t.Errorf("Expected field %q at offset %d, got %q at %d (%s)", tt.wantField, tt.wantOffset, decErr.Field, decErr.Offset, decErr.Message)
+			}
+		})
+	}
+}
+
+func TestWriteDecodeError(t *testing.T) {
+	t.Run("Decode Error", func(t *testing.T) {
+		var v decodeTarget
+		w := httptest.NewRecorder()
+		WriteDecodeError(w, nil, DecodeJSON(strings.NewReader(`{"asignee_id": "u1"}`), &v))
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", w.Code)
+		}
+		var problem Problem
+		json.Unmarshal(w.Body.Bytes(), &problem)
+		if problem.Code != CodeInvalidBody || problem.Extensions["field"] != "asignee_id" || problem.Extensions["offset"] != float64(13) {
+			t.Errorf("Unexpected problem: %s", w.Body.String())
+		}
+	})
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		var v decodeTarget
+		body := http.MaxBytesReader(httptest.NewRecorder(), readCloser{strings.NewReader(`{"title": "` + strings.Repeat("x", 100) + `"}`)}, 32)
+		w := httptest.NewRecorder()
+		WriteDecodeError(w, nil, DecodeJSON(body, &v))
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413, got %d", w.Code)
+		}
+	})
+}
+
+type readCloser struct{ *strings.Reader }
+
+func (readCloser) Close() error { return nil }
@@ -5,20 +5,6 @@ import (
 	"net/http"
 )
 
-type ErrorResponse struct {
-	Error   string                 `json:"error"`
-	Details map[string]interface{} `json:"details,omitempty"`
-}
-
-func WriteError(w http.ResponseWriter, status int, message string, details map[string]interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(ErrorResponse{
-		Error:   message,
-		Details: details,
-	})
-}
-
 func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -1,14 +1,41 @@
 package validator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
+	"time"
+)
+
+// Error codes identify the rule a field broke, for clients that map errors
+// to their own messages
+const (
+	CodeInvalid      = "invalid"
+	CodeRequired     = "required"
+	CodeTooLong      = "too_long"
+	CodeTooShort     = "too_short"
+	CodeOneOf        = "one_of"
+	CodeInvalidUUID  = "invalid_uuid"
+	CodeInvalidColor = "invalid_color"
+	CodeInvalidURL   = "invalid_url"
+	CodeInvalidDate  = "invalid_date"
+	CodeInvalidRange = "invalid_range"
+	CodeNotFound     = "not_found"
+)
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
 )
 
 // ValidationError represents a validation error
 type ValidationError struct {
-	Field   string
-	Message string
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 // ValidationErrors is a collection of validation errors
@@ -22,6 +49,22 @@ func (v ValidationErrors) Error() string {
 	return strings.Join(messages, "; ")
 }
 
+// As returns the validation errors in err's chain
+func As(err error) (ValidationErrors, bool) {
+	var errs ValidationErrors
+	ok := errors.As(err, &errs)
+	return errs, ok
+}
+
+// Field returns a single error for field, e.g. for a query parameter that
+// failed to parse
+func Field(field, code, message string) ValidationErrors {
+	return ValidationErrors{{Field: field, Code: code, Message: message}}
+}
+
+// LookupFunc returns the IDs among ids that do not exist
+type LookupFunc func(ctx context.Context, ids []string) ([]string, error)
+
 // Validator provides validation functions
 type Validator struct {
 	errors ValidationErrors
@@ -34,32 +77,48 @@ func New() *Validator {
 	}
 }
 
-// AddError adds a validation error
+// AddError adds a validation error with the generic invalid code
 func (v *Validator) AddError(field, message string) {
+	v.Add(field, CodeInvalid, message)
+}
+
+// Add adds a validation error
+func (v *Validator) Add(field, code, message string) {
 	v.errors = append(v.errors, ValidationError{
 		Field:   field,
+		Code:    code,
 		Message: message,
 	})
 }
 
+// HasError reports whether field already failed a rule
+func (v *Validator) HasError(field string) bool {
+	for _, err := range v.errors {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
 // Required checks if a string is not empty
 func (v *Validator) Required(field, value string) {
 	if strings.TrimSpace(value) == "" {
-		v.AddError(field, "is required")
+		v.Add(field, CodeRequired, "is required")
 	}
 }
 
 // MaxLength checks if a string does not exceed max length
 func (v *Validator) MaxLength(field, value string, max int) {
 	if len(value) > max {
-		v.AddError(field, fmt.Sprintf("must not exceed %d characters", max))
+		v.Add(field, CodeTooLong, fmt.Sprintf("must not exceed %d characters", max))
 	}
 }
 
 // MinLength checks if a string meets minimum length
 func (v *Validator) MinLength(field, value string, min int) {
 	if len(value) < min {
-		v.AddError(field, fmt.Sprintf("must be at least %d characters", min))
+		v.Add(field, CodeTooShort, fmt.Sprintf("must be at least %d characters", min))
 	}
 }
 
@@ -70,7 +129,81 @@ func (v *Validator) OneOf(field, value string, allowed []string) {
 			return
 		}
 	}
-	v.AddError(field, fmt.Sprintf("must be one of: %s", strings.Join(allowed, ", ")))
+	v.Add(field, CodeOneOf, fmt.Sprintf("must be one of: %s", strings.Join(allowed, ", ")))
+}
+
+// UUID checks that value is a UUID in canonical form
+func (v *Validator) UUID(field, value string) {
+	if !uuidPattern.MatchString(value) {
+		v.Add(field, CodeInvalidUUID, "must be a UUID")
+	}
+}
+
+// UUIDs checks every value with UUID, reporting the first bad one
+func (v *Validator) UUIDs(field string, values []string) {
+	for i, value := range values {
+		if !uuidPattern.MatchString(value) {
+			v.Add(fmt.Sprintf("%s[%d]", field, i), CodeInvalidUUID, "must be a UUID")
+			return
+		}
+	}
+}
+
+// HexColor checks that value is a #rgb or #rrggbb color
+func (v *Validator) HexColor(field, value string) {
+	if !hexColorPattern.MatchString(value) {
+		v.Add(field, CodeInvalidColor, "must be a hex color such as #3b82f6")
+	}
+}
+
+// URL checks that value is an absolute http or https URL
+func (v *Validator) URL(field, value string) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.Add(field, CodeInvalidURL, "must be an http or https URL")
+	}
+}
+
+// DateRange checks that from is before to, reporting the error on fromField
+func (v *Validator) DateRange(fromField, toField string, from, to time.Time) {
+	if !from.Before(to) {
+		v.Add(fromField, CodeInvalidRange, fmt.Sprintf("must be before %s", toField))
+	}
+}
+
+// Exist checks that every ID in ids exists according to lookup. It is
+// skipped if field already has an error, e.g. a malformed ID. The returned
+// error is lookup's own failure, not a validation error.
+func (v *Validator) Exist(ctx context.Context, field string, ids []string, lookup LookupFunc) error {
+	if len(ids) == 0 || v.HasError(field) || v.hasErrorUnder(field) {
+		return nil
+	}
+	missing, err := lookup(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		v.Add(field, CodeNotFound, fmt.Sprintf("unknown ID: %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// hasErrorUnder reports whether an element of field, e.g. field[2], failed
+func (v *Validator) hasErrorUnder(field string) bool {
+	for _, err := range v.errors {
+		if strings.HasPrefix(err.Field, field+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the collected errors, or nil if there are none
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
 }
 
 // Valid returns true if there are no validation errors
@@ -1,8 +1,13 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRequired(t *testing.T) {
@@ -283,3 +288,207 @@ func TestEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestCodes(t *testing.T) {
+	v := New()
+	v.Required("title", "")
+	v.MaxLength("description", "too long", 3)
+	v.MinLength("password", "pw", 8)
+	v.OneOf("status", "Nope", []string{"Todo"})
+
+	want := []string{CodeRequired, CodeTooLong, CodeTooShort, CodeOneOf}
+	errors := v.Errors()
+	if len(errors) != len(want) {
+		t.Fatalf("Expected %d errors, got %d", len(want), len(errors))
+	}
+	for i, code := range want {
+		if errors[i].Code != code {
+			t.Errorf("Error %d: expected code '%s', got '%s'", i, code, errors[i].Code)
+		}
+	}
+}
+
+func TestUUID(t *testing.T) {
+	t.Run("Valid UUID", func(t *testing.T) {
+		v := New()
+		v.UUID("assignee_id", "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a")
+
+		if !v.Valid() {
+			t.Errorf("Expected valid UUID, got %v", v.Errors())
+		}
+	})
+
+	t.Run("Invalid UUID", func(t *testing.T) {
+		for _, value := range []string{"", "user1", "6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6", "6f1c7a523b8e4c419d0a1f2e3d4c5b6a"} {
+			v := New()
+			v.UUID("assignee_id", value)
+
+			if v.Valid() || v.Errors()[0].Code != CodeInvalidUUID {
+				t.Errorf("Expected invalid_uuid for %q, got %v", value, v.Errors())
+			}
+		}
+	})
+
+	t.Run("Reports First Bad Element", func(t *testing.T) {
+		v := New()
+		v.UUIDs("label_ids", []string{"6f1c7a52-3b8e-4c41-9d0a-1f2e3d4c5b6a", "bug", "feature"})
+
+		errors := v.Errors()
+		if len(errors) != 1 || errors[0].Field != "label_ids[1]" {
+			t.Errorf("Expected one error on label_ids[1], got %v", errors)
+		}
+	})
+}
+
+func TestHexColor(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"#3b82f6": true,
+		"#FFF":    true,
+		"3b82f6":  false,
+		"#3b82f":  false,
+		"#gggggg": false,
+		"red":     false,
+	} {
+		v := New()
+		v.HexColor("color", value)
+
+		if v.Valid() != valid {
+			t.Errorf("%q: expected valid=%v, got %v", value, valid, v.Errors())
+		}
+		if !valid && v.Errors()[0].Code != CodeInvalidColor {
+			t.Errorf("%q: expected code invalid_color, got %s", value, v.Errors()[0].Code)
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"https://example.com/avatar.png": true,
+		"http://localhost:8080":          true,
+		"ftp://example.com/file":         false,
+		"/avatars/alice.png":             false,
+		"https://":                       false,
+		"not a url":                      false,
+	} {
+		v := New()
+		v.URL("avatar_url", value)
+
+		if v.Valid() != valid {
+			t.Errorf("%q: expected valid=%v, got %v", value, valid, v.Errors())
+		}
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	t.Run("Ordered", func(t *testing.T) {
+		v := New()
+		v.DateRange("from", "to", from, to)
+
+		if !v.Valid() {
+			t.Errorf("Expected valid range, got %v", v.Errors())
+		}
+	})
+
+	t.Run("Reversed", func(t *testing.T) {
+		v := New()
+		v.DateRange("from", "to", to, from)
+
+		errors := v.Errors()
+		if len(errors) != 1 || errors[0].Field != "from" || errors[0].Code != CodeInvalidRange {
+			t.Errorf("Expected invalid_range on from, got %v", errors)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		v := New()
+		v.DateRange("from", "to", from, from)
+
+		if v.Valid() {
+			t.Error("Expected an empty range to be invalid")
+		}
+	})
+}
+
+func TestExist(t *testing.T) {
+	ctx := context.Background()
+	known := map[string]bool{"a": true, "b": true}
+	calls := 0
+	lookup := func(ctx context.Context, ids []string) ([]string, error) {
+		calls++
+		var missing []string
+		for _, id := range ids {
+			if !known[id] {
+				missing = append(missing, id)
+			}
+		}
+		return missing, nil
+	}
+
+	t.Run("All Known", func(t *testing.T) {
+		v := New()
+		if err := v.Exist(ctx, "label_ids", []string{"a", "b"}, lookup); err != nil {
+			t.Fatal(err)
+		}
+		if !v.Valid() {
+			t.Errorf("Expected valid, got %v", v.Errors())
+		}
+	})
+
+	t.Run("Unknown IDs", func(t *testing.T) {
+		v := New()
+		v.Exist(ctx, "label_ids", []string{"a", "x", "y"}, lookup)
+
+		errors := v.Errors()
+		if len(errors) != 1 || errors[0].Code != CodeNotFound {
+			t.Fatalf("Expected one not_found error, got %v", errors)
+		}
+		if !strings.Contains(errors[0].Message, "x, y") {
+			t.Errorf("Expected message to name the unknown IDs, got %q", errors[0].Message)
+		}
+	})
+
+	t.Run("Skipped After Format Error", func(t *testing.T) {
+		v := New()
+		v.UUIDs("label_ids", []string{"x"})
+		before := calls
+		v.Exist(ctx, "label_ids", []string{"x"}, lookup)
+
+		if calls != before || len(v.Errors()) != 1 {
+			t.Errorf("Expected lookup to be skipped, got %d calls and %v", calls-before, v.Errors())
+		}
+	})
+
+	t.Run("Lookup Failure", func(t *testing.T) {
+		v := New()
+		failing := func(ctx context.Context, ids []string) ([]string, error) {
+			return nil, errors.New("database is locked")
+		}
+		if err := v.Exist(ctx, "assignee_id", []string{"a"}, failing); err == nil {
+			t.Error("Expected the lookup error to be returned")
+		}
+		if !v.Valid() {
+			t.Error("Expected a lookup failure not to be a validation error")
+		}
+	})
+}
+
+func TestErr(t *testing.T) {
+	v := New()
+	if v.Err() != nil {
+		t.Error("Expected nil error for a valid validator")
+	}
+
+	v.Add("title", CodeRequired, "is required")
+	errs, ok := As(fmt.Errorf("create issue: %w", v.Err()))
+	if !ok || len(errs) != 1 || errs[0].Field != "title" {
+		t.Fatalf("Expected wrapped validation errors, got %v", errs)
+	}
+
+	data, _ := json.Marshal(errs)
+	if string(data) != `[{"field":"title","code":"required","message":"is required"}]` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
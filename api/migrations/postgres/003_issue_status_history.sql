@@ -0,0 +1,12 @@
+-- Status transitions of every issue, used for cycle/lead time and flow metrics
+
+CREATE TABLE IF NOT EXISTS issue_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_status_history_issue_id ON issue_status_history(issue_id, changed_at);
+
@@ -0,0 +1,8 @@
+-- Role of each user, globally (empty project_id) or within a project
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    project_id TEXT NOT NULL DEFAULT '',
+    role TEXT NOT NULL CHECK(role IN ('viewer', 'member', 'maintainer', 'admin')),
+    PRIMARY KEY (user_id, project_id)
+);
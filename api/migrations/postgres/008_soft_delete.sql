@@ -0,0 +1,6 @@
+-- Deleted issues move to the trash: deleted_at is set instead of removing
+-- the row, and queries exclude them unless they ask for the trash.
+
+ALTER TABLE issues ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_issues_deleted_at ON issues(deleted_at);
@@ -0,0 +1,6 @@
+-- Archived issues leave the board but stay readable: archived_at is set
+-- and issue lists skip them unless include_archived is passed.
+
+ALTER TABLE issues ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_issues_archived_at ON issues(archived_at);
@@ -0,0 +1,13 @@
+-- Status transitions of every issue, used for cycle/lead time and flow metrics
+
+CREATE TABLE IF NOT EXISTS issue_status_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    changed_at DATETIME NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_status_history_issue_id ON issue_status_history(issue_id, changed_at);
+
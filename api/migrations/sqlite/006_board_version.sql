@@ -0,0 +1,10 @@
+-- Board-wide change counter, bumped by every write to issues, labels and
+-- users. Clients revalidate cached lists against it cheaply.
+
+CREATE TABLE IF NOT EXISTS board_version (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO board_version (id, version) VALUES (1, 0) ON CONFLICT (id) DO NOTHING;
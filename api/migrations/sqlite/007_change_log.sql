@@ -0,0 +1,12 @@
+-- Append-only log of writes to issues, labels and users. Its sequence is the
+-- sync token clients pass to GET /api/sync; deletes are kept as tombstones.
+
+CREATE TABLE IF NOT EXISTS change_log (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity TEXT NOT NULL CHECK (entity IN ('issue', 'label', 'user')),
+    entity_id TEXT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_log_entity ON change_log(entity, entity_id, seq);
@@ -0,0 +1,7 @@
+-- Deleted issues move to the trash: deleted_at is set instead of removing
+-- the row, and queries exclude them unless they ask for the trash.
+-- Recorded in schema_migrations, so the ALTER runs once.
+
+ALTER TABLE issues ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_issues_deleted_at ON issues(deleted_at);
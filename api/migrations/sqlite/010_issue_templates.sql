@@ -0,0 +1,19 @@
+-- Issue templates: defaults applied server-side when an issue is created
+-- with a template_id. Empty status and priority leave them to the request.
+
+CREATE TABLE IF NOT EXISTS issue_templates (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    title_prefix TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '' CHECK(status IN ('', 'Backlog', 'Todo', 'In Progress', 'Done', 'Canceled')),
+    priority TEXT NOT NULL DEFAULT '' CHECK(priority IN ('', 'Low', 'Medium', 'High', 'Critical')),
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS issue_template_labels (
+    template_id TEXT NOT NULL REFERENCES issue_templates(id) ON DELETE CASCADE,
+    label_id TEXT NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (template_id, label_id)
+);
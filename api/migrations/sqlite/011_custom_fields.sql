@@ -0,0 +1,23 @@
+-- Custom fields: board-wide issue attributes defined at runtime. Values are
+-- stored as text, one row per option of a multi-select value; number
+-- fields also keep the numeric value for sorting.
+
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id TEXT PRIMARY KEY,
+    key TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK(type IN ('text', 'number', 'date', 'select', 'multi_select', 'user')),
+    options TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS issue_field_values (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    field_id TEXT NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value TEXT NOT NULL,
+    number REAL,
+    PRIMARY KEY (issue_id, field_id, value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_field_values_field ON issue_field_values(field_id, value);
@@ -0,0 +1,24 @@
+-- Issue types: every issue is a bug, feature, task or epic. Each type has
+-- an icon and color for display, fields it requires and the statuses it
+-- may be in (an empty list allows all of them).
+
+ALTER TABLE issues ADD COLUMN type TEXT NOT NULL DEFAULT 'task' CHECK(type IN ('bug', 'feature', 'task', 'epic'));
+
+CREATE INDEX IF NOT EXISTS idx_issues_type ON issues(type);
+
+CREATE TABLE IF NOT EXISTS issue_types (
+    key TEXT PRIMARY KEY CHECK(key IN ('bug', 'feature', 'task', 'epic')),
+    name TEXT NOT NULL,
+    icon TEXT NOT NULL,
+    color TEXT NOT NULL,
+    required_fields TEXT NOT NULL DEFAULT '[]',
+    statuses TEXT NOT NULL DEFAULT '[]',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO issue_types (key, name, icon, color, required_fields) VALUES
+    ('bug', 'Bug', 'bug', '#ef4444', '["description"]'),
+    ('feature', 'Feature', 'sparkles', '#8b5cf6', '[]'),
+    ('task', 'Task', 'check-square', '#3b82f6', '[]'),
+    ('epic', 'Epic', 'layers', '#f59e0b', '[]')
+ON CONFLICT (key) DO NOTHING;
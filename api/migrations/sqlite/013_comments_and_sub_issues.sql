@@ -0,0 +1,18 @@
+-- Sub-issues and comments: an issue may belong to a parent issue and has a
+-- thread of comments. Purging a parent detaches its children.
+
+ALTER TABLE issues ADD COLUMN parent_id TEXT REFERENCES issues(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_issues_parent_id ON issues(parent_id);
+
+-- author_id is the caller that wrote the comment, which for service
+-- tokens need not be a user
+CREATE TABLE IF NOT EXISTS issue_comments (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    author_id TEXT,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_comments_issue ON issue_comments(issue_id, created_at);
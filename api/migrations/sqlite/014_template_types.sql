@@ -0,0 +1,4 @@
+-- Template types: a template may set the type of the issues created from
+-- it. An empty type leaves it to the create request.
+
+ALTER TABLE issue_templates ADD COLUMN type TEXT NOT NULL DEFAULT '' CHECK(type IN ('', 'bug', 'feature', 'task', 'epic'));
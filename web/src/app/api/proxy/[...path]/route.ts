@@ -1,7 +1,8 @@
 import { NextRequest, NextResponse } from 'next/server';
 
 const API_BASE_URL = process.env.API_BASE_URL ?? 'http://localhost:8080/api';
-const API_KEY = process.env.API_KEY ?? '';
+const API_PATH = new URL(API_BASE_URL).pathname.replace(/\/$/, '');
+const PROXY_PATH = '/api/proxy';
 
 export const dynamic = 'force-dynamic';
 
@@ -14,14 +15,16 @@ async function proxyRequest(req: NextRequest, params: ProxyParams) {
     targetUrl.searchParams.append(key, value);
   });
 
+  // The browser's session cookie and CSRF token are forwarded as they are
   const headers = new Headers(req.headers);
-  headers.set('X-API-Key', API_KEY);
   headers.delete('host');
   headers.delete('content-length');
 
   const init: RequestInit = {
     method: req.method,
     headers,
+    // Pass redirects, e.g. to the OIDC provider, on to the browser
+    redirect: 'manual',
   };
 
   if (!['GET', 'HEAD'].includes(req.method)) {
@@ -33,7 +36,19 @@ async function proxyRequest(req: NextRequest, params: ProxyParams) {
     const responseHeaders = new Headers(upstreamResponse.headers);
     responseHeaders.delete('content-encoding');
 
-    if (upstreamResponse.status === 204 || upstreamResponse.status === 304) {
+    // Cookies scoped to an API path, like the OIDC login state, are rescoped
+    // to the same path under the proxy
+    responseHeaders.delete('set-cookie');
+    for (const cookie of upstreamResponse.headers.getSetCookie()) {
+      responseHeaders.append(
+        'set-cookie',
+        cookie.replace(`Path=${API_PATH}/`, `Path=${PROXY_PATH}/`)
+      );
+    }
+
+    // No body for 204, 304 and redirects
+    const { status } = upstreamResponse;
+    if (status === 204 || (status >= 300 && status < 400)) {
       return new NextResponse(null, {
         status: upstreamResponse.status,
         headers: responseHeaders,
@@ -23,7 +23,14 @@ import {
   AlertDialogHeader,
   AlertDialogTitle,
 } from '@/components/ui/alert-dialog';
-import { deleteIssue, updateIssue, getUsers, getLabels, getIssue } from '@/lib/api';
+import {
+  deleteIssue,
+  updateIssue,
+  getUsers,
+  getLabels,
+  getIssue,
+  problemFieldErrors,
+} from '@/lib/api';
 import { Issue, IssuePriority, IssueStatus, Label, User } from '@/types';
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
 import { ArrowLeft, Save, Trash2, Check, ChevronsUpDown } from 'lucide-react';
@@ -134,6 +141,8 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
     ...issue,
     label_ids: issue.labels?.map((l) => l.id) || [],
   }));
+  // Field errors of the last failed save, by API field name
+  const [fieldErrors, setFieldErrors] = useState<Record<string, string>>({});
 
   const updateMutation = useMutation({
     mutationFn: (updates: IssueFormData) =>
@@ -151,6 +160,9 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
       toast.success('Issue updated successfully');
       onNavigateBack();
     },
+    onError: (error) => {
+      setFieldErrors(problemFieldErrors(error));
+    },
   });
 
   const deleteMutation = useMutation({
@@ -163,6 +175,7 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
   });
 
   const handleSave = () => {
+    setFieldErrors({});
     updateMutation.mutate(formData);
   };
 
@@ -190,7 +203,11 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
                   value={formData.title ?? issue.title}
                   onChange={(e) => setFormData({ ...formData, title: e.target.value })}
                   className="text-xl font-bold border-none shadow-none focus-visible:ring-0 px-0 w-full"
+                  aria-invalid={!!fieldErrors.title}
                 />
+                {fieldErrors.title && (
+                  <p className="text-xs text-red-500 mt-1 font-normal">{fieldErrors.title}</p>
+                )}
               </CardTitle>
               <div className="flex items-center gap-2 shrink-0 self-end sm:self-auto">
                 <Button
@@ -227,6 +244,9 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
                       ))}
                     </SelectContent>
                   </Select>
+                  {fieldErrors.status && (
+                    <p className="text-xs text-red-500 mt-1">{fieldErrors.status}</p>
+                  )}
                 </div>
                 <div className="space-y-2">
                   <label className="text-sm font-medium">Priority</label>
@@ -247,6 +267,9 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
                       ))}
                     </SelectContent>
                   </Select>
+                  {fieldErrors.priority && (
+                    <p className="text-xs text-red-500 mt-1">{fieldErrors.priority}</p>
+                  )}
                 </div>
               </div>
 
@@ -270,6 +293,9 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
                     ))}
                   </SelectContent>
                 </Select>
+                {fieldErrors.assignee_id && (
+                  <p className="text-xs text-red-500 mt-1">{fieldErrors.assignee_id}</p>
+                )}
               </div>
 
               <div className="space-y-2">
@@ -332,6 +358,9 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
                     </Command>
                   </PopoverContent>
                 </Popover>
+                {fieldErrors.label_ids && (
+                  <p className="text-xs text-red-500 mt-1">{fieldErrors.label_ids}</p>
+                )}
               </div>
 
               <div className="space-y-2">
@@ -340,7 +369,11 @@ function IssueDetailsContent({ issue, users, labels, onNavigateBack }: IssueDeta
                   value={formData.description ?? issue.description ?? ''}
                   onChange={(e) => setFormData({ ...formData, description: e.target.value })}
                   className="min-h-[150px]"
+                  aria-invalid={!!fieldErrors.description}
                 />
+                {fieldErrors.description && (
+                  <p className="text-xs text-red-500 mt-1">{fieldErrors.description}</p>
+                )}
               </div>
 
               <div className="space-y-4 pt-4 border-t">
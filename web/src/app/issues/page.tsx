@@ -4,6 +4,7 @@ import { Suspense } from 'react';
 import dynamic from 'next/dynamic';
 import { Board } from '@/components/board';
 import { BoardFilters } from '@/components/board-filters';
+import { LogoutButton } from '@/components/logout-button';
 
 const CreateIssueModal = dynamic(
   () => import('@/components/create-issue-modal').then((mod) => mod.CreateIssueModal),
@@ -25,8 +26,9 @@ export default function IssuesPage() {
             </Suspense>
           </div>
         </div>
-        <div className="self-end md:self-auto">
+        <div className="self-end md:self-auto flex items-center gap-2">
           <CreateIssueModal />
+          <LogoutButton />
         </div>
       </header>
       <main className="flex-1 overflow-hidden bg-slate-100 h-full">
@@ -0,0 +1,14 @@
+import { LoginForm } from '@/components/login-form';
+
+export default async function LoginPage({
+  searchParams,
+}: {
+  searchParams: Promise<{ next?: string }>;
+}) {
+  const { next } = await searchParams;
+  return (
+    <main className="min-h-screen flex items-center justify-center bg-slate-100 p-4">
+      <LoginForm next={next} />
+    </main>
+  );
+}
@@ -31,7 +31,7 @@ import {
 } from '@/components/ui/command';
 import { Popover, PopoverContent, PopoverTrigger } from '@/components/ui/popover';
 import { cn } from '@/lib/utils';
-import { createIssue, getUsers, getLabels } from '@/lib/api';
+import { createIssue, getUsers, getLabels, problemFieldErrors } from '@/lib/api';
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
 import { Plus, Check, ChevronsUpDown, Loader2 } from 'lucide-react';
 import { useState, useEffect } from 'react';
@@ -85,6 +85,7 @@ export function CreateIssueModal({
     handleSubmit,
     control,
     reset,
+    setError,
     formState: { errors, isSubmitting },
   } = form;
 
@@ -98,8 +99,13 @@ export function CreateIssueModal({
       }, 300);
       toast.success('Issue created successfully');
     },
-    onError: () => {
-      toast.error('Failed to create issue');
+    onError: (error) => {
+      // Show the API's field errors next to their inputs
+      Object.entries(problemFieldErrors(error)).forEach(([field, message]) => {
+        if (field in createIssueSchema.shape) {
+          setError(field as keyof CreateIssueFormData, { type: 'server', message });
+        }
+      });
     },
   });
 
@@ -303,6 +309,9 @@ export function CreateIssueModal({
                 </Select>
               )}
             />
+            {errors.assignee_id && (
+              <p className="text-xs text-red-500 mt-1">{errors.assignee_id.message}</p>
+            )}
           </div>
 
           {/* Labels */}
@@ -413,6 +422,9 @@ export function CreateIssueModal({
                 </>
               )}
             />
+            {errors.label_ids && (
+              <p className="text-xs text-red-500 mt-1">{errors.label_ids.message}</p>
+            )}
           </div>
 
           {/* Submit Button */}
@@ -0,0 +1,90 @@
+'use client';
+
+import { Button } from '@/components/ui/button';
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card';
+import { Input } from '@/components/ui/input';
+import { Label } from '@/components/ui/label';
+import { Separator } from '@/components/ui/separator';
+import { login, OIDC_LOGIN_URL } from '@/lib/api';
+import { loginSchema, type LoginFormData } from '@/lib/schemas';
+import { zodResolver } from '@hookform/resolvers/zod';
+import { Loader2 } from 'lucide-react';
+import { useRouter } from 'next/navigation';
+import { useForm } from 'react-hook-form';
+
+interface LoginFormProps {
+  next?: string;
+}
+
+// Only same-site paths are followed after logging in
+function safeNext(next?: string) {
+  return next?.startsWith('/') && !next.startsWith('//') ? next : '/issues';
+}
+
+export function LoginForm({ next }: LoginFormProps) {
+  const router = useRouter();
+  const {
+    register,
+    handleSubmit,
+    formState: { errors, isSubmitting },
+  } = useForm<LoginFormData>({
+    resolver: zodResolver(loginSchema),
+    defaultValues: { email: '', password: '' },
+  });
+
+  const onSubmit = async (data: LoginFormData) => {
+    try {
+      await login(data.email, data.password);
+      router.replace(safeNext(next));
+    } catch {
+      // The API error is shown by the response interceptor
+    }
+  };
+
+  return (
+    <Card className="w-full max-w-sm">
+      <CardHeader>
+        <CardTitle>Sign in to Issue Board</CardTitle>
+        <CardDescription>
+          Use your account or your organization&apos;s single sign-on.
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="grid gap-4">
+        <form onSubmit={handleSubmit(onSubmit)} className="grid gap-4">
+          <div className="grid gap-2">
+            <Label htmlFor="email">Email</Label>
+            <Input
+              id="email"
+              type="email"
+              autoComplete="username"
+              aria-invalid={!!errors.email}
+              {...register('email')}
+            />
+            {errors.email && <p className="text-xs text-red-500 mt-1">{errors.email.message}</p>}
+          </div>
+          <div className="grid gap-2">
+            <Label htmlFor="password">Password</Label>
+            <Input
+              id="password"
+              type="password"
+              autoComplete="current-password"
+              aria-invalid={!!errors.password}
+              {...register('password')}
+            />
+            {errors.password && (
+              <p className="text-xs text-red-500 mt-1">{errors.password.message}</p>
+            )}
+          </div>
+          <Button type="submit" disabled={isSubmitting}>
+            {isSubmitting && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+            Sign in
+          </Button>
+        </form>
+        <Separator />
+        <Button variant="outline" asChild>
+          <a href={OIDC_LOGIN_URL}>Sign in with SSO</a>
+        </Button>
+      </CardContent>
+    </Card>
+  );
+}
@@ -0,0 +1,24 @@
+'use client';
+
+import { Button } from '@/components/ui/button';
+import { logout } from '@/lib/api';
+import { LogOut } from 'lucide-react';
+import { useRouter } from 'next/navigation';
+
+export function LogoutButton() {
+  const router = useRouter();
+
+  const handleLogout = async () => {
+    try {
+      await logout();
+    } finally {
+      router.replace('/login');
+    }
+  };
+
+  return (
+    <Button variant="ghost" size="icon" onClick={handleLogout} aria-label="Sign out">
+      <LogOut className="h-4 w-4" />
+    </Button>
+  );
+}
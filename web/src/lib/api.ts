@@ -1,15 +1,55 @@
-import axios from 'axios';
-import { CreateIssueRequest, Issue, Label, UpdateIssueRequest, User } from '@/types';
-import { issuesArraySchema, issueSchema, usersArraySchema, labelsArraySchema } from '@/lib/schemas';
+import axios, { AxiosError } from 'axios';
+import {
+  CreateIssueRequest,
+  Issue,
+  Label,
+  Problem,
+  SessionInfo,
+  UpdateIssueRequest,
+  User,
+} from '@/types';
+import {
+  issuesArraySchema,
+  issueSchema,
+  usersArraySchema,
+  labelsArraySchema,
+  sessionSchema,
+} from '@/lib/schemas';
 import { toast } from 'sonner';
 
+const API_PREFIX = '/api/proxy';
+
+// Starts a single sign-on login; the API redirects back to the board afterwards
+export const OIDC_LOGIN_URL = `${API_PREFIX}/auth/oidc/login`;
+
 const api = axios.create({
-  baseURL: '/api/proxy',
+  baseURL: API_PREFIX,
+  withCredentials: true,
   headers: {
     'Content-Type': 'application/json',
   },
 });
 
+// CSRF token of the current session, echoed on every write
+let csrfToken: string | null = null;
+
+const SAFE_METHODS = ['GET', 'HEAD', 'OPTIONS'];
+
+api.interceptors.request.use(async (config) => {
+  const method = (config.method ?? 'get').toUpperCase();
+  if (SAFE_METHODS.includes(method)) {
+    return config;
+  }
+  // After a page reload the token is only known to the API
+  if (!csrfToken && config.url !== '/auth/login') {
+    await getSession();
+  }
+  if (csrfToken) {
+    config.headers.set('X-CSRF-Token', csrfToken);
+  }
+  return config;
+});
+
 api.interceptors.response.use(
   (response) => response,
   (error) => {
@@ -18,6 +58,19 @@ api.interceptors.response.use(
       return Promise.reject(error);
     }
 
+    // Without a session, send the user to the login page
+    if (error.response?.status === 401 && !error.config?.url?.startsWith('/auth/')) {
+      csrfToken = null;
+      if (typeof window !== 'undefined' && window.location.pathname !== '/login') {
+        const next = encodeURIComponent(window.location.pathname + window.location.search);
+        window.location.assign(`/login?next=${next}`);
+      }
+      return Promise.reject(error);
+    }
+    if (error.config?.url === '/auth/session') {
+      return Promise.reject(error);
+    }
+
     // Log error details for debugging
     if (process.env.NODE_ENV === 'development') {
       console.error('API Error:', {
@@ -27,25 +80,39 @@ api.interceptors.response.use(
       });
     }
 
-    const payload = error.response?.data;
-    let message = error.message || 'An error occurred';
-
-    if (payload) {
-      if (typeof payload === 'string') {
-        message = payload;
-      } else if (typeof payload === 'object') {
-        const maybeMessage =
-          (payload as { message?: string; error?: string }).message ||
-          (payload as { message?: string; error?: string }).error;
-        message = maybeMessage || JSON.stringify(payload);
-      }
-    }
-
-    toast.error(message);
+    toast.error(problemMessage(error));
     return Promise.reject(error);
   }
 );
 
+// problemMessage summarises a failed request for a toast with the problem's
+// detail or title. Field errors are left to the form, see problemFieldErrors.
+export const problemMessage = (error: unknown) => {
+  const payload = (error as AxiosError<Problem | string>).response?.data;
+  if (typeof payload === 'string' && payload) {
+    return payload;
+  }
+  if (payload && typeof payload === 'object' && (payload.detail || payload.title)) {
+    return payload.detail || payload.title;
+  }
+  return (error as Error).message || 'An error occurred';
+};
+
+// problemFieldErrors returns the messages of a failed request's field errors
+// by field, e.g. to show them next to the form inputs
+export const problemFieldErrors = (error: unknown) => {
+  const payload = (error as AxiosError<Problem>).response?.data;
+  const fields: Record<string, string> = {};
+  if (payload && typeof payload === 'object') {
+    payload.errors?.forEach((e) => {
+      if (e.field && !fields[e.field]) {
+        fields[e.field] = e.message;
+      }
+    });
+  }
+  return fields;
+};
+
 export const getIssues = async (filters?: {
   status?: string[];
   assignee?: string;
@@ -138,3 +205,36 @@ export const getLabels = async () => {
   }
   return validated.data;
 };
+
+export const login = async (email: string, password: string) => {
+  const { data } = await api.post<SessionInfo>('/auth/login', { email, password });
+  const validated = sessionSchema.safeParse(data);
+  if (!validated.success) {
+    console.error('Invalid session response:', validated.error);
+    throw new Error('Invalid data received from server');
+  }
+  csrfToken = validated.data.csrf_token;
+  return validated.data;
+};
+
+// getSession returns the current session, or null when not logged in
+export const getSession = async () => {
+  try {
+    const { data } = await api.get<SessionInfo>('/auth/session');
+    const validated = sessionSchema.safeParse(data);
+    if (!validated.success) {
+      console.error('Invalid session response:', validated.error);
+      return null;
+    }
+    csrfToken = validated.data.csrf_token;
+    return validated.data;
+  } catch {
+    csrfToken = null;
+    return null;
+  }
+};
+
+export const logout = async () => {
+  await api.post('/auth/logout');
+  csrfToken = null;
+};
@@ -16,6 +16,13 @@ export const createIssueSchema = z.object({
 
 export type CreateIssueFormData = z.infer<typeof createIssueSchema>;
 
+export const loginSchema = z.object({
+  email: z.string().min(1, 'Email is required'),
+  password: z.string().min(1, 'Password is required'),
+});
+
+export type LoginFormData = z.infer<typeof loginSchema>;
+
 // API response validation schemas
 export const labelSchema = z.object({
   id: z.string(),
@@ -44,6 +51,12 @@ export const issueSchema = z.object({
   updated_at: z.string(),
 });
 
+export const sessionSchema = z.object({
+  user: userSchema,
+  csrf_token: z.string(),
+  expires_at: z.string(),
+});
+
 export const issuesArraySchema = z.array(issueSchema);
 export const usersArraySchema = z.array(userSchema);
 export const labelsArraySchema = z.array(labelSchema);
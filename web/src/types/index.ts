@@ -37,3 +37,20 @@ export type CreateIssueRequest = {
 };
 
 export type UpdateIssueRequest = Partial<CreateIssueRequest>;
+
+export type SessionInfo = {
+  user: User;
+  csrf_token: string;
+  expires_at: string;
+};
+
+// Problem is an RFC 7807 error response of the API
+export type Problem = {
+  type: string;
+  title: string;
+  status: number;
+  code: string;
+  detail?: string;
+  request_id?: string;
+  errors?: { field: string; code: string; message: string }[];
+};